@@ -0,0 +1,168 @@
+// Package classification classifica as rubricas dos contracheques
+// (detalhamento_contracheque) em categorias, indo além dos campos fixos do
+// resumo de rubricas.
+//
+// As regras ficam no dicionário dicionario.json, embutido no binário. Cada
+// regra associa uma expressão regular e/ou palavras-chave a uma categoria, e as
+// regras são avaliadas em ordem. Para mudar uma classificação, basta editar o
+// dicionário e a sua versão.
+package classification
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//go:embed dicionario.json
+var dictionaryJSON []byte
+
+// Dictionary é o dicionário de classificação das rubricas.
+type Dictionary struct {
+	Version           string            `json:"versao"`
+	Description       string            `json:"descricao"`
+	DefaultCategories map[string]string `json:"categoria_padrao"` // categoria usada quando nenhuma regra casa, por categoria do contracheque
+	Categories        []Category        `json:"categorias"`
+	Rules             []Rule            `json:"regras"`
+	categories        map[string]Category
+}
+
+type Category struct {
+	ID             string `json:"id"`
+	Description    string `json:"descricao"`
+	OutsideCeiling bool   `json:"fora_do_teto,omitempty"` // a categoria não entra na comparação com o teto constitucional
+}
+
+// Rule associa rubricas a uma categoria. A regra casa se a rubrica casar com
+// a expressão regular ou contiver alguma das palavras-chave.
+type Rule struct {
+	Category          string   `json:"categoria"`
+	PayslipCategories []string `json:"categorias_contracheque,omitempty"` // se vazio, vale para todas
+	Regex             string   `json:"regex,omitempty"`
+	Keywords          []string `json:"palavras,omitempty"`
+	re                *regexp.Regexp
+	keywords          []string // palavras-chave normalizadas, usadas na comparação
+}
+
+var defaultDictionary = mustParse(dictionaryJSON)
+
+// Default retorna o dicionário embutido no binário. O dicionário é
+// compartilhado e não deve ser alterado.
+func Default() *Dictionary {
+	return defaultDictionary
+}
+
+func mustParse(b []byte) *Dictionary {
+	d, err := Parse(b)
+	if err != nil {
+		// O dicionário é embutido no binário, então um erro aqui é um erro de programação.
+		panic(err)
+	}
+	return d
+}
+
+// Parse lê e valida um dicionário.
+func Parse(b []byte) (*Dictionary, error) {
+	var d Dictionary
+	if err := json.Unmarshal(b, &d); err != nil {
+		return nil, fmt.Errorf("error parsing dictionary: %w", err)
+	}
+	categories := make(map[string]Category)
+	for _, c := range d.Categories {
+		categories[c.ID] = c
+	}
+	d.categories = categories
+	for _, c := range d.DefaultCategories {
+		if _, ok := categories[c]; !ok {
+			return nil, fmt.Errorf("unknown default category: %s", c)
+		}
+	}
+	for i := range d.Rules {
+		r := &d.Rules[i]
+		if _, ok := categories[r.Category]; !ok {
+			return nil, fmt.Errorf("unknown category in rule %d: %s", i, r.Category)
+		}
+		if r.Regex == "" && len(r.Keywords) == 0 {
+			return nil, fmt.Errorf("rule %d (%s) has no regex or keywords", i, r.Category)
+		}
+		if r.Regex != "" {
+			re, err := regexp.Compile(r.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex in rule %d (%s): %w", i, r.Category, err)
+			}
+			r.re = re
+		}
+		// As palavras-chave são mantidas como escritas no dicionário, que é
+		// servido pela API; a comparação usa a forma normalizada.
+		r.keywords = make([]string, len(r.Keywords))
+		for j, k := range r.Keywords {
+			r.keywords[j] = normalize(k)
+		}
+	}
+	return &d, nil
+}
+
+// Classify retorna a categoria da rubrica item, da categoria do contracheque
+// payslipCategory (base, outras ou descontos).
+func (d *Dictionary) Classify(payslipCategory, item string) string {
+	text := normalize(item)
+	for _, r := range d.Rules {
+		if r.matches(payslipCategory, text) {
+			return r.Category
+		}
+	}
+	if c, ok := d.DefaultCategories[payslipCategory]; ok {
+		return c
+	}
+	return d.DefaultCategories["outras"]
+}
+
+// SubjectToCeiling diz se a rubrica entra na comparação com o teto
+// constitucional: rubricas de remuneração (base ou outras) cuja categoria não
+// está marcada como fora do teto.
+func (d *Dictionary) SubjectToCeiling(payslipCategory, item string) bool {
+	if payslipCategory != "base" && payslipCategory != "outras" {
+		return false
+	}
+	return !d.categories[d.Classify(payslipCategory, item)].OutsideCeiling
+}
+
+func (r Rule) matches(payslipCategory, text string) bool {
+	if len(r.PayslipCategories) > 0 {
+		found := false
+		for _, c := range r.PayslipCategories {
+			if c == payslipCategory {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.re != nil && r.re.MatchString(text) {
+		return true
+	}
+	for _, k := range r.keywords {
+		if strings.Contains(text, k) {
+			return true
+		}
+	}
+	return false
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "º", "o", "ª", "a",
+)
+
+// normalize deixa o texto em minúsculas, sem acentos e sem espaços repetidos.
+func normalize(s string) string {
+	return strings.Join(strings.Fields(accents.Replace(strings.ToLower(s))), " ")
+}
@@ -0,0 +1,70 @@
+package classification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	d := Default()
+	assert.NotEmpty(t, d.Version)
+
+	for _, c := range []struct {
+		payslipCategory string
+		item            string
+		expected        string
+	}{
+		{"base", "Subsídio", "subsidio"},
+		{"outras", "AUXÍLIO-SAÚDE", "auxilio_saude"},
+		{"outras", "Auxílio Alimentação", "auxilio_alimentacao"},
+		{"outras", "Indenização de férias não gozadas", "indenizacao_de_ferias"},
+		{"outras", "1/3 de férias", "ferias"},
+		{"outras", "Gratificação Natalina", "gratificacao_natalina"},
+		{"outras", "Licença-prêmio indenizada", "licenca_premio"},
+		{"outras", "Gratificação por acumulação de acervo", "gratificacao_acumulacao"},
+		{"outras", "Diferença de subsídio - exercícios anteriores", "retroativos"},
+		{"outras", "Verba sem regra", "outras"},
+		{"descontos", "Imposto de Renda", "imposto_de_renda"},
+		{"descontos", "IRRF", "imposto_de_renda"},
+		{"descontos", "Contribuição Previdenciária", "previdencia"},
+		{"descontos", "Retenção por teto constitucional", "abate_teto"},
+		{"descontos", "Pensão alimentícia", "outros_descontos"},
+		// As regras de descontos não valem para remunerações.
+		{"outras", "Abate teto", "outras"},
+	} {
+		assert.Equal(t, c.expected, d.Classify(c.payslipCategory, c.item), c.item)
+	}
+}
+
+func TestSubjectToCeiling(t *testing.T) {
+	d := Default()
+	assert.True(t, d.SubjectToCeiling("base", "Subsídio"))
+	assert.True(t, d.SubjectToCeiling("outras", "Gratificação por acumulação de acervo"))
+	assert.True(t, d.SubjectToCeiling("outras", "Verba sem regra"))
+	assert.False(t, d.SubjectToCeiling("outras", "Auxílio Alimentação"))
+	assert.False(t, d.SubjectToCeiling("outras", "Licença-prêmio indenizada"))
+	assert.False(t, d.SubjectToCeiling("outras", "Diferença de subsídio - exercícios anteriores"))
+	assert.False(t, d.SubjectToCeiling("descontos", "Imposto de Renda"))
+}
+
+func TestParse(t *testing.T) {
+	d, err := Parse([]byte(`{
+		"versao": "teste",
+		"categoria_padrao": {"outras": "outras"},
+		"categorias": [{"id": "outras"}, {"id": "auxilio_saude"}],
+		"regras": [{"categoria": "auxilio_saude", "palavras": ["Saúde"]}]
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "auxilio_saude", d.Classify("base", "auxilio saude"))
+	// As palavras-chave são mantidas como escritas no dicionário.
+	assert.Equal(t, []string{"Saúde"}, d.Rules[0].Keywords)
+	assert.Equal(t, "outras", d.Classify("descontos", "qualquer"))
+
+	_, err = Parse([]byte(`{"categorias": [{"id": "outras"}], "regras": [{"categoria": "moradia", "palavras": ["moradia"]}]}`))
+	assert.Error(t, err)
+	_, err = Parse([]byte(`{"categorias": [{"id": "outras"}], "regras": [{"categoria": "outras", "regex": "("}]}`))
+	assert.Error(t, err)
+	_, err = Parse([]byte(`{"categorias": [{"id": "outras"}], "regras": [{"categoria": "outras"}]}`))
+	assert.Error(t, err)
+}
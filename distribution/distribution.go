@@ -0,0 +1,85 @@
+// Package distribution calcula estatísticas de distribuição das remunerações
+// (mediana, percentis e coeficiente de Gini), usadas nas análises de
+// desigualdade. Os valores de entrada vêm dos zips de remunerações, em que cada
+// par membro/mês é uma observação.
+package distribution
+
+import (
+	"math"
+	"sort"
+)
+
+// Stats - estatísticas de distribuição de uma série de valores.
+type Stats struct {
+	Median float64
+	P10    float64
+	P25    float64
+	P75    float64
+	P90    float64
+	P99    float64
+	Gini   float64
+}
+
+// Distribution - distribuição da remuneração bruta e líquida dos membros de um
+// órgão em um mês ou ano.
+type Distribution struct {
+	Members int // quantidade de observações (pares membro/mês)
+	Gross   Stats
+	Net     Stats
+}
+
+// New calcula a distribuição a partir das remunerações bruta e líquida de cada
+// observação. As duas séries devem ter o mesmo tamanho.
+func New(gross, net []float64) Distribution {
+	return Distribution{Members: len(gross), Gross: NewStats(gross), Net: NewStats(net)}
+}
+
+// NewStats calcula as estatísticas dos valores. Uma série vazia resulta em
+// estatísticas zeradas.
+func NewStats(values []float64) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return Stats{
+		Median: Percentile(sorted, 50),
+		P10:    Percentile(sorted, 10),
+		P25:    Percentile(sorted, 25),
+		P75:    Percentile(sorted, 75),
+		P90:    Percentile(sorted, 90),
+		P99:    Percentile(sorted, 99),
+		Gini:   Gini(sorted),
+	}
+}
+
+// Percentile retorna o percentil p (0 a 100) dos valores, que devem estar
+// ordenados, interpolando linearmente entre as duas posições mais próximas.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+// Gini retorna o coeficiente de Gini dos valores, que devem estar ordenados:
+// 0 quando todos recebem o mesmo valor e próximo de 1 quando um único membro
+// concentra todo o total. Valores negativos (remunerações líquidas negativas,
+// por exemplo) são considerados zero.
+func Gini(sorted []float64) float64 {
+	n := float64(len(sorted))
+	var total, weighted float64
+	for i, v := range sorted {
+		v = math.Max(v, 0)
+		total += v
+		weighted += float64(i+1) * v
+	}
+	if total == 0 {
+		return 0
+	}
+	return 2*weighted/(n*total) - (n+1)/n
+}
@@ -0,0 +1,39 @@
+package distribution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStats(t *testing.T) {
+	values := []float64{50, 10, 40, 20, 30}
+	s := NewStats(values)
+	assert.Equal(t, 30.0, s.Median)
+	assert.InDelta(t, 14, s.P10, 1e-9)
+	assert.InDelta(t, 20, s.P25, 1e-9)
+	assert.InDelta(t, 40, s.P75, 1e-9)
+	assert.InDelta(t, 46, s.P90, 1e-9)
+	assert.InDelta(t, 49.6, s.P99, 1e-9)
+	// Os valores de entrada não são reordenados.
+	assert.Equal(t, []float64{50, 10, 40, 20, 30}, values)
+
+	assert.Equal(t, Stats{}, NewStats(nil))
+}
+
+func TestGini(t *testing.T) {
+	assert.Equal(t, 0.0, Gini([]float64{100, 100, 100}))
+	assert.InDelta(t, 0.75, Gini([]float64{0, 0, 0, 100}), 1e-9)
+	assert.InDelta(t, 0.25, Gini([]float64{1, 2, 3, 4}), 1e-9)
+	assert.InDelta(t, 2.0/3, Gini([]float64{-10, 0, 100}), 1e-9)
+	assert.Equal(t, 0.0, Gini([]float64{-10, 0}))
+	assert.Equal(t, 0.0, Gini(nil))
+}
+
+func TestNew(t *testing.T) {
+	d := New([]float64{10, 20, 30}, []float64{5, 10, 15})
+	assert.Equal(t, 3, d.Members)
+	assert.Equal(t, 20.0, d.Gross.Median)
+	assert.Equal(t, 10.0, d.Net.Median)
+	assert.InDelta(t, 2.0/9, d.Gross.Gini, 1e-9)
+}
@@ -35,6 +35,24 @@ const docTemplate = `{
                         "name": "orgao",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca. Os valores de cada ano são corrigidos pela média dos fatores dos meses com dados.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unidade dos valores: reais (padrão) ou salario_minimo. Os valores de cada ano são divididos pela média do salário mínimo em vigor nos meses com dados. Não pode ser combinada com corrigir, pois os múltiplos do salário mínimo já descontam a variação de preços. A unidade só está disponível aqui e nos totais do órgão no ano.",
+                        "name": "unidade",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -48,7 +66,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Parâmetro orgao inválido",
+                        "description": "Parâmetro orgao, corrigir, base ou unidade inválido",
                         "schema": {
                             "type": "string"
                         }
@@ -101,17 +119,28 @@ const docTemplate = `{
                         "description": "Categorias a serem pesquisadas",
                         "name": "categorias",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "datapackage",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Formato do arquivo. O padrão é csv; datapackage retorna um zip no formato Frictionless Data Package e ndjson retorna uma remuneração por linha (se a leitura falhar no meio, a última linha é um objeto {\\",
+                        "name": "formato",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Arquivo CSV com os dados.",
+                        "description": "Arquivo CSV, zip ou NDJSON com os dados.",
                         "schema": {
                             "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Erro de validação dos parâmetros.",
+                        "description": "Erro de validação dos parâmetros ou nenhum filtro informado.",
                         "schema": {
                             "type": "string"
                         }
@@ -142,6 +171,18 @@ const docTemplate = `{
                         "name": "ano",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -155,7 +196,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Parâmetro ano inválido.",
+                        "description": "Parâmetro ano, corrigir ou base inválido.",
                         "schema": {
                             "type": "string"
                         }
@@ -195,6 +236,255 @@ const docTemplate = `{
                 }
             }
         },
+        "/uiapi/v2/orgao/histograma/{orgao}": {
+            "get": {
+                "description": "Histograma das remunerações dos membros de um órgão em um período, calculado a partir dos zips de remunerações. Cada par membro/mês é uma observação. Informando os limites das faixas, é possível comparar órgãos e períodos diferentes na mesma escala.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ui_api"
+                ],
+                "operationId": "GetIncomeHistogram",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês inicial, no formato AAAA-MM. Exemplo: 2023-01.",
+                        "name": "inicio",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês final, no formato AAAA-MM. Exemplo: 2023-12.",
+                        "name": "fim",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quantidade de faixas de mesma largura entre o menor e o maior valor (padrão: 10, máximo: 100) ou os limites das faixas, crescentes e separados por vírgula. Exemplo: 0,10000,20000,40000,80000.",
+                        "name": "bins",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Valor usado no histograma: bruto (padrão), liquido ou base.",
+                        "name": "metrica",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores antes da distribuição nas faixas. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/uiapi.incomeHistogram"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Não existem dados para os parâmetros informados.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/uiapi/v2/orgao/maiores/{orgao}/{ano}/{mes}": {
+            "get": {
+                "description": "Busca os membros com as maiores remunerações brutas (base + outras) de um órgão em um mês.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ui_api"
+                ],
+                "operationId": "GetTopEarners",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano da remuneração. Exemplo: 2018.",
+                        "name": "ano",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Mês da remuneração. Exemplo: 1.",
+                        "name": "mes",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número de membros. O padrão é 50 e o máximo é 500.",
+                        "name": "n",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/uiapi.topEarners"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Não existem dados para os parâmetros informados.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/uiapi/v2/orgao/pagamentos-excepcionais/{orgao}": {
+            "get": {
+                "description": "Lista rubricas de outras remunerações muito acima do histórico do próprio membro, como pagamentos retroativos, indenizações de férias acumuladas e licenças-prêmio. Cada rubrica recebida em um mês do período é comparada com os demais meses do membro nos zips de remunerações: a razão é o valor da rubrica acima da sua mediana dividido pela mediana da remuneração bruta do membro. Membros com menos de três outros meses não são avaliados.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ui_api"
+                ],
+                "operationId": "GetExceptionalPayments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês inicial, no formato AAAA-MM. Exemplo: 2023-01. O período tem no máximo 12 meses.",
+                        "name": "inicio",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês final, no formato AAAA-MM. Exemplo: 2023-12.",
+                        "name": "fim",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Meses anteriores ao início incluídos no histórico dos membros, entre 0 e 12. Padrão: 12.",
+                        "name": "historico",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Razão mínima para a rubrica ser listada. Padrão: 1 (uma remuneração bruta mediana do membro).",
+                        "name": "razao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores antes da comparação. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/uiapi.exceptionalPayments"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Não existem dados para os parâmetros informados.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/uiapi/v2/orgao/resumo/{orgao}/{ano}/{mes}": {
             "get": {
                 "description": "Resume os dados de remuneração mensal de um órgão.",
@@ -329,6 +619,24 @@ const docTemplate = `{
                         "name": "ano",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unidade dos valores: reais (padrão) ou salario_minimo, em múltiplos do salário mínimo em vigor em cada mês. As médias por membro do ano são divididas pela média do salário mínimo nos meses com dados. Não pode ser combinada com corrigir, pois os múltiplos do salário mínimo já descontam a variação de preços. A unidade só está disponível aqui e no resumo anual do órgão.",
+                        "name": "unidade",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -339,7 +647,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Parâmetro ano ou orgao inválido.",
+                        "description": "Parâmetro ano, orgao, corrigir, base ou unidade inválido.",
                         "schema": {
                             "type": "string"
                         }
@@ -463,6 +771,16 @@ const docTemplate = `{
                         "description": "Categorias a serem pesquisadas",
                         "name": "categorias",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Formato da resposta. Com ndjson, cada remuneração é enviada em uma linha à medida que os dados são lidos; se a leitura falhar no meio, a última linha é um objeto {\\",
+                        "name": "formato",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -487,34 +805,72 @@ const docTemplate = `{
                 }
             }
         },
-        "/v2/dados/{orgao}": {
+        "/uiapi/v2/rubricas/categorias/{orgao}/{ano}/{mes}": {
             "get": {
-                "description": "Busca todas as informações de um órgão específico.",
+                "description": "Soma as rubricas (detalhamento_contracheque) de um órgão em um mês pelas categorias do dicionário de classificação, disponível em /v2/rubricas/dicionario.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "public_api"
+                    "ui_api"
                 ],
-                "operationId": "GetAllAgencyInformation",
+                "operationId": "GetItemCategories",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "órgão",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
                         "name": "orgao",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Requisição bem sucedida.",
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano da remuneração. Exemplo: 2018.",
+                        "name": "ano",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Mês da remuneração. Exemplo: 1.",
+                        "name": "mes",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
                         "schema": {
-                            "$ref": "#/definitions/papi.allAgencyInformation"
+                            "$ref": "#/definitions/uiapi.itemCategories"
                         }
                     },
                     "400": {
-                        "description": "Requisição inválida.",
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Não existem dados para os parâmetros informados.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
                         "schema": {
                             "type": "string"
                         }
@@ -522,50 +878,79 @@ const docTemplate = `{
                 }
             }
         },
-        "/v2/dados/{orgao}/{ano}": {
+        "/uiapi/v2/teto/{param}/{valor}": {
             "get": {
-                "description": "Busca os dados mensais de um órgão por ano",
+                "description": "Relatório de membros que receberam remuneração mensal acima do teto constitucional, com o valor pago acima do teto e as rubricas recebidas por esses membros. A remuneração comparada com o teto exclui as rubricas que o teto não alcança, segundo o dicionário de classificação (/v2/rubricas/dicionario): verbas indenizatórias, gratificação natalina, adicional de férias e retroativos (categorias_fora_do_teto). Rubricas que o dicionário não reconhece são consideradas sujeitas ao teto.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "public_api"
+                    "ui_api"
                 ],
-                "operationId": "GetMonthlyInfosByYear",
+                "operationId": "GetCeilingReport",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Ano",
-                        "name": "ano",
+                        "type": "string",
+                        "description": "'grupo' ou 'orgao'",
+                        "name": "param",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Órgão",
-                        "name": "orgao",
+                        "description": "Jurisdição ou ID do órgão. Exemplos: justica-estadual, tjal.",
+                        "name": "valor",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês inicial, no formato AAAA-MM. Exemplo: 2023-01.",
+                        "name": "inicio",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês final, no formato AAAA-MM. Exemplo: 2023-12.",
+                        "name": "fim",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca. A comparação com o teto é sempre feita com os valores nominais.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Requisição bem sucedida",
+                        "description": "Requisição bem sucedida.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/papi.summaryzedMI"
-                            }
+                            "$ref": "#/definitions/uiapi.ceilingReport"
                         }
                     },
                     "400": {
-                        "description": "Parâmetros inválidos",
+                        "description": "Parâmetros inválidos.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Não existem dados para os parâmetros informados",
+                        "description": "Grupo não encontrado, grupo sem órgãos ou não existem dados para os parâmetros informados.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
                         "schema": {
                             "type": "string"
                         }
@@ -573,54 +958,89 @@ const docTemplate = `{
                 }
             }
         },
-        "/v2/dados/{orgao}/{ano}/{mes}": {
+        "/v2/anomalias": {
             "get": {
-                "description": "Busca um dado mensal de um órgão",
+                "description": "Lista os órgãos/meses cuja remuneração bruta total, remuneração bruta por membro ou rubricas do resumo de rubricas se desviam fortemente da linha de base do próprio órgão. A linha de base é a mediana dos meses anteriores (janela móvel) ou, nos meses com picos sazonais esperados, como a gratificação natalina em dezembro, a mediana do mesmo mês nos anos anteriores. A pontuação é um escore z robusto, baseado no desvio absoluto mediano. Anomalias na remuneração bruta indicam a rubrica que mais contribuiu para o desvio.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "public_api"
                 ],
-                "operationId": "GetMonthlyInfo",
+                "operationId": "GetAnomalies",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Ano",
-                        "name": "ano",
-                        "in": "path",
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb. Informe orgao ou grupo.",
+                        "name": "orgao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos.",
+                        "name": "grupo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês inicial, no formato AAAA-MM. Exemplo: 2023-01. O período tem no máximo 120 meses.",
+                        "name": "inicio",
+                        "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Órgão",
-                        "name": "orgao",
-                        "in": "path",
+                        "description": "Mês final, no formato AAAA-MM. Exemplo: 2023-12.",
+                        "name": "fim",
+                        "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "number",
+                        "description": "Pontuação mínima, em módulo, para um valor ser considerado anômalo. Padrão: 3.5.",
+                        "name": "limiar",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Mês",
-                        "name": "mes",
-                        "in": "path",
-                        "required": true
+                        "description": "Quantidade de meses anteriores na linha de base móvel, entre 6 e 36. Padrão: 12.",
+                        "name": "janela",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores antes da comparação. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Requisição bem sucedida",
+                        "description": "Requisição bem sucedida.",
                         "schema": {
-                            "$ref": "#/definitions/papi.summaryzedMI"
+                            "$ref": "#/definitions/papi.anomalies"
                         }
                     },
                     "400": {
-                        "description": "Parâmetros inválidos",
+                        "description": "Parâmetros inválidos.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Não existem dados para os parâmetros informados",
+                        "description": "Órgão ou grupo não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
                         "schema": {
                             "type": "string"
                         }
@@ -628,24 +1048,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/v2/indice": {
+        "/v2/atualizacao": {
             "get": {
-                "description": "Busca as informações de índices de todos os órgãos.",
+                "description": "Lista os órgãos atrasados: aqueles cujo último mês com dados é anterior ao mês esperado (o mês atual menos o prazo de publicação). Para cada órgão, informa quantos meses de atraso, o último mês com dados, se houve erro na nossa coleta depois dele, os motivos registrados na última verificação da coleta e o link da ouvidoria. Órgãos que nunca tiveram dados aparecem primeiro, sem meses de atraso.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "public_api"
                 ],
-                "operationId": "GetAggregateIndexes",
+                "operationId": "GetFreshness",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos. Se omitido, todos os órgãos são incluídos.",
+                        "name": "grupo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Prazo de publicação, em meses, entre 0 e 24. Padrão: 2 (em outubro, espera-se que os dados de agosto já estejam publicados).",
+                        "name": "prazo",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Requisição bem sucedida.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/papi.aggregateIndexesByGroup"
-                            }
+                            "$ref": "#/definitions/papi.freshness"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Grupo não encontrado.",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -657,44 +1100,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/v2/indice/{param}/{valor}": {
+        "/v2/cobertura": {
             "get": {
-                "description": "Busca as informações de índices de um grupo ou órgão específico.",
+                "description": "Monta a matriz órgão × mês da situação das coletas no período. Cada célula é ok, coleta_manual, erro_coleta (com o status informado pelo coletor), indisponivel (status 4: dados indisponíveis ou malformados) ou nao_coletado. Inclui os totais de cada órgão, de cada mês e da matriz.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "public_api"
                 ],
-                "operationId": "GetAggregateIndexesWithParams",
+                "operationId": "GetCoverage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "'grupo' ou 'orgao'",
-                        "name": "param",
-                        "in": "path",
+                        "description": "Mês inicial, no formato AAAA-MM. O período tem no máximo 120 meses.",
+                        "name": "inicio",
+                        "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Jurisdição ou ID do órgao",
-                        "name": "valor",
-                        "in": "path",
+                        "description": "Mês final, no formato AAAA-MM.",
+                        "name": "fim",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos. Se omitido, todos os órgãos são incluídos.",
+                        "name": "grupo",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Requisição bem sucedida.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/papi.aggregateIndexes"
-                            }
+                            "$ref": "#/definitions/papi.coverage"
                         }
                     },
                     "400": {
-                        "description": "Requisição inválida.",
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Grupo não encontrado.",
                         "schema": {
                             "type": "string"
                         }
@@ -708,16 +1160,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v2/orgao/{orgao}": {
+        "/v2/coletores/{orgao}": {
             "get": {
-                "description": "Busca um órgão específico utilizando seu ID.",
+                "description": "Lista as versões do coletor e do parser usadas nas coletas de um órgão, na ordem em que passaram a ser usadas, com os períodos de meses coletados por cada versão, a quantidade de coletas, a taxa de erro, estatísticas da duração das coletas e o link do commit. Coletas com status 4 (dados indisponíveis ou malformados) não contam como erro.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "public_api"
                 ],
-                "operationId": "GetAgencyById",
+                "operationId": "GetCrawlerHistory",
                 "parameters": [
                     {
                         "type": "string",
@@ -731,7 +1183,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Requisição bem sucedida.",
                         "schema": {
-                            "$ref": "#/definitions/papi.agency"
+                            "$ref": "#/definitions/papi.crawlerHistory"
                         }
                     },
                     "404": {
@@ -739,28 +1191,78 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/v2/orgaos": {
+        "/v2/comparar": {
             "get": {
-                "description": "Busca todos os órgãos disponíveis.",
+                "description": "Retorna as séries mensais de vários órgãos alinhadas aos meses do período: totais de remuneração, remuneração bruta por membro, quantidade de membros, resumo de rubricas e índices de transparência. Os meses sem dados ou com erro na coleta ficam com valores nulos e são listados por órgão e em meses_incompletos.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "public_api"
                 ],
-                "operationId": "GetAllAgencies",
+                "operationId": "CompareAgencies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "IDs dos órgãos, separados por vírgula (no máximo 10). Exemplo: tjpb,tjpe,tjrn.",
+                        "name": "orgaos",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês inicial, no formato AAAA-MM. Exemplo: 2020-01. O período tem no máximo 120 meses.",
+                        "name": "inicio",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês final, no formato AAAA-MM. Exemplo: 2023-12.",
+                        "name": "fim",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Requisição bem sucedida.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/papi.agency"
-                            }
+                            "$ref": "#/definitions/papi.comparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Órgão não encontrado.",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -771,146 +1273,3124 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "papi.agency": {
-            "type": "object",
-            "properties": {
-                "coletando": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/papi.collecting"
-                    }
-                },
-                "entidade": {
-                    "description": "\"J\" For Judiciário, \"M\" for Ministério Público, \"P\" for Procuradorias and \"D\" for Defensorias.",
-                    "type": "string"
-                },
+        },
+        "/v2/crescimento/grupo/{grupo}": {
+            "get": {
+                "description": "Calcula a variação absoluta e percentual da remuneração bruta total, da remuneração bruta por membro e da quantidade de membros de um grupo de órgãos, mês a mês e ano a ano. As variações consideram apenas os órgãos com dados nos dois períodos comparados; os órgãos sem dados ou com erro na coleta são listados em cada mês. A variação anual de cada ano compara, em cada órgão, apenas os meses com dados nos dois anos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetGroupGrowth",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos.",
+                        "name": "grupo",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês inicial, no formato AAAA-MM. Exemplo: 2023-01. O período tem no máximo 120 meses.",
+                        "name": "inicio",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês final, no formato AAAA-MM. Exemplo: 2023-12.",
+                        "name": "fim",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores, para calcular o crescimento real. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.growth"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Grupo não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/crescimento/{orgao}": {
+            "get": {
+                "description": "Calcula a variação absoluta e percentual da remuneração bruta total, da remuneração bruta por membro e da quantidade de membros de um órgão, mês a mês e ano a ano. Meses sem dados ou com erro na coleta são sinalizados e não são comparados com outros meses; a variação mensal sempre compara com o mês imediatamente anterior. A variação anual de cada ano compara apenas os meses com dados nos dois anos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetAgencyGrowth",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês inicial, no formato AAAA-MM. Exemplo: 2023-01. O período tem no máximo 120 meses.",
+                        "name": "inicio",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês final, no formato AAAA-MM. Exemplo: 2023-12.",
+                        "name": "fim",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores, para calcular o crescimento real. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.growth"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Órgão não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/dados/{orgao}": {
+            "get": {
+                "description": "Busca todas as informações de um órgão específico.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetAllAgencyInformation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "órgão",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.allAgencyInformation"
+                        }
+                    },
+                    "400": {
+                        "description": "Requisição inválida.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/dados/{orgao}/{ano}": {
+            "get": {
+                "description": "Busca os dados mensais de um órgão por ano",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetMonthlyInfosByYear",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ano",
+                        "name": "ano",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Órgão",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/papi.summaryzedMI"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Não existem dados para os parâmetros informados",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/dados/{orgao}/{ano}/{mes}": {
+            "get": {
+                "description": "Busca um dado mensal de um órgão. Com distribuicao=true, o bloco distribuicao traz a mediana, os percentis e o coeficiente de Gini das remunerações bruta e líquida, calculados a partir dos dados de remuneração do mês. O cálculo lê o zip de remunerações, então a resposta é mais lenta.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetMonthlyInfo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ano",
+                        "name": "ano",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Órgão",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Mês",
+                        "name": "mes",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui a distribuição das remunerações do mês. O padrão é false.",
+                        "name": "distribuicao",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida",
+                        "schema": {
+                            "$ref": "#/definitions/papi.summaryzedMI"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Não existem dados para os parâmetros informados",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/distribuicao/{orgao}/{ano}": {
+            "get": {
+                "description": "Busca a distribuição das remunerações bruta e líquida de um órgão em um ano: mediana, percentis (p10, p25, p75, p90 e p99) e coeficiente de Gini. Cada par membro/mês com dados no ano é uma observação.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetAgencyYearDistribution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano",
+                        "name": "ano",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca. A remuneração de cada par membro/mês é corrigida antes do cálculo das estatísticas.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.agencyYearDistribution"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Não existem dados para os parâmetros informados.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/erros": {
+            "get": {
+                "description": "Agrupa as coletas com erro no período por órgão, status, repositório e versão do coletor e mensagem de erro normalizada (última linha da saída de erro, sem endereços e números), com a quantidade de ocorrências, a primeira e a última ocorrência e um exemplo da saída de erro. Os grupos vêm do mais frequente para o menos frequente. Inclui as coletas com status 4 (dados indisponíveis ou malformados).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetCrawlingErrors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
+                        "name": "orgao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos. Se orgao e grupo forem omitidos, todos os órgãos são incluídos.",
+                        "name": "grupo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês inicial, no formato AAAA-MM. O período tem no máximo 120 meses.",
+                        "name": "inicio",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês final, no formato AAAA-MM.",
+                        "name": "fim",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.crawlingErrors"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Órgão ou grupo não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/feed.atom": {
+            "get": {
+                "description": "Feed Atom com os órgãos/meses coletados ou atualizados recentemente, do mais recente para o mais antigo (até 50 entradas). Cada entrada traz o número de membros, a remuneração total e o índice de transparência do mês, e os links para a página do órgão no site e para o pacote de dados. Uma recoleta atualiza a entrada do mês (mesmo id, novo updated). As coletas são ordenadas pela data da coleta, qualquer que seja o mês a que se referem.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetFeed",
+                "responses": {
+                    "200": {
+                        "description": "Feed Atom.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Feed indisponível.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/grupo/{grupo}/{ano}": {
+            "get": {
+                "description": "Soma os totais mensais, o número de membros e o resumo de rubricas dos órgãos de um grupo em cada mês do ano, com os valores por membro, os totais do ano de cada órgão e a cobertura de cada mês: quantos órgãos têm dados, quais não têm e quais tiveram erro na coleta. Meses sem dados ou com erro na coleta ficam fora da soma.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetGroupYear",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Grupo de órgãos. Exemplos: justica-do-trabalho, ministerios-publicos.",
+                        "name": "grupo",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano",
+                        "name": "ano",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.aggregate"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Grupo não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/grupos": {
+            "get": {
+                "description": "Lista os grupos de órgãos (jurisdições), com o nome usado nas URLs, o nome de exibição e o número de órgãos de cada grupo, total e por entidade.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetGroups",
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/papi.groupInfo"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/indice": {
+            "get": {
+                "description": "Busca as informações de índices de todos os órgãos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetAggregateIndexes",
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/papi.aggregateIndexesByGroup"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/indice/{param}/{valor}": {
+            "get": {
+                "description": "Busca as informações de índices de um grupo ou órgão específico.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetAggregateIndexesWithParams",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "'grupo' ou 'orgao'",
+                        "name": "param",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Jurisdição ou ID do órgao",
+                        "name": "valor",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/papi.aggregateIndexes"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Requisição inválida.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/orgao/{orgao}": {
+            "get": {
+                "description": "Busca um órgão específico utilizando seu ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetAgencyById",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.agency"
+                        }
+                    },
+                    "404": {
+                        "description": "Órgão não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/orgao/{orgao}/feed.atom": {
+            "get": {
+                "description": "Feed Atom com os meses coletados ou atualizados recentemente de um órgão, do mais recente para o mais antigo (até 50 entradas). Cada entrada traz o número de membros, a remuneração total e o índice de transparência do mês, e os links para a página do órgão no site e para o pacote de dados. Uma recoleta atualiza a entrada do mês (mesmo id, novo updated).",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetAgencyFeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Feed Atom.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Órgão não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/orgaos": {
+            "get": {
+                "description": "Busca todos os órgãos disponíveis.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetAllAgencies",
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/papi.agency"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/ranking/remuneracao": {
+            "get": {
+                "description": "Ordena os órgãos por uma métrica do resumo anual, do maior para o menor valor, com a posição, o valor, o percentil e a variação de posição em relação ao ano anterior. Órgãos com menos de 12 meses de dados no ano são sinalizados e, por padrão, ficam sem posição no fim da lista, pois seus totais não são comparáveis aos dos demais.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetRemunerationRanking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ano. Exemplo: 2023.",
+                        "name": "ano",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Métrica do resumo anual. Padrão: remuneracoes_por_membro. Valores aceitos: descontos, descontos_por_membro, num_membros, outras_remuneracoes, outras_remuneracoes_por_membro, remuneracao_base, remuneracao_base_por_membro, remuneracoes, remuneracoes_por_membro.",
+                        "name": "metrica",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos. Se omitido, todos os órgãos são ordenados.",
+                        "name": "grupo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Se verdadeiro, os órgãos com ano incompleto também recebem posição. Padrão: falso.",
+                        "name": "incluir_incompletos",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.ranking"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Grupo não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/rubricas/catalogo": {
+            "get": {
+                "description": "Lista as rubricas (detalhamento_contracheque) encontradas nos zips de remunerações, com a categoria do dicionário de classificação, a quantidade de ocorrências, o valor total, o primeiro e o último mês em que aparecem e os órgãos que as usam. O catálogo é guardado no banco e atualizado em segundo plano por uma única instância da API, que só lê os zips de órgãos/meses novos ou alterados. Pode estar incompleto enquanto indexando for verdadeiro.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetItemCatalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Categoria do dicionário de classificação. Exemplo: auxilio_saude.",
+                        "name": "categoria",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjba, mppb.",
+                        "name": "orgao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Apenas rubricas vistas pela primeira vez a partir deste mês, no formato AAAA-MM. Útil para encontrar rubricas novas.",
+                        "name": "desde",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/uiapi.itemCatalogResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/rubricas/dicionario": {
+            "get": {
+                "description": "Retorna o dicionário versionado usado para classificar as rubricas (detalhamento_contracheque) em categorias.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetItemDictionary",
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/classification.Dictionary"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/rubricas/{orgao}": {
+            "get": {
+                "description": "Busca as séries mensais das rubricas (resumo_rubricas) de um órgão, com o total e o valor por membro de cada mês.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetItemSeries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do órgão. Exemplos: tjal, tjpb, mppb.",
+                        "name": "orgao",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês inicial, no formato AAAA-MM. Exemplo: 2018-01. O período tem no máximo 120 meses.",
+                        "name": "inicio",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês final, no formato AAAA-MM. Exemplo: 2023-12.",
+                        "name": "fim",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rubricas separadas por vírgula. O padrão são todas. Exemplo: auxilio_saude,licenca_premio.",
+                        "name": "rubricas",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Formato da resposta: json (padrão) ou csv.",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.itemSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Órgão não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/uf/{uf}/resumo": {
+            "get": {
+                "description": "Soma os resumos anuais de todos os órgãos de uma UF, ano a ano, com os totais de cada órgão e a cobertura: quantos órgãos têm dados no ano e quais têm menos de 12 meses de dados. O número de membros do ano é a soma das médias mensais dos órgãos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetStateSummary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sigla da UF. Exemplos: PB, SP.",
+                        "name": "uf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca. Os valores anuais de cada órgão são corrigidos pela média dos fatores dos seus meses com dados no ano.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.aggregateSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "UF não encontrada ou sem órgãos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/uf/{uf}/{ano}": {
+            "get": {
+                "description": "Soma os totais mensais, o número de membros e o resumo de rubricas de todos os órgãos de uma UF em cada mês do ano, com os totais do ano de cada órgão e a cobertura de cada mês: quantos órgãos têm dados, quais não têm e quais tiveram erro na coleta. Meses sem dados ou com erro na coleta ficam fora da soma.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetStateYear",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sigla da UF. Exemplos: PB, SP.",
+                        "name": "uf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano",
+                        "name": "ano",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Índice para correção monetária dos valores. Valor aceito: ipca.",
+                        "name": "corrigir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice.",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/papi.aggregate"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "UF não encontrada ou sem órgãos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/ufs": {
+            "get": {
+                "description": "Lista as unidades federativas, com a sigla, o nome usado nas URLs, o nome, a região, a bandeira e o número de órgãos de cada UF, total e por entidade.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetUFs",
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/papi.ufInfo"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/webhooks": {
+            "post": {
+                "description": "Cria uma assinatura de webhook. A URL informada recebe um POST a cada coleta nova (tipo nova_coleta) ou recoleta (tipo recoleta) de um órgão/mês, com os números do resumo e, nas recoletas, a variação em relação à coleta anterior. A assinatura pode ser restrita a um órgão ou a um grupo; sem nenhum dos dois, recebe os eventos de todos os órgãos. O corpo de cada POST é assinado com o segredo retornado aqui, que não é exibido de novo: o cabeçalho X-DadosJusBr-Signature traz sha256=\u003cHMAC-SHA256 do corpo, em hexadecimal\u003e. Respostas 2xx confirmam a entrega; erros de rede, 429 e 5xx são tentados de novo nas rodadas seguintes, com espera crescente entre as tentativas. O id da assinatura é necessário para consultá-la, testá-la e apagá-la. Antes de criar a assinatura, a URL recebe um evento do tipo verificacao com o campo desafio e deve responder com status 2xx trazendo o desafio no corpo; URLs que apontam para endereços internos não são aceitas. As entregas não seguem redirecionamentos. A criação é limitada a algumas requisições por hora por IP.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "CreateWebhook",
+                "parameters": [
+                    {
+                        "description": "URL e filtro da assinatura.",
+                        "name": "assinatura",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/papi.webhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Assinatura criada.",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos ou URL que não respondeu à verificação.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Órgão ou grupo não encontrado.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Webhooks indisponíveis.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/webhooks/{id}": {
+            "get": {
+                "description": "Busca uma assinatura de webhook. O segredo não é retornado.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetWebhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura, retornado na criação.",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Subscription"
+                        }
+                    },
+                    "404": {
+                        "description": "Assinatura não encontrada.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Webhooks indisponíveis.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Apaga uma assinatura de webhook e o seu registro de entregas.",
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "DeleteWebhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura, retornado na criação.",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Assinatura apagada.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Assinatura não encontrada.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Webhooks indisponíveis.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/webhooks/{id}/entregas": {
+            "get": {
+                "description": "Lista as últimas tentativas de entrega de uma assinatura de webhook, da mais recente para a mais antiga.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "GetWebhookDeliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura, retornado na criação.",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número de entregas, entre 1 e 500. Padrão: 50.",
+                        "name": "limite",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhook.Delivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Assinatura não encontrada.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Webhooks indisponíveis.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/webhooks/{id}/teste": {
+            "post": {
+                "description": "Envia um evento de teste (tipo teste) para a URL da assinatura, uma única vez, e retorna o resultado da entrega. Só URLs verificadas na criação da assinatura recebem o teste, e o envio é limitado a algumas requisições por hora por IP.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public_api"
+                ],
+                "operationId": "PingWebhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura, retornado na criação.",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requisição bem sucedida. O campo entregue indica se a URL respondeu com 2xx.",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Delivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Assinatura não encontrada.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Webhooks indisponíveis.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "classification.Category": {
+            "type": "object",
+            "properties": {
+                "descricao": {
+                    "type": "string"
+                },
+                "fora_do_teto": {
+                    "description": "a categoria não entra na comparação com o teto constitucional",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "classification.Dictionary": {
+            "type": "object",
+            "properties": {
+                "categoria_padrao": {
+                    "description": "categoria usada quando nenhuma regra casa, por categoria do contracheque",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "categorias": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/classification.Category"
+                    }
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "regras": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/classification.Rule"
+                    }
+                },
+                "versao": {
+                    "type": "string"
+                }
+            }
+        },
+        "classification.Rule": {
+            "type": "object",
+            "properties": {
+                "categoria": {
+                    "type": "string"
+                },
+                "categorias_contracheque": {
+                    "description": "se vazio, vale para todas",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "palavras": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "regex": {
+                    "type": "string"
+                }
+            }
+        },
+        "papi.agency": {
+            "type": "object",
+            "properties": {
+                "coletando": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.collecting"
+                    }
+                },
+                "entidade": {
+                    "description": "Kind of institution, e.g. \"Tribunal\", \"Ministério\". See taxonomy.Entities.",
+                    "type": "string"
+                },
+                "id_orgao": {
+                    "description": "'trt13'",
+                    "type": "string"
+                },
+                "jurisdicao": {
+                    "description": "Jurisdiction of the agency's group, e.g. \"Estadual\", \"Trabalho\". See taxonomy.Groups.",
+                    "type": "string"
+                },
+                "nome": {
+                    "description": "'Tribunal Regional do Trabalho 13° Região'",
+                    "type": "string"
+                },
+                "ouvidoria": {
+                    "description": "Agencys's ombudsman url",
+                    "type": "string"
+                },
+                "possui_dados": {
+                    "description": "If there is data from that agency",
+                    "type": "boolean"
+                },
+                "twitter_handle": {
+                    "description": "Agency's twitter handle",
+                    "type": "string"
+                },
+                "uf": {
+                    "description": "Short code for federative unity.",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Link for state url",
+                    "type": "string"
+                }
+            }
+        },
+        "papi.agencyComparison": {
+            "type": "object",
+            "properties": {
+                "descontos": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "indice_transparencia": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.score"
+                    }
+                },
+                "meses_sem_dados": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "num_membros": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "orgao": {
+                    "type": "string"
+                },
+                "outras_remuneracoes": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "remuneracao_base": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "remuneracao_bruta": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "remuneracao_bruta_por_membro": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "resumo_rubricas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.itemSeriesValues"
+                    }
+                },
+                "situacao": {
+                    "description": "com_dados, sem_dados ou erro_coleta, para cada mês",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "papi.agencyCoverage": {
+            "type": "object",
+            "properties": {
+                "id_orgao": {
+                    "type": "string"
+                },
+                "meses": {
+                    "description": "alinhadas aos meses",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.coverageCell"
+                    }
+                },
+                "totais": {
+                    "$ref": "#/definitions/papi.coverageTotals"
+                }
+            }
+        },
+        "papi.agencyYearDistribution": {
+            "type": "object",
+            "properties": {
+                "ano": {
+                    "type": "integer"
+                },
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "distribuicao": {
+                    "$ref": "#/definitions/papi.remunerationDistribution"
+                },
+                "id_orgao": {
+                    "type": "string"
+                }
+            }
+        },
+        "papi.aggregate": {
+            "type": "object",
+            "properties": {
+                "ano": {
+                    "type": "integer"
+                },
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "grupo": {
+                    "type": "string"
+                },
+                "meses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateMonth"
+                    }
+                },
+                "orgaos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "por_orgao": {
+                    "description": "totais do ano de cada órgão",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateAgency"
+                    }
+                },
+                "uf": {
+                    "type": "string"
+                }
+            }
+        },
+        "papi.aggregateAgency": {
+            "type": "object",
+            "properties": {
+                "id_orgao": {
+                    "type": "string"
+                },
+                "meses_com_dados": {
+                    "type": "integer"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "totais": {
+                    "$ref": "#/definitions/papi.aggregateTotals"
+                }
+            }
+        },
+        "papi.aggregateAnnual": {
+            "type": "object",
+            "properties": {
+                "ano": {
+                    "type": "integer"
+                },
+                "cobertura": {
+                    "$ref": "#/definitions/papi.yearCoverage"
+                },
+                "por_orgao": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateAgency"
+                    }
+                },
+                "totais": {
+                    "$ref": "#/definitions/papi.aggregateTotals"
+                }
+            }
+        },
+        "papi.aggregateIndexes": {
+            "type": "object",
+            "properties": {
+                "agregado": {
+                    "description": "Aggregate indices",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/papi.score"
+                        }
+                    ]
+                },
+                "detalhe": {
+                    "description": "All agency indices",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.indexInformation"
+                    }
+                },
+                "id_orgao": {
+                    "description": "e.g. 'trt13'",
+                    "type": "string"
+                }
+            }
+        },
+        "papi.aggregateIndexesByGroup": {
+            "type": "object",
+            "properties": {
+                "conselhos-de-justica": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateIndexes"
+                    }
+                },
+                "justica-do-trabalho": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateIndexes"
+                    }
+                },
+                "justica-eleitoral": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateIndexes"
+                    }
+                },
+                "justica-estadual": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateIndexes"
+                    }
+                },
+                "justica-federal": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateIndexes"
+                    }
+                },
+                "justica-militar": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateIndexes"
+                    }
+                },
+                "justica-superior": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateIndexes"
+                    }
+                },
+                "ministerios-publicos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateIndexes"
+                    }
+                }
+            }
+        },
+        "papi.aggregateMonth": {
+            "type": "object",
+            "properties": {
+                "cobertura": {
+                    "$ref": "#/definitions/papi.monthCoverage"
+                },
+                "mes": {
+                    "type": "integer"
+                },
+                "totais": {
+                    "$ref": "#/definitions/papi.aggregateTotals"
+                }
+            }
+        },
+        "papi.aggregateSummary": {
+            "type": "object",
+            "properties": {
+                "anos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.aggregateAnnual"
+                    }
+                },
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "grupo": {
+                    "type": "string"
+                },
+                "orgaos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "uf": {
+                    "type": "string"
+                }
+            }
+        },
+        "papi.aggregateTotals": {
+            "type": "object",
+            "properties": {
+                "descontos": {
+                    "type": "number"
+                },
+                "descontos_por_membro": {
+                    "type": "number"
+                },
+                "num_membros": {
+                    "type": "integer"
+                },
+                "outras_remuneracoes": {
+                    "type": "number"
+                },
+                "outras_remuneracoes_por_membro": {
+                    "type": "number"
+                },
+                "remuneracao_base": {
+                    "type": "number"
+                },
+                "remuneracao_base_por_membro": {
+                    "type": "number"
+                },
+                "remuneracoes": {
+                    "type": "number"
+                },
+                "remuneracoes_por_membro": {
+                    "type": "number"
+                },
+                "resumo_rubricas": {
+                    "$ref": "#/definitions/papi.itemSummary"
+                }
+            }
+        },
+        "papi.allAgencyInformation": {
+            "type": "object",
+            "properties": {
+                "coletando": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.collecting"
+                    }
+                },
+                "coletas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.summaryzedMI"
+                    }
+                },
+                "entidade": {
+                    "description": "\"J\" For Judiciário, \"M\" for Ministério Público, \"P\" for Procuradorias and \"D\" for Defensorias.",
+                    "type": "string"
+                },
+                "id_orgao": {
+                    "description": "'trt13'",
+                    "type": "string"
+                },
+                "indice_transparencia": {
+                    "$ref": "#/definitions/papi.score"
+                },
+                "jurisdicao": {
+                    "description": "\"R\" for Regional, \"M\" for Municipal, \"F\" for Federal, \"E\" for State.",
+                    "type": "string"
+                },
+                "meses_com_dados": {
+                    "type": "integer"
+                },
+                "nome": {
+                    "description": "'Tribunal Regional do Trabalho 13° Região'",
+                    "type": "string"
+                },
+                "ouvidoria": {
+                    "description": "Agencys's ombudsman url",
+                    "type": "string"
+                },
+                "total_coletas_realizadas": {
+                    "type": "integer"
+                },
+                "twitter_handle": {
+                    "description": "Agency's twitter handle",
+                    "type": "string"
+                },
+                "uf": {
+                    "description": "Short code for federative unity.",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Link for state url",
+                    "type": "string"
+                }
+            }
+        },
+        "papi.anomalies": {
+            "type": "object",
+            "properties": {
+                "anomalias": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.anomaly"
+                    }
+                },
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "fim": {
+                    "type": "string"
+                },
+                "grupo": {
+                    "type": "string"
+                },
+                "inicio": {
+                    "type": "string"
+                },
+                "janela": {
+                    "description": "meses da linha de base móvel",
+                    "type": "integer"
+                },
+                "limiar": {
+                    "description": "pontuação mínima, em módulo, para um valor ser anômalo",
+                    "type": "number"
+                },
+                "orgaos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "papi.anomaly": {
+            "type": "object",
+            "properties": {
+                "faixa_esperada": {
+                    "$ref": "#/definitions/papi.expectedRange"
+                },
+                "mediana_referencia": {
+                    "type": "number"
+                },
+                "mes": {
+                    "description": "AAAA-MM",
+                    "type": "string"
+                },
+                "meses_referencia": {
+                    "description": "meses usados na linha de base",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "metrica": {
+                    "description": "remuneracao_bruta, remuneracao_bruta_por_membro ou um campo de resumo_rubricas",
+                    "type": "string"
+                },
+                "orgao": {
+                    "type": "string"
+                },
+                "pontuacao": {
+                    "description": "escore z robusto: desvio da mediana em unidades de desvio absoluto mediano",
+                    "type": "number"
+                },
+                "referencia": {
+                    "description": "movel (meses anteriores) ou sazonal (mesmo mês dos anos anteriores)",
+                    "type": "string"
+                },
+                "rubrica_principal": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "papi.backup": {
+            "type": "object",
+            "properties": {
+                "hash": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "papi.collect": {
+            "type": "object",
+            "properties": {
+                "duracao_segundos": {
+                    "type": "number"
+                },
+                "repositorio_coletor": {
+                    "type": "string"
+                },
+                "repositorio_parser": {
+                    "type": "string"
+                },
+                "versao_coletor": {
+                    "type": "string"
+                },
+                "versao_parser": {
+                    "type": "string"
+                }
+            }
+        },
+        "papi.collecting": {
+            "type": "object",
+            "properties": {
+                "descricao": {
+                    "description": "Reasons why we didn't collect the data",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timestamp": {
+                    "description": "Day(unix) we checked the status of the data",
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.comparison": {
+            "type": "object",
+            "properties": {
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "fim": {
+                    "type": "string"
+                },
+                "inicio": {
+                    "type": "string"
+                },
+                "meses": {
+                    "description": "AAAA-MM",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "meses_incompletos": {
+                    "description": "meses em que algum órgão não tem dados",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "orgaos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.agencyComparison"
+                    }
+                }
+            }
+        },
+        "papi.coverage": {
+            "type": "object",
+            "properties": {
+                "fim": {
+                    "type": "string"
+                },
+                "grupo": {
+                    "type": "string"
+                },
+                "inicio": {
+                    "type": "string"
+                },
+                "meses": {
+                    "description": "AAAA-MM",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "orgaos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.agencyCoverage"
+                    }
+                },
+                "totais": {
+                    "$ref": "#/definitions/papi.coverageTotals"
+                },
+                "totais_por_mes": {
+                    "description": "alinhados aos meses",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.coverageTotals"
+                    }
+                }
+            }
+        },
+        "papi.coverageCell": {
+            "type": "object",
+            "properties": {
+                "situacao": {
+                    "description": "ok, coleta_manual, erro_coleta, indisponivel ou nao_coletado",
+                    "type": "string"
+                },
+                "status_coleta": {
+                    "description": "status informado pelo coletor, em erro_coleta e indisponivel",
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.coverageTotals": {
+            "type": "object",
+            "properties": {
+                "coleta_manual": {
+                    "type": "integer"
+                },
+                "erro_coleta": {
+                    "type": "integer"
+                },
+                "indisponivel": {
+                    "type": "integer"
+                },
+                "nao_coletado": {
+                    "type": "integer"
+                },
+                "ok": {
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.crawlerHistory": {
+            "type": "object",
+            "properties": {
+                "coletas_sem_versao_coletor": {
+                    "type": "integer"
+                },
+                "coletas_sem_versao_parser": {
+                    "type": "integer"
+                },
+                "coletores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.versionUsage"
+                    }
+                },
+                "id_orgao": {
+                    "type": "string"
+                },
+                "parsers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.versionUsage"
+                    }
+                }
+            }
+        },
+        "papi.crawlingErrorCount": {
+            "type": "object",
+            "properties": {
+                "id_orgao": {
+                    "type": "string"
+                },
+                "ocorrencias": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.crawlingErrorGroup": {
+            "type": "object",
+            "properties": {
+                "cmd": {
+                    "type": "string"
+                },
+                "exemplo": {
+                    "description": "saída de erro da primeira ocorrência",
+                    "type": "string"
+                },
+                "id_orgao": {
+                    "type": "string"
+                },
+                "mensagem": {
+                    "description": "última linha da saída de erro, sem endereços e números",
+                    "type": "string"
+                },
+                "meses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ocorrencias": {
+                    "type": "integer"
+                },
+                "primeira_ocorrencia": {
+                    "description": "AAAA-MM",
+                    "type": "string"
+                },
+                "repositorio_coletor": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "ultima_ocorrencia": {
+                    "description": "AAAA-MM",
+                    "type": "string"
+                },
+                "versao_coletor": {
+                    "type": "string"
+                }
+            }
+        },
+        "papi.crawlingErrors": {
+            "type": "object",
+            "properties": {
+                "erros": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.crawlingErrorGroup"
+                    }
+                },
+                "fim": {
+                    "type": "string"
+                },
+                "grupo": {
+                    "type": "string"
+                },
+                "inicio": {
+                    "type": "string"
+                },
+                "orgao": {
+                    "type": "string"
+                },
+                "por_orgao": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.crawlingErrorCount"
+                    }
+                },
+                "por_status": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.crawlingErrorCount"
+                    }
+                },
+                "total_erros": {
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.dataSummary": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "number"
+                },
+                "media": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "papi.distributionStats": {
+            "type": "object",
+            "properties": {
+                "gini": {
+                    "type": "number"
+                },
+                "mediana": {
+                    "type": "number"
+                },
+                "p10": {
+                    "type": "number"
+                },
+                "p25": {
+                    "type": "number"
+                },
+                "p75": {
+                    "type": "number"
+                },
+                "p90": {
+                    "type": "number"
+                },
+                "p99": {
+                    "type": "number"
+                }
+            }
+        },
+        "papi.durationStats": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "number"
+                },
+                "media": {
+                    "type": "number"
+                },
+                "mediana": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                }
+            }
+        },
+        "papi.entityCount": {
+            "type": "object",
+            "properties": {
+                "entidade": {
+                    "description": "valor do campo entidade dos órgãos, ex: Tribunal",
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "num_orgaos": {
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.expectedRange": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                }
+            }
+        },
+        "papi.freshness": {
+            "type": "object",
+            "properties": {
+                "grupo": {
+                    "type": "string"
+                },
+                "mes_atual": {
+                    "description": "AAAA-MM",
+                    "type": "string"
+                },
+                "mes_esperado": {
+                    "description": "último mês que já deveria ter dados: mes_atual - prazo_meses",
+                    "type": "string"
+                },
+                "orgaos_atrasados": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.lateAgency"
+                    }
+                },
+                "orgaos_em_dia": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prazo_meses": {
+                    "description": "prazo de publicação, em meses",
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.groupInfo": {
+            "type": "object",
+            "properties": {
+                "entidades": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.entityCount"
+                    }
+                },
+                "grupo": {
+                    "description": "nome usado nas URLs, ex: justica-estadual",
+                    "type": "string"
+                },
+                "jurisdicao": {
+                    "description": "valor do campo jurisdicao dos órgãos do grupo",
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "num_orgaos": {
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.growth": {
+            "type": "object",
+            "properties": {
+                "anos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.growthYear"
+                    }
+                },
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "fim": {
+                    "type": "string"
+                },
+                "grupo": {
+                    "type": "string"
+                },
+                "inicio": {
+                    "type": "string"
+                },
+                "meses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.growthMonth"
+                    }
+                },
+                "orgaos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "papi.growthComparison": {
+            "type": "object",
+            "properties": {
+                "comparado_com": {
+                    "description": "AAAA-MM ou AAAA",
+                    "type": "string"
+                },
+                "num_membros": {
+                    "$ref": "#/definitions/papi.growthVariation"
+                },
+                "num_orgaos_comparados": {
+                    "type": "integer"
+                },
+                "parcial": {
+                    "description": "a comparação exclui órgãos sem dados em um dos períodos ou, entre anos, meses com dados em só um dos anos",
+                    "type": "boolean"
+                },
+                "remuneracao_bruta": {
+                    "$ref": "#/definitions/papi.growthVariation"
+                },
+                "remuneracao_bruta_por_membro": {
+                    "$ref": "#/definitions/papi.growthVariation"
+                }
+            }
+        },
+        "papi.growthMonth": {
+            "type": "object",
+            "properties": {
+                "mes": {
+                    "description": "AAAA-MM",
+                    "type": "string"
+                },
+                "orgaos_com_erro": {
+                    "description": "órgãos cuja coleta do mês falhou",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "orgaos_sem_dados": {
+                    "description": "órgãos sem coleta no mês",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "situacao": {
+                    "description": "completo, parcial ou sem_dados",
+                    "type": "string"
+                },
+                "valores": {
+                    "$ref": "#/definitions/papi.growthValues"
+                },
+                "variacao_anual": {
+                    "description": "em relação ao mesmo mês do ano anterior",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/papi.growthComparison"
+                        }
+                    ]
+                },
+                "variacao_mensal": {
+                    "description": "em relação ao mês anterior; ausente se não há dados em um dos meses",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/papi.growthComparison"
+                        }
+                    ]
+                }
+            }
+        },
+        "papi.growthValues": {
+            "type": "object",
+            "properties": {
+                "num_membros": {
+                    "type": "number"
+                },
+                "remuneracao_bruta": {
+                    "type": "number"
+                },
+                "remuneracao_bruta_por_membro": {
+                    "type": "number"
+                }
+            }
+        },
+        "papi.growthVariation": {
+            "type": "object",
+            "properties": {
+                "absoluta": {
+                    "type": "number"
+                },
+                "percentual": {
+                    "description": "nulo quando o valor anterior é zero",
+                    "type": "number"
+                }
+            }
+        },
+        "papi.growthYear": {
+            "type": "object",
+            "properties": {
+                "ano": {
+                    "type": "integer"
+                },
+                "completo": {
+                    "description": "todos os órgãos têm os 12 meses",
+                    "type": "boolean"
+                },
+                "meses_com_dados": {
+                    "description": "soma dos meses com dados de cada órgão",
+                    "type": "integer"
+                },
+                "valores": {
+                    "$ref": "#/definitions/papi.growthValues"
+                },
+                "variacao_anual": {
+                    "$ref": "#/definitions/papi.growthComparison"
+                }
+            }
+        },
+        "papi.indexInformation": {
+            "type": "object",
+            "properties": {
+                "ano": {
+                    "type": "integer"
+                },
+                "indice_transparencia": {
+                    "$ref": "#/definitions/papi.score"
+                },
+                "mes": {
+                    "type": "integer"
+                },
+                "metadados": {
+                    "$ref": "#/definitions/papi.metadata"
+                }
+            }
+        },
+        "papi.itemSeries": {
+            "type": "object",
+            "properties": {
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "fim": {
+                    "type": "string"
+                },
+                "inicio": {
+                    "type": "string"
+                },
+                "meses": {
+                    "description": "AAAA-MM",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "num_membros": {
+                    "description": "nulo nos meses sem dados",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "orgao": {
+                    "type": "string"
+                },
+                "rubricas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.itemSeriesValues"
+                    }
+                }
+            }
+        },
+        "papi.itemSeriesValues": {
+            "type": "object",
+            "properties": {
+                "por_membro": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "rubrica": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "papi.itemSummary": {
+            "type": "object",
+            "properties": {
+                "auxilio_alimentacao": {
+                    "type": "number"
+                },
+                "auxilio_saude": {
+                    "type": "number"
+                },
+                "ferias": {
+                    "type": "number"
+                },
+                "gratificacao_natalina": {
+                    "type": "number"
+                },
+                "indenizacao_de_ferias": {
+                    "type": "number"
+                },
+                "licenca_compensatoria": {
+                    "type": "number"
+                },
+                "licenca_premio": {
+                    "type": "number"
+                },
+                "outras": {
+                    "description": "valor agregado de outras rubricas não identificadas",
+                    "type": "number"
+                }
+            }
+        },
+        "papi.lateAgency": {
+            "type": "object",
+            "properties": {
+                "erro_coleta": {
+                    "description": "se algum mês posterior ao último com dados teve erro na coleta",
+                    "type": "boolean"
+                },
+                "id_orgao": {
+                    "type": "string"
+                },
+                "meses_atraso": {
+                    "description": "nulo se o órgão nunca teve dados",
+                    "type": "integer"
+                },
+                "motivos": {
+                    "description": "motivos informados na última verificação da coleta do órgão",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "motivos_timestamp": {
+                    "type": "integer"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "ouvidoria": {
+                    "type": "string"
+                },
+                "ultimo_mes_com_dados": {
+                    "description": "AAAA-MM, nulo se o órgão nunca teve dados",
+                    "type": "string"
+                }
+            }
+        },
+        "papi.metadata": {
+            "type": "object",
+            "properties": {
+                "acesso": {
+                    "type": "string"
+                },
+                "dados_estritamente_tabulares": {
+                    "type": "boolean"
+                },
+                "despesas": {
+                    "type": "string"
+                },
+                "extensao": {
+                    "type": "string"
+                },
+                "formato_aberto": {
+                    "type": "boolean"
+                },
+                "manteve_consistencia_no_formato": {
+                    "type": "boolean"
+                },
+                "outras_receitas": {
+                    "type": "string"
+                },
+                "remuneracao_basica": {
+                    "type": "string"
+                },
+                "tem_cargo": {
+                    "type": "boolean"
+                },
+                "tem_lotacao": {
+                    "type": "boolean"
+                },
+                "tem_matricula": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "papi.miError": {
+            "type": "object",
+            "properties": {
+                "cmd": {
+                    "type": "string"
+                },
+                "err_msg": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.monthCoverage": {
+            "type": "object",
+            "properties": {
+                "orgaos_com_dados": {
+                    "type": "integer"
+                },
+                "orgaos_com_erro_coleta": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "orgaos_sem_dados": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "total_orgaos": {
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.ranking": {
+            "type": "object",
+            "properties": {
+                "ano": {
+                    "type": "integer"
+                },
+                "grupo": {
+                    "type": "string"
+                },
+                "inclui_incompletos": {
+                    "description": "se os órgãos com menos de 12 meses de dados recebem posição",
+                    "type": "boolean"
+                },
+                "metrica": {
+                    "type": "string"
+                },
+                "orgaos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.rankingEntry"
+                    }
+                },
+                "orgaos_sem_dados": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "papi.rankingEntry": {
+            "type": "object",
+            "properties": {
+                "ano_incompleto": {
+                    "type": "boolean"
+                },
+                "id_orgao": {
+                    "type": "string"
+                },
+                "meses_com_dados": {
+                    "type": "integer"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "percentil": {
+                    "description": "100 para o primeiro colocado e 0 para o último",
+                    "type": "number"
+                },
+                "posicao": {
+                    "description": "nula para órgãos com ano incompleto, se não forem incluídos",
+                    "type": "integer"
+                },
+                "posicao_ano_anterior": {
+                    "type": "integer"
+                },
+                "valor": {
+                    "type": "number"
+                },
+                "variacao_posicao": {
+                    "description": "positiva quando o órgão subiu no ranking",
+                    "type": "integer"
+                }
+            }
+        },
+        "papi.remunerationDistribution": {
+            "type": "object",
+            "properties": {
+                "num_observacoes": {
+                    "type": "integer"
+                },
+                "remuneracao_bruta": {
+                    "$ref": "#/definitions/papi.distributionStats"
+                },
+                "remuneracao_liquida": {
+                    "$ref": "#/definitions/papi.distributionStats"
+                }
+            }
+        },
+        "papi.score": {
+            "type": "object",
+            "properties": {
+                "indice_completude": {
+                    "type": "number"
+                },
+                "indice_facilidade": {
+                    "type": "number"
+                },
+                "indice_transparencia": {
+                    "type": "number"
+                }
+            }
+        },
+        "papi.summaries": {
+            "type": "object",
+            "properties": {
+                "membros_ativos": {
+                    "$ref": "#/definitions/papi.summary"
+                }
+            }
+        },
+        "papi.summary": {
+            "type": "object",
+            "properties": {
+                "descontos": {
+                    "$ref": "#/definitions/papi.dataSummary"
+                },
+                "outras_remuneracoes": {
+                    "$ref": "#/definitions/papi.dataSummary"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "remuneracao_base": {
+                    "$ref": "#/definitions/papi.dataSummary"
+                },
+                "remuneracoes": {
+                    "$ref": "#/definitions/papi.dataSummary"
+                },
+                "resumo_rubricas": {
+                    "$ref": "#/definitions/papi.itemSummary"
+                }
+            }
+        },
+        "papi.summaryzedMI": {
+            "type": "object",
+            "properties": {
+                "ano": {
+                    "type": "integer"
+                },
+                "coleta_manual": {
+                    "type": "boolean"
+                },
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "dados_coleta": {
+                    "$ref": "#/definitions/papi.collect"
+                },
+                "distribuicao": {
+                    "$ref": "#/definitions/papi.remunerationDistribution"
+                },
+                "error": {
+                    "$ref": "#/definitions/papi.miError"
+                },
                 "id_orgao": {
-                    "description": "'trt13'",
                     "type": "string"
                 },
-                "jurisdicao": {
-                    "description": "\"R\" for Regional, \"M\" for Municipal, \"F\" for Federal, \"E\" for State.",
+                "indice_transparencia": {
+                    "$ref": "#/definitions/papi.score"
+                },
+                "mes": {
+                    "type": "integer"
+                },
+                "metadados": {
+                    "$ref": "#/definitions/papi.metadata"
+                },
+                "pacote_de_dados": {
+                    "$ref": "#/definitions/papi.backup"
+                },
+                "sumarios": {
+                    "$ref": "#/definitions/papi.summaries"
+                }
+            }
+        },
+        "papi.ufInfo": {
+            "type": "object",
+            "properties": {
+                "bandeira": {
                     "type": "string"
                 },
+                "entidades": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/papi.entityCount"
+                    }
+                },
                 "nome": {
-                    "description": "'Tribunal Regional do Trabalho 13° Região'",
                     "type": "string"
                 },
-                "ouvidoria": {
-                    "description": "Agencys's ombudsman url",
-                    "type": "string"
+                "num_orgaos": {
+                    "type": "integer"
                 },
-                "possui_dados": {
-                    "description": "If there is data from that agency",
-                    "type": "boolean"
+                "regiao": {
+                    "type": "string"
                 },
-                "twitter_handle": {
-                    "description": "Agency's twitter handle",
+                "slug": {
+                    "description": "nome usado nas URLs, ex: pb",
                     "type": "string"
                 },
                 "uf": {
-                    "description": "Short code for federative unity.",
+                    "description": "sigla, ex: PB",
+                    "type": "string"
+                }
+            }
+        },
+        "papi.versionPeriod": {
+            "type": "object",
+            "properties": {
+                "fim": {
+                    "description": "AAAA-MM",
                     "type": "string"
                 },
-                "url": {
-                    "description": "Link for state url",
+                "inicio": {
+                    "description": "AAAA-MM",
                     "type": "string"
                 }
             }
         },
-        "papi.aggregateIndexes": {
+        "papi.versionUsage": {
             "type": "object",
             "properties": {
-                "agregado": {
-                    "description": "Aggregate indices",
+                "coletas": {
+                    "type": "integer"
+                },
+                "coletas_com_erro": {
+                    "type": "integer"
+                },
+                "coletas_indisponiveis": {
+                    "description": "status 4: dados indisponíveis ou malformados, não contam como erro",
+                    "type": "integer"
+                },
+                "commit": {
+                    "description": "link do commit, para repositórios do GitHub",
+                    "type": "string"
+                },
+                "duracao_segundos": {
+                    "description": "duração total das coletas, em segundos",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/papi.score"
+                            "$ref": "#/definitions/papi.durationStats"
                         }
                     ]
                 },
-                "detalhe": {
-                    "description": "All agency indices",
+                "periodos": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/papi.indexInformation"
+                        "$ref": "#/definitions/papi.versionPeriod"
                     }
                 },
-                "id_orgao": {
-                    "description": "e.g. 'trt13'",
+                "primeira_coleta": {
+                    "type": "string"
+                },
+                "repositorio": {
+                    "type": "string"
+                },
+                "taxa_erro": {
+                    "type": "number"
+                },
+                "ultima_coleta": {
+                    "type": "string"
+                },
+                "versao": {
                     "type": "string"
                 }
             }
         },
-        "papi.aggregateIndexesByGroup": {
+        "papi.webhookRequest": {
             "type": "object",
             "properties": {
-                "conselhos-de-justica": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/papi.aggregateIndexes"
-                    }
+                "grupo": {
+                    "description": "se preenchido, recebe somente os eventos do grupo (ex.: justica-estadual)",
+                    "type": "string"
                 },
-                "justica-do-trabalho": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/papi.aggregateIndexes"
-                    }
+                "orgao": {
+                    "description": "se preenchido, recebe somente os eventos do órgão",
+                    "type": "string"
                 },
-                "justica-eleitoral": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "papi.yearCoverage": {
+            "type": "object",
+            "properties": {
+                "orgaos_ano_incompleto": {
+                    "description": "órgãos com menos de 12 meses de dados",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/papi.aggregateIndexes"
+                        "type": "string"
                     }
                 },
-                "justica-estadual": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/papi.aggregateIndexes"
-                    }
+                "orgaos_com_dados": {
+                    "type": "integer"
                 },
-                "justica-federal": {
+                "orgaos_sem_dados": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/papi.aggregateIndexes"
+                        "type": "string"
                     }
                 },
-                "justica-militar": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/papi.aggregateIndexes"
-                    }
+                "total_orgaos": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tables.Correction": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "description": "mês base (AAAA-MM) para o qual os valores foram corrigidos",
+                    "type": "string"
                 },
-                "justica-superior": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/papi.aggregateIndexes"
-                    }
+                "indice": {
+                    "type": "string"
                 },
-                "ministerios-publicos": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/papi.aggregateIndexes"
-                    }
+                "versao_tabela": {
+                    "type": "string"
                 }
             }
         },
-        "papi.allAgencyInformation": {
+        "uiapi.agency": {
             "type": "object",
             "properties": {
                 "coletando": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/papi.collecting"
-                    }
-                },
-                "coletas": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/papi.summaryzedMI"
+                        "$ref": "#/definitions/uiapi.collecting"
                     }
                 },
                 "entidade": {
@@ -921,42 +4401,144 @@ const docTemplate = `{
                     "description": "'trt13'",
                     "type": "string"
                 },
-                "indice_transparencia": {
-                    "$ref": "#/definitions/papi.score"
+                "jurisdicao": {
+                    "description": "\"R\" for Regional, \"M\" for Municipal, \"F\" for Federal, \"E\" for State.",
+                    "type": "string"
+                },
+                "nome": {
+                    "description": "'Tribunal Regional do Trabalho 13° Região'",
+                    "type": "string"
+                },
+                "ouvidoria": {
+                    "description": "Agencys's ombudsman url",
+                    "type": "string"
+                },
+                "possui_dados": {
+                    "description": "If there is data from that agency",
+                    "type": "boolean"
+                },
+                "twitter_handle": {
+                    "description": "Agency's twitter handle",
+                    "type": "string"
+                },
+                "uf": {
+                    "description": "Short code for federative unity.",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Link for state url",
+                    "type": "string"
+                }
+            }
+        },
+        "uiapi.agencyBasic": {
+            "type": "object",
+            "properties": {
+                "agencyCategory": {
+                    "type": "string"
+                },
+                "fullName": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "uiapi.agencyRemuneration": {
+            "type": "object",
+            "properties": {
+                "histograma": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "max_salario": {
+                    "type": "number"
+                },
+                "package": {
+                    "$ref": "#/definitions/uiapi.backup"
+                }
+            }
+        },
+        "uiapi.annualSummary": {
+            "type": "object",
+            "properties": {
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "dados_anuais": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/uiapi.annualSummaryData"
+                    }
+                },
+                "orgao": {
+                    "$ref": "#/definitions/uiapi.agency"
+                },
+                "unidade": {
+                    "$ref": "#/definitions/uiapi.unit"
+                }
+            }
+        },
+        "uiapi.annualSummaryData": {
+            "type": "object",
+            "properties": {
+                "ano": {
+                    "type": "integer"
+                },
+                "descontos": {
+                    "type": "number"
+                },
+                "descontos_por_membro": {
+                    "type": "number"
+                },
+                "descontos_por_mes": {
+                    "type": "number"
+                },
+                "meses_com_dados": {
+                    "type": "integer"
+                },
+                "num_membros": {
+                    "type": "integer"
+                },
+                "outras_remuneracoes": {
+                    "type": "number"
+                },
+                "outras_remuneracoes_por_membro": {
+                    "type": "number"
+                },
+                "outras_remuneracoes_por_mes": {
+                    "type": "number"
                 },
-                "jurisdicao": {
-                    "description": "\"R\" for Regional, \"M\" for Municipal, \"F\" for Federal, \"E\" for State.",
-                    "type": "string"
+                "package": {
+                    "$ref": "#/definitions/uiapi.backup"
                 },
-                "meses_com_dados": {
-                    "type": "integer"
+                "remuneracao_base": {
+                    "type": "number"
                 },
-                "nome": {
-                    "description": "'Tribunal Regional do Trabalho 13° Região'",
-                    "type": "string"
+                "remuneracao_base_por_membro": {
+                    "type": "number"
                 },
-                "ouvidoria": {
-                    "description": "Agencys's ombudsman url",
-                    "type": "string"
+                "remuneracao_base_por_mes": {
+                    "type": "number"
                 },
-                "total_coletas_realizadas": {
-                    "type": "integer"
+                "remuneracoes": {
+                    "type": "number"
                 },
-                "twitter_handle": {
-                    "description": "Agency's twitter handle",
-                    "type": "string"
+                "remuneracoes_por_membro": {
+                    "type": "number"
                 },
-                "uf": {
-                    "description": "Short code for federative unity.",
-                    "type": "string"
+                "remuneracoes_por_mes": {
+                    "type": "number"
                 },
-                "url": {
-                    "description": "Link for state url",
-                    "type": "string"
+                "resumo_rubricas": {
+                    "$ref": "#/definitions/uiapi.itemSummary"
                 }
             }
         },
-        "papi.backup": {
+        "uiapi.backup": {
             "type": "object",
             "properties": {
                 "hash": {
@@ -970,432 +4552,434 @@ const docTemplate = `{
                 }
             }
         },
-        "papi.collect": {
+        "uiapi.catalogItem": {
             "type": "object",
             "properties": {
-                "duracao_segundos": {
-                    "type": "number"
-                },
-                "repositorio_coletor": {
+                "categoria": {
+                    "description": "categoria do dicionário de classificação",
                     "type": "string"
                 },
-                "repositorio_parser": {
+                "categoria_contracheque": {
                     "type": "string"
                 },
-                "versao_coletor": {
+                "detalhamento_contracheque": {
                     "type": "string"
                 },
-                "versao_parser": {
-                    "type": "string"
-                }
-            }
-        },
-        "papi.collecting": {
-            "type": "object",
-            "properties": {
-                "descricao": {
-                    "description": "Reasons why we didn't collect the data",
+                "ocorrencias": {
+                    "type": "integer"
+                },
+                "orgaos": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "timestamp": {
-                    "description": "Day(unix) we checked the status of the data",
-                    "type": "integer"
+                "primeiro_mes": {
+                    "description": "AAAA-MM",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "ultimo_mes": {
+                    "description": "AAAA-MM",
+                    "type": "string"
                 }
             }
         },
-        "papi.dataSummary": {
+        "uiapi.ceilingItem": {
             "type": "object",
             "properties": {
-                "max": {
-                    "type": "number"
+                "categoria": {
+                    "description": "categoria da rubrica no dicionário de classificação",
+                    "type": "string"
                 },
-                "media": {
-                    "type": "number"
+                "detalhamento": {
+                    "type": "string"
                 },
-                "min": {
-                    "type": "number"
+                "ocorrencias": {
+                    "type": "integer"
                 },
-                "total": {
+                "valor": {
                     "type": "number"
                 }
             }
         },
-        "papi.indexInformation": {
+        "uiapi.ceilingMonth": {
             "type": "object",
             "properties": {
                 "ano": {
                     "type": "integer"
                 },
-                "indice_transparencia": {
-                    "$ref": "#/definitions/papi.score"
+                "membros_acima_teto": {
+                    "type": "integer"
                 },
                 "mes": {
                     "type": "integer"
                 },
-                "metadados": {
-                    "$ref": "#/definitions/papi.metadata"
-                }
-            }
-        },
-        "papi.itemSummary": {
-            "type": "object",
-            "properties": {
-                "auxilio_alimentacao": {
-                    "type": "number"
-                },
-                "auxilio_saude": {
-                    "type": "number"
-                },
-                "ferias": {
-                    "type": "number"
-                },
-                "gratificacao_natalina": {
-                    "type": "number"
+                "orgao": {
+                    "type": "string"
                 },
-                "indenizacao_de_ferias": {
-                    "type": "number"
+                "resumo_rubricas": {
+                    "$ref": "#/definitions/uiapi.itemSummary"
                 },
-                "licenca_compensatoria": {
+                "teto": {
                     "type": "number"
                 },
-                "licenca_premio": {
-                    "type": "number"
+                "total_membros": {
+                    "type": "integer"
                 },
-                "outras": {
-                    "description": "valor agregado de outras rubricas não identificadas",
+                "valor_acima_teto": {
                     "type": "number"
                 }
             }
         },
-        "papi.metadata": {
+        "uiapi.ceilingReport": {
             "type": "object",
             "properties": {
-                "acesso": {
-                    "type": "string"
+                "categorias_fora_do_teto": {
+                    "description": "categorias do dicionário que não entram na comparação",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "dados_estritamente_tabulares": {
-                    "type": "boolean"
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
                 },
-                "despesas": {
+                "fim": {
                     "type": "string"
                 },
-                "extensao": {
+                "inicio": {
                     "type": "string"
                 },
-                "formato_aberto": {
-                    "type": "boolean"
+                "membros_acima_teto": {
+                    "description": "pessoas distintas que receberam acima do teto em algum mês",
+                    "type": "integer"
                 },
-                "manteve_consistencia_no_formato": {
-                    "type": "boolean"
+                "meses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/uiapi.ceilingMonth"
+                    }
                 },
-                "outras_receitas": {
-                    "type": "string"
+                "ocorrencias_acima_teto": {
+                    "description": "quantidade de pares membro/mês acima do teto",
+                    "type": "integer"
                 },
-                "remuneracao_basica": {
-                    "type": "string"
+                "orgaos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "tem_cargo": {
-                    "type": "boolean"
+                "rubricas": {
+                    "description": "rubricas de outras remunerações sujeitas ao teto recebidas pelos membros acima do teto",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/uiapi.ceilingItem"
+                    }
                 },
-                "tem_lotacao": {
-                    "type": "boolean"
+                "valor_acima_teto": {
+                    "type": "number"
                 },
-                "tem_matricula": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "papi.miError": {
-            "type": "object",
-            "properties": {
-                "cmd": {
+                "versao_dicionario": {
+                    "description": "versão do dicionário que classifica as rubricas fora do teto",
                     "type": "string"
                 },
-                "err_msg": {
+                "versao_tabela_teto": {
                     "type": "string"
-                },
-                "status": {
-                    "type": "integer"
                 }
             }
         },
-        "papi.score": {
+        "uiapi.collecting": {
             "type": "object",
             "properties": {
-                "indice_completude": {
-                    "type": "number"
-                },
-                "indice_facilidade": {
-                    "type": "number"
+                "descricao": {
+                    "description": "Reasons why we didn't collect the data",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "indice_transparencia": {
-                    "type": "number"
-                }
-            }
-        },
-        "papi.summaries": {
-            "type": "object",
-            "properties": {
-                "membros_ativos": {
-                    "$ref": "#/definitions/papi.summary"
+                "timestamp": {
+                    "description": "Day(unix) we checked the status of the data",
+                    "type": "integer"
                 }
             }
         },
-        "papi.summary": {
+        "uiapi.earner": {
             "type": "object",
             "properties": {
+                "cargo": {
+                    "type": "string"
+                },
                 "descontos": {
-                    "$ref": "#/definitions/papi.dataSummary"
+                    "type": "number"
                 },
-                "outras_remuneracoes": {
-                    "$ref": "#/definitions/papi.dataSummary"
+                "lotacao": {
+                    "type": "string"
                 },
-                "quantidade": {
-                    "type": "integer"
+                "matricula": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "outras_remuneracoes": {
+                    "type": "number"
                 },
                 "remuneracao_base": {
-                    "$ref": "#/definitions/papi.dataSummary"
+                    "type": "number"
                 },
-                "remuneracoes": {
-                    "$ref": "#/definitions/papi.dataSummary"
+                "remuneracao_bruta": {
+                    "type": "number"
                 },
-                "resumo_rubricas": {
-                    "$ref": "#/definitions/papi.itemSummary"
+                "remuneracao_liquida": {
+                    "type": "number"
+                },
+                "rubricas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/uiapi.earnerItem"
+                    }
                 }
             }
         },
-        "papi.summaryzedMI": {
+        "uiapi.earnerItem": {
             "type": "object",
             "properties": {
-                "ano": {
-                    "type": "integer"
-                },
-                "coleta_manual": {
-                    "type": "boolean"
-                },
-                "dados_coleta": {
-                    "$ref": "#/definitions/papi.collect"
-                },
-                "error": {
-                    "$ref": "#/definitions/papi.miError"
-                },
-                "id_orgao": {
+                "categoria": {
                     "type": "string"
                 },
-                "indice_transparencia": {
-                    "$ref": "#/definitions/papi.score"
-                },
-                "mes": {
-                    "type": "integer"
-                },
-                "metadados": {
-                    "$ref": "#/definitions/papi.metadata"
-                },
-                "pacote_de_dados": {
-                    "$ref": "#/definitions/papi.backup"
-                },
-                "sumarios": {
-                    "$ref": "#/definitions/papi.summaries"
+                "detalhamento": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
                 }
             }
         },
-        "uiapi.agency": {
+        "uiapi.exceptionalPayment": {
             "type": "object",
             "properties": {
-                "coletando": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/uiapi.collecting"
-                    }
+                "ano": {
+                    "type": "integer"
                 },
-                "entidade": {
-                    "description": "\"J\" For Judiciário, \"M\" for Ministério Público, \"P\" for Procuradorias and \"D\" for Defensorias.",
+                "cargo": {
                     "type": "string"
                 },
-                "id_orgao": {
-                    "description": "'trt13'",
+                "categoria": {
+                    "description": "categoria do dicionário de classificação",
                     "type": "string"
                 },
-                "jurisdicao": {
-                    "description": "\"R\" for Regional, \"M\" for Municipal, \"F\" for Federal, \"E\" for State.",
+                "matricula": {
                     "type": "string"
                 },
-                "nome": {
-                    "description": "'Tribunal Regional do Trabalho 13° Região'",
-                    "type": "string"
+                "mediana_remuneracao_bruta": {
+                    "description": "mediana da remuneração bruta nos demais meses do membro",
+                    "type": "number"
                 },
-                "ouvidoria": {
-                    "description": "Agencys's ombudsman url",
-                    "type": "string"
+                "mediana_rubrica": {
+                    "description": "mediana da rubrica nos demais meses do membro (zero nos meses sem a rubrica)",
+                    "type": "number"
                 },
-                "possui_dados": {
-                    "description": "If there is data from that agency",
-                    "type": "boolean"
+                "mes": {
+                    "type": "integer"
                 },
-                "twitter_handle": {
-                    "description": "Agency's twitter handle",
-                    "type": "string"
+                "meses_comparados": {
+                    "type": "integer"
                 },
-                "uf": {
-                    "description": "Short code for federative unity.",
+                "nome": {
                     "type": "string"
                 },
-                "url": {
-                    "description": "Link for state url",
+                "razao": {
+                    "description": "(valor - mediana_rubrica) / mediana_remuneracao_bruta",
+                    "type": "number"
+                },
+                "rubrica": {
                     "type": "string"
+                },
+                "valor": {
+                    "type": "number"
                 }
             }
         },
-        "uiapi.agencyBasic": {
+        "uiapi.exceptionalPayments": {
             "type": "object",
             "properties": {
-                "agencyCategory": {
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "fim": {
                     "type": "string"
                 },
-                "fullName": {
+                "inicio": {
                     "type": "string"
                 },
-                "name": {
+                "meses_historico": {
+                    "description": "meses anteriores ao início incluídos no histórico dos membros",
+                    "type": "integer"
+                },
+                "orgao": {
                     "type": "string"
-                }
-            }
-        },
-        "uiapi.agencyRemuneration": {
-            "type": "object",
-            "properties": {
-                "histograma": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
+                },
+                "pagamentos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/uiapi.exceptionalPayment"
                     }
                 },
-                "max_salario": {
+                "razao_minima": {
                     "type": "number"
                 },
-                "package": {
-                    "$ref": "#/definitions/uiapi.backup"
+                "versao_dicionario": {
+                    "type": "string"
                 }
             }
         },
-        "uiapi.annualSummary": {
+        "uiapi.generalSummary": {
             "type": "object",
             "properties": {
-                "dados_anuais": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/uiapi.annualSummaryData"
-                    }
+                "data_fim": {
+                    "type": "string"
                 },
-                "orgao": {
-                    "$ref": "#/definitions/uiapi.agency"
+                "data_inicio": {
+                    "type": "string"
+                },
+                "num_meses_coletados": {
+                    "type": "integer"
+                },
+                "num_orgaos": {
+                    "type": "integer"
+                },
+                "remuneracao_total": {
+                    "type": "number"
                 }
             }
         },
-        "uiapi.annualSummaryData": {
+        "uiapi.histogramBin": {
             "type": "object",
             "properties": {
-                "ano": {
-                    "type": "integer"
-                },
-                "descontos": {
-                    "type": "number"
-                },
-                "descontos_por_membro": {
+                "max": {
                     "type": "number"
                 },
-                "descontos_por_mes": {
+                "min": {
                     "type": "number"
                 },
-                "meses_com_dados": {
+                "quantidade": {
                     "type": "integer"
-                },
-                "num_membros": {
+                }
+            }
+        },
+        "uiapi.incomeHistogram": {
+            "type": "object",
+            "properties": {
+                "abaixo": {
+                    "description": "observações menores que o limite inferior da primeira faixa",
                     "type": "integer"
                 },
-                "outras_remuneracoes": {
-                    "type": "number"
-                },
-                "outras_remuneracoes_por_membro": {
-                    "type": "number"
-                },
-                "outras_remuneracoes_por_mes": {
-                    "type": "number"
-                },
-                "package": {
-                    "$ref": "#/definitions/uiapi.backup"
+                "acima": {
+                    "description": "observações maiores que o limite superior da última faixa",
+                    "type": "integer"
                 },
-                "remuneracao_base": {
-                    "type": "number"
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
                 },
-                "remuneracao_base_por_membro": {
-                    "type": "number"
+                "faixas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/uiapi.histogramBin"
+                    }
                 },
-                "remuneracao_base_por_mes": {
-                    "type": "number"
+                "fim": {
+                    "type": "string"
                 },
-                "remuneracoes": {
-                    "type": "number"
+                "inicio": {
+                    "type": "string"
                 },
-                "remuneracoes_por_membro": {
-                    "type": "number"
+                "metrica": {
+                    "type": "string"
                 },
-                "remuneracoes_por_mes": {
-                    "type": "number"
+                "observacoes": {
+                    "type": "integer"
                 },
-                "resumo_rubricas": {
-                    "$ref": "#/definitions/uiapi.itemSummary"
+                "orgao": {
+                    "type": "string"
                 }
             }
         },
-        "uiapi.backup": {
+        "uiapi.itemCatalogResult": {
             "type": "object",
             "properties": {
-                "hash": {
+                "atualizado_em": {
                     "type": "string"
                 },
-                "size": {
+                "indexando": {
+                    "type": "boolean"
+                },
+                "meses_indexados": {
+                    "description": "quantidade de pares órgão/mês indexados",
                     "type": "integer"
                 },
-                "url": {
+                "rubricas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/uiapi.catalogItem"
+                    }
+                },
+                "versao_dicionario": {
                     "type": "string"
                 }
             }
         },
-        "uiapi.collecting": {
+        "uiapi.itemCategories": {
             "type": "object",
             "properties": {
-                "descricao": {
-                    "description": "Reasons why we didn't collect the data",
+                "ano": {
+                    "type": "integer"
+                },
+                "categorias": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/uiapi.itemCategory"
                     }
                 },
-                "timestamp": {
-                    "description": "Day(unix) we checked the status of the data",
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "mes": {
                     "type": "integer"
+                },
+                "orgao": {
+                    "type": "string"
+                },
+                "versao_dicionario": {
+                    "type": "string"
                 }
             }
         },
-        "uiapi.generalSummary": {
+        "uiapi.itemCategory": {
             "type": "object",
             "properties": {
-                "data_fim": {
+                "categoria": {
                     "type": "string"
                 },
-                "data_inicio": {
+                "categoria_contracheque": {
                     "type": "string"
                 },
-                "num_meses_coletados": {
+                "membros": {
+                    "description": "membros que receberam alguma rubrica da categoria",
                     "type": "integer"
                 },
-                "num_orgaos": {
-                    "type": "integer"
+                "rubricas": {
+                    "description": "rubricas classificadas na categoria",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "remuneracao_total": {
+                "total": {
                     "type": "number"
                 }
             }
@@ -1433,6 +5017,9 @@ const docTemplate = `{
         "uiapi.mensalRemuneration": {
             "type": "object",
             "properties": {
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
                 "descontos": {
                     "type": "number"
                 },
@@ -1604,6 +5191,40 @@ const docTemplate = `{
                 }
             }
         },
+        "uiapi.topEarners": {
+            "type": "object",
+            "properties": {
+                "ano": {
+                    "type": "integer"
+                },
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
+                "membros": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/uiapi.earner"
+                    }
+                },
+                "mes": {
+                    "type": "integer"
+                },
+                "orgao": {
+                    "type": "string"
+                }
+            }
+        },
+        "uiapi.unit": {
+            "type": "object",
+            "properties": {
+                "nome": {
+                    "type": "string"
+                },
+                "versao_tabela": {
+                    "type": "string"
+                }
+            }
+        },
         "uiapi.v2AgencySummary": {
             "type": "object",
             "properties": {
@@ -1657,6 +5278,9 @@ const docTemplate = `{
                 "ano": {
                     "type": "integer"
                 },
+                "correcao_monetaria": {
+                    "$ref": "#/definitions/tables.Correction"
+                },
                 "media_por_membro": {
                     "$ref": "#/definitions/uiapi.perCapitaData"
                 },
@@ -1671,6 +5295,9 @@ const docTemplate = `{
                 },
                 "package": {
                     "$ref": "#/definitions/uiapi.backup"
+                },
+                "unidade": {
+                    "$ref": "#/definitions/uiapi.unit"
                 }
             }
         },
@@ -1728,6 +5355,61 @@ const docTemplate = `{
                     "$ref": "#/definitions/uiapi.timestamp"
                 }
             }
+        },
+        "webhook.Delivery": {
+            "type": "object",
+            "properties": {
+                "duracao_ms": {
+                    "type": "integer"
+                },
+                "entregue": {
+                    "type": "boolean"
+                },
+                "erro": {
+                    "description": "categoria do erro: dns, tempo_esgotado, conexao_recusada, tls, endereco_bloqueado, requisicao_invalida ou erro_de_rede",
+                    "type": "string"
+                },
+                "id_evento": {
+                    "type": "string"
+                },
+                "status_http": {
+                    "type": "integer"
+                },
+                "tentativa": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "tipo": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhook.Subscription": {
+            "type": "object",
+            "properties": {
+                "criado_em": {
+                    "type": "string"
+                },
+                "grupo": {
+                    "description": "slug do grupo, como em /v2/grupos",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "orgao": {
+                    "type": "string"
+                },
+                "segredo": {
+                    "description": "só é retornado na criação da assinatura",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -75,3 +75,74 @@ CREATE TABLE remuneracoes(
     CONSTRAINT remuneracoes_pk PRIMARY KEY (id_orgao, mes, ano )
 );
 
+
+CREATE TABLE webhooks(
+    id VARCHAR(32) PRIMARY KEY,    -- Identificador aleatório da assinatura, usado para consultá-la e apagá-la.
+    url TEXT NOT NULL,    -- URL que recebe os eventos.
+    segredo VARCHAR(64) NOT NULL,    -- Segredo usado para assinar os eventos (HMAC-SHA256 do corpo).
+    id_orgao VARCHAR(10),    -- Se preenchido, a assinatura recebe somente os eventos do órgão.
+    grupo VARCHAR(50),    -- Se preenchido, a assinatura recebe somente os eventos do grupo. Exemplos: justica-estadual, ministerios-publicos...
+    criado_em TIMESTAMP NOT NULL    -- Marca temporal da criação da assinatura.
+);
+
+CREATE TABLE webhook_entregas(
+    id SERIAL PRIMARY KEY,
+    id_webhook VARCHAR(32) NOT NULL,    -- Assinatura que recebeu o evento.
+    id_evento VARCHAR(100) NOT NULL,    -- Identificador do evento: id_orgao/mes/ano/timestamp da coleta.
+    tipo VARCHAR(25) NOT NULL,    -- Tipo do evento: nova_coleta, recoleta ou teste.
+    tentativa INT NOT NULL,    -- Número da tentativa de entrega do evento.
+    status_http INT NOT NULL,    -- Status HTTP da resposta. 0 se não houve resposta.
+    erro TEXT NOT NULL,    -- Categoria do erro da requisição (dns, tempo_esgotado, conexao_recusada...), quando não houve resposta.
+    entregue BOOL NOT NULL,    -- A resposta teve status 2xx?
+    timestamp TIMESTAMP NOT NULL,    -- Marca temporal da tentativa.
+    duracao_ms INT NOT NULL,    -- Duração da requisição em milissegundos.
+
+    CONSTRAINT webhook_entregas_fk FOREIGN KEY (id_webhook) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_entregas_indice ON webhook_entregas(id_webhook);
+
+CREATE TABLE webhook_pendentes(
+    id_webhook VARCHAR(32) NOT NULL,    -- Assinatura que deve receber o evento.
+    id_evento VARCHAR(100) NOT NULL,    -- Identificador do evento: id_orgao/mes/ano/timestamp da coleta.
+    evento JSONB NOT NULL,    -- Evento a ser entregue, como enviado ao assinante.
+    tentativa INT NOT NULL,    -- Número da próxima tentativa de entrega do evento.
+    proxima_tentativa TIMESTAMP NOT NULL,    -- Marca temporal a partir da qual a entrega é tentada de novo.
+
+    CONSTRAINT webhook_pendentes_pk PRIMARY KEY (id_webhook, id_evento),
+    CONSTRAINT webhook_pendentes_fk FOREIGN KEY (id_webhook) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE TABLE webhook_cursor(
+    id INT PRIMARY KEY,    -- Sempre 1.
+    timestamp TIMESTAMP NOT NULL    -- Timestamp da última coleta avisada aos webhooks.
+);
+
+CREATE TABLE execucoes(
+    tarefa VARCHAR(50) PRIMARY KEY,    -- Tarefa em segundo plano. Exemplos: catalogo_rubricas, webhooks...
+    instancia VARCHAR(100) NOT NULL,    -- Instância da API que detém a concessão da tarefa.
+    expira_em TIMESTAMP NOT NULL    -- Fim da concessão. Depois disso, outra instância pode assumir a tarefa.
+);
+
+CREATE TABLE catalogo_rubricas_zips(
+    id_orgao VARCHAR(10) NOT NULL,    -- Órgão do zip indexado.
+    mes INT NOT NULL,    -- Mês do zip indexado.
+    ano INT NOT NULL,    -- Ano do zip indexado.
+    identificacao TEXT NOT NULL,    -- Identificação do zip indexado (url e número de linhas). Se mudar, o zip é indexado novamente.
+    indexado_em TIMESTAMP NOT NULL,    -- Marca temporal da indexação.
+
+    CONSTRAINT catalogo_rubricas_zips_pk PRIMARY KEY (id_orgao, mes, ano)
+);
+
+CREATE TABLE catalogo_rubricas(
+    id_orgao VARCHAR(10) NOT NULL,
+    mes INT NOT NULL,
+    ano INT NOT NULL,
+    categoria_contracheque VARCHAR(25) NOT NULL,    -- Categoria da rubrica no contracheque: base, outras ou descontos.
+    detalhamento_contracheque TEXT NOT NULL,    -- Nome da rubrica.
+    ocorrencias INT NOT NULL,    -- Número de linhas com a rubrica no órgão/mês.
+    total DOUBLE PRECISION NOT NULL,    -- Soma dos valores da rubrica no órgão/mês.
+
+    CONSTRAINT catalogo_rubricas_pk PRIMARY KEY (id_orgao, mes, ano, categoria_contracheque, detalhamento_contracheque),
+    CONSTRAINT catalogo_rubricas_fk FOREIGN KEY (id_orgao, mes, ano) REFERENCES catalogo_rubricas_zips(id_orgao, mes, ano) ON DELETE CASCADE
+);
@@ -0,0 +1,76 @@
+// Package lease elege a instância da API que executa uma tarefa em segundo
+// plano. Todas as instâncias (dynos) iniciam as mesmas tarefas, mas só a que
+// detém a concessão da tarefa na tabela execucoes (ver init_db.sql) a executa.
+// A concessão expira se não for renovada, então outra instância assume a
+// tarefa se a atual parar.
+package lease
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Lease é a concessão de uma tarefa. Uma Lease nil sempre é obtida, o que
+// permite executar as tarefas sem banco, como nos testes.
+type Lease struct {
+	conn     *gorm.DB
+	task     string
+	instance string
+}
+
+// New cria a concessão da tarefa para esta instância.
+func New(conn *gorm.DB, task string) *Lease {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	rand.Read(b)
+	return &Lease{conn: conn, task: task, instance: fmt.Sprintf("%s-%s", host, hex.EncodeToString(b))}
+}
+
+// Acquire obtém ou renova a concessão por ttl. Retorna false se outra
+// instância detém a concessão.
+func (l *Lease) Acquire(ttl time.Duration) (bool, error) {
+	if l == nil {
+		return true, nil
+	}
+	// O horário do banco é usado em vez do horário das instâncias, que podem
+	// estar dessincronizados.
+	query := `INSERT INTO execucoes (tarefa, instancia, expira_em) VALUES (?, ?, NOW() + ? * INTERVAL '1 millisecond')
+		ON CONFLICT (tarefa) DO UPDATE SET instancia = EXCLUDED.instancia, expira_em = EXCLUDED.expira_em
+		WHERE execucoes.instancia = EXCLUDED.instancia OR execucoes.expira_em < NOW()`
+	res := l.conn.Exec(query, l.task, l.instance, ttl.Milliseconds())
+	if res.Error != nil {
+		return false, fmt.Errorf("error acquiring lease (%s): %w", l.task, res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// Release libera a concessão, se ela for desta instância.
+func (l *Lease) Release() error {
+	if l == nil {
+		return nil
+	}
+	query := `UPDATE execucoes SET expira_em = NOW() WHERE tarefa = ? AND instancia = ?`
+	if err := l.conn.Exec(query, l.task, l.instance).Error; err != nil {
+		return fmt.Errorf("error releasing lease (%s): %w", l.task, err)
+	}
+	return nil
+}
+
+// Held diz se alguma instância detém a concessão, ou seja, se a tarefa está
+// em execução.
+func (l *Lease) Held() (bool, error) {
+	if l == nil {
+		return false, nil
+	}
+	var count int64
+	query := `SELECT COUNT(*) FROM execucoes WHERE tarefa = ? AND expira_em > NOW()`
+	if err := l.conn.Raw(query, l.task).Scan(&count).Error; err != nil {
+		return false, fmt.Errorf("error checking lease (%s): %w", l.task, err)
+	}
+	return count > 0, nil
+}
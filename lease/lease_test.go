@@ -0,0 +1,26 @@
+package lease
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilLease(t *testing.T) {
+	var l *Lease
+	ok, err := l.Acquire(time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	held, err := l.Held()
+	assert.NoError(t, err)
+	assert.False(t, held)
+	assert.NoError(t, l.Release())
+}
+
+func TestNew(t *testing.T) {
+	a, b := New(nil, "tarefa"), New(nil, "tarefa")
+	assert.Equal(t, "tarefa", a.task)
+	// Cada instância tem uma identificação própria, mesmo no mesmo host.
+	assert.NotEqual(t, a.instance, b.instance)
+}
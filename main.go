@@ -8,8 +8,10 @@ import (
 	"time"
 
 	_ "github.com/dadosjusbr/api/docs"
+	"github.com/dadosjusbr/api/lease"
 	"github.com/dadosjusbr/api/papi"
 	"github.com/dadosjusbr/api/uiapi"
+	"github.com/dadosjusbr/api/webhook"
 	"github.com/dadosjusbr/storage"
 	"github.com/dadosjusbr/storage/repo/database"
 	"github.com/dadosjusbr/storage/repo/file_storage"
@@ -20,6 +22,7 @@ import (
 	"github.com/newrelic/go-agent/v3/integrations/nrecho-v4"
 	"github.com/newrelic/go-agent/v3/newrelic"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"golang.org/x/time/rate"
 )
 
 type config struct {
@@ -36,6 +39,10 @@ type config struct {
 	// Site env
 	DadosJusURL    string `envconfig:"DADOSJUS_URL" required:"true"`
 	PackageRepoURL string `envconfig:"PACKAGE_REPO_URL" required:"true"`
+	// Base URL of the site, used in the links of the feeds
+	SiteURL string `envconfig:"SITE_URL" default:"https://dadosjusbr.org"`
+	// Base URL of the state flags, served as <base>/<uf>.svg
+	FlagsURL string `envconfig:"FLAGS_URL"`
 
 	// PostgresDB config
 	PgUser     string `envconfig:"PG_USER"`
@@ -51,6 +58,14 @@ type config struct {
 	// Newrelic config
 	NewRelicApp     string `envconfig:"NEWRELIC_APP_NAME"`
 	NewRelicLicense string `envconfig:"NEWRELIC_LICENSE"`
+
+	// Interval between runs of the item catalog indexer. Only one instance
+	// indexes at a time; 0 disables the indexer in this instance.
+	ItemCatalogInterval time.Duration `envconfig:"ITEM_CATALOG_INTERVAL" default:"6h"`
+	// Interval between searches for new collections to notify the webhooks
+	WebhookInterval time.Duration `envconfig:"WEBHOOK_INTERVAL" default:"10m"`
+	// Minimum interval between webhook creations or tests from the same IP, after a burst of 5
+	WebhookRateInterval time.Duration `envconfig:"WEBHOOK_RATE_INTERVAL" default:"10m"`
 }
 
 var pgS3Client *storage.Client
@@ -120,6 +135,10 @@ func main() {
 	fmt.Printf("Going to start listening at port:%d\n", conf.Port)
 
 	e := echo.New()
+	// The real IP (used by the rate limiters) is the last X-Forwarded-For address
+	// appended by a proxy in our network (nginx and the beanstalk load balancer).
+	// Addresses added by the client itself are not trusted.
+	e.IPExtractor = echo.ExtractIPFromXFFHeader(echo.TrustLoopback(true), echo.TrustLinkLocal(true), echo.TrustPrivateNet(true))
 
 	e.GET("/", func(ctx echo.Context) error {
 		return ctx.Redirect(http.StatusMovedPermanently, "/doc")
@@ -165,10 +184,13 @@ func main() {
 	e.GET("/doc", func(c echo.Context) error {
 		return c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
 	})
-	uiApiHandler, err := uiapi.NewHandler(pgS3Client, conn, nr, conf.AwsRegion, conf.AwsS3Bucket, loc, conf.EnvOmittedFields, conf.SearchLimit, conf.DownloadLimit)
+	uiApiHandler, err := uiapi.NewHandler(pgS3Client, conn, nr, conf.AwsRegion, conf.AwsS3Bucket, loc, conf.EnvOmittedFields, conf.SearchLimit, conf.DownloadLimit, conf.FlagsURL)
 	if err != nil {
 		log.Fatalf("Error creating uiapi handler: %q", err)
 	}
+	if conf.ItemCatalogInterval > 0 {
+		uiApiHandler.StartItemCatalogIndexer(conf.ItemCatalogInterval)
+	}
 	// Return a summary of an agency. This information will be used in the head of the agency page.
 	uiAPIGroup.GET("/v1/orgao/resumo/:orgao/:ano/:mes", uiApiHandler.GetSummaryOfAgency)
 	uiAPIGroup.GET("/v2/orgao/resumo/:orgao/:ano/:mes", uiApiHandler.V2GetSummaryOfAgency)
@@ -178,6 +200,14 @@ func main() {
 	// Return all the salary of a month and year. This will be used in the point chart at the entity page.
 	uiAPIGroup.GET("/v1/orgao/salario/:orgao/:ano/:mes", uiApiHandler.GetSalaryOfAgencyMonthYear)
 	uiAPIGroup.GET("/v2/orgao/salario/:orgao/:ano/:mes", uiApiHandler.V2GetSalaryOfAgencyMonthYear)
+	// Return the members with the highest remunerations of an agency in a month.
+	uiAPIGroup.GET("/v2/orgao/maiores/:orgao/:ano/:mes", uiApiHandler.V2GetTopEarners)
+	// Return the income histogram of an agency in a period, with custom bins.
+	uiAPIGroup.GET("/v2/orgao/histograma/:orgao", uiApiHandler.V2GetIncomeHistogram)
+	// Return the one-off payments that are far above the members' own history.
+	uiAPIGroup.GET("/v2/orgao/pagamentos-excepcionais/:orgao", uiApiHandler.V2GetExceptionalPayments)
+	// Return the totals of an agency in a month grouped by the categories of the item dictionary.
+	uiAPIGroup.GET("/v2/rubricas/categorias/:orgao/:ano/:mes", uiApiHandler.V2GetItemCategories)
 	// Return the total of salary of every month of a year of a agency. The salary is divided in Wage, Perks and Others. This will be used to plot the bars chart at the state page.
 	uiAPIGroup.GET("/v1/orgao/totais/:orgao/:ano", uiApiHandler.GetTotalsOfAgencyYear)
 	uiAPIGroup.GET("/v2/orgao/totais/:orgao/:ano", uiApiHandler.V2GetTotalsOfAgencyYear)
@@ -188,12 +218,21 @@ func main() {
 	uiAPIGroup.GET("/v2/geral/remuneracao/:ano", uiApiHandler.V2GetGeneralRemunerationFromYear)
 	uiAPIGroup.GET("/v1/geral/resumo", uiApiHandler.GeneralSummaryHandler)
 	uiAPIGroup.GET("/v2/geral/resumo", uiApiHandler.GetGeneralSummary)
+	// Return the members paid above the constitutional ceiling in an agency or group.
+	uiAPIGroup.GET("/v2/teto/:param/:valor", uiApiHandler.V2GetCeilingReport)
 	// Retorna um conjunto de dados a partir de filtros informados por query params
 	uiAPIGroup.GET("/v2/pesquisar", uiApiHandler.SearchByUrl)
 	// Baixa um conjunto de dados a partir de filtros informados por query params
 	uiAPIGroup.GET("/v2/download", uiApiHandler.DownloadByUrl)
 
-	apiHandler := papi.NewHandler(pgS3Client, conf.DadosJusURL, conf.PackageRepoURL)
+	// The webhooks are notified of the new collections in the background, by
+	// the instance that holds the lease.
+	webhookStore := webhook.NewPostgres(conn)
+	webhooks := webhook.NewDispatcher(webhookStore, webhookStore, nil)
+	webhooks.Lease = lease.New(conn, "webhooks")
+	webhooks.Start(conf.WebhookInterval)
+	// The distribution statistics and the recent collections of the feed come from the uiapi handler, which has access to the remuneration zips and to postgres.
+	apiHandler := papi.NewHandler(pgS3Client, conf.DadosJusURL, conf.PackageRepoURL, conf.FlagsURL, conf.SiteURL, uiApiHandler, uiApiHandler, webhooks)
 	// Public API configuration
 	apiGroup := e.Group("/v1", middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
@@ -218,6 +257,51 @@ func main() {
 	apiGroupV2.GET("/dados/:orgao/:ano", apiHandler.GetMonthlyInfosByYear)
 	// Return MIs by month
 	apiGroupV2.GET("/dados/:orgao/:ano/:mes", apiHandler.V2GetMonthlyInfo)
+	// Return the distribution statistics of the remunerations of an agency in a year
+	apiGroupV2.GET("/distribuicao/:orgao/:ano", apiHandler.V2GetAgencyYearDistribution)
+	// Return the month-over-month and year-over-year growth of an agency or group
+	apiGroupV2.GET("/crescimento/:orgao", apiHandler.V2GetAgencyGrowth)
+	apiGroupV2.GET("/crescimento/grupo/:grupo", apiHandler.V2GetGroupGrowth)
+	// Return the agency-months whose values deviate from the agency's baseline
+	apiGroupV2.GET("/anomalias", apiHandler.V2GetAnomalies)
+	// Return aligned monthly series of several agencies
+	apiGroupV2.GET("/comparar", apiHandler.V2CompareAgencies)
+	// Return agencies ranked by a metric of the annual summaries
+	apiGroupV2.GET("/ranking/remuneracao", apiHandler.V2GetRemunerationRanking)
+	// Return the agency x month grid of the collection status
+	apiGroupV2.GET("/cobertura", apiHandler.V2GetCoverage)
+	// Return the failed collections grouped by cause
+	apiGroupV2.GET("/erros", apiHandler.V2GetCrawlingErrors)
+	// Return the crawler and parser versions used over time by an agency
+	apiGroupV2.GET("/coletores/:orgao", apiHandler.V2GetCrawlerHistory)
+	// Return the agencies whose data is late
+	apiGroupV2.GET("/atualizacao", apiHandler.V2GetFreshness)
+	// Return Atom feeds of the recently collected or updated agency-months
+	apiGroupV2.GET("/feed.atom", apiHandler.V2GetFeed)
+	apiGroupV2.GET("/orgao/:orgao/feed.atom", apiHandler.V2GetAgencyFeed)
+	// Manage the webhook subscriptions, notified of new or re-collected agency-months.
+	// Creating and testing a subscription send requests to the informed URL, so
+	// they are rate limited by IP.
+	webhookLimiter := middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:      rate.Every(conf.WebhookRateInterval),
+			Burst:     5,
+			ExpiresIn: time.Hour,
+		}),
+	})
+	apiGroupV2.POST("/webhooks", apiHandler.V2CreateWebhook, webhookLimiter)
+	apiGroupV2.GET("/webhooks/:id", apiHandler.V2GetWebhook)
+	apiGroupV2.DELETE("/webhooks/:id", apiHandler.V2DeleteWebhook)
+	apiGroupV2.GET("/webhooks/:id/entregas", apiHandler.V2GetWebhookDeliveries)
+	apiGroupV2.POST("/webhooks/:id/teste", apiHandler.V2PingWebhook, webhookLimiter)
+	// Return the groups and states used to classify the agencies
+	apiGroupV2.GET("/grupos", apiHandler.V2GetGroups)
+	apiGroupV2.GET("/ufs", apiHandler.V2GetUFs)
+	// Return the aggregated data of all agencies of a state
+	apiGroupV2.GET("/uf/:uf/resumo", apiHandler.V2GetStateSummary)
+	apiGroupV2.GET("/uf/:uf/:ano", apiHandler.V2GetStateYear)
+	// Return the aggregated monthly data of the agencies of a group
+	apiGroupV2.GET("/grupo/:grupo/:ano", apiHandler.V2GetGroupYear)
 	// Return agency index information
 	apiGroupV2.GET("/indice", apiHandler.V2GetAggregateIndexes)
 	apiGroupV2.GET("/indice/:param/:valor", apiHandler.V2GetAggregateIndexesWithParams)
@@ -226,6 +310,14 @@ func main() {
 	apiGroupV2.GET("/indices/:ano", apiHandler.V2GetAggregateIndexesWithParams)
 	apiGroupV2.GET("/indices/:ano/:mes", apiHandler.V2GetAggregateIndexesWithParams)
 	apiGroupV2.GET("/dados/:orgao", apiHandler.V2GetAllAgencyInformation)
+	// Return monthly series of item summaries
+	apiGroupV2.GET("/rubricas/:orgao", apiHandler.V2GetItemSeries)
+	// Return the item classification dictionary
+	apiGroupV2.GET("/rubricas/dicionario", apiHandler.V2GetItemDictionary)
+	// Return the catalogue of items found in the remuneration zips. The zips are
+	// only accessible by the uiapi handler, which also runs the indexer. The
+	// catalogue is stored in postgres and shared by all instances.
+	apiGroupV2.GET("/rubricas/catalogo", uiApiHandler.V2GetItemCatalog)
 
 	s := &http.Server{
 		Addr:         fmt.Sprintf(":%d", conf.Port),
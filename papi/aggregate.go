@@ -0,0 +1,207 @@
+package papi
+
+import (
+	"sort"
+
+	"github.com/dadosjusbr/api/tables"
+	"github.com/dadosjusbr/storage/models"
+)
+
+// aggregator soma os valores de vários órgãos e/ou meses.
+type aggregator struct {
+	memberMonths       int // soma do número de membros de cada mês
+	baseRemuneration   float64
+	otherRemunerations float64
+	discounts          float64
+	remunerations      float64
+	items              itemSummary
+}
+
+func (a *aggregator) addMonth(s *models.Summary, f float64) {
+	a.memberMonths += s.Count
+	a.baseRemuneration += s.BaseRemuneration.Total * f
+	a.otherRemunerations += s.OtherRemunerations.Total * f
+	a.discounts += s.Discounts.Total * f
+	a.remunerations += s.Remunerations.Total * f
+	a.items = a.items.add(s.ItemSummary, f)
+}
+
+func (a *aggregator) addYear(s models.AnnualSummary, f float64) {
+	a.memberMonths += s.TotalCount
+	a.baseRemuneration += s.BaseRemuneration * f
+	a.otherRemunerations += s.OtherRemunerations * f
+	a.discounts += s.Discounts * f
+	a.remunerations += s.Remunerations * f
+	a.items = a.items.add(s.ItemSummary, f)
+}
+
+// totals retorna os totais com o número de membros informado. Os valores por
+// membro são médias mensais: o total dividido pela soma dos membros de cada mês.
+func (a aggregator) totals(members int) aggregateTotals {
+	t := aggregateTotals{
+		MemberCount:        members,
+		BaseRemuneration:   a.baseRemuneration,
+		OtherRemunerations: a.otherRemunerations,
+		Discounts:          a.discounts,
+		Remunerations:      a.remunerations,
+		ItemSummary:        a.items,
+	}
+	if a.memberMonths > 0 {
+		n := float64(a.memberMonths)
+		t.BaseRemunerationPerCapita = a.baseRemuneration / n
+		t.OtherRemunerationsPerCapita = a.otherRemunerations / n
+		t.DiscountsPerCapita = a.discounts / n
+		t.RemunerationsPerCapita = a.remunerations / n
+	}
+	return t
+}
+
+func (s itemSummary) add(o models.ItemSummary, f float64) itemSummary {
+	return itemSummary{
+		FoodAllowance:        s.FoodAllowance + o.FoodAllowance*f,
+		BonusLicense:         s.BonusLicense + o.BonusLicense*f,
+		VacationCompensation: s.VacationCompensation + o.VacationCompensation*f,
+		Vacation:             s.Vacation + o.Vacation*f,
+		ChristmasBonus:       s.ChristmasBonus + o.ChristmasBonus*f,
+		CompensatoryLicense:  s.CompensatoryLicense + o.CompensatoryLicense*f,
+		HealthAllowance:      s.HealthAllowance + o.HealthAllowance*f,
+		Others:               s.Others + o.Others*f,
+	}
+}
+
+func sortedAgencies(agencies []models.Agency) []models.Agency {
+	sorted := append([]models.Agency{}, agencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+func agencyIDs(agencies []models.Agency) []string {
+	ids := make([]string, len(agencies))
+	for i, a := range agencies {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+// newAggregateYear soma os dados mensais dos órgãos em cada mês do ano. Meses
+// com erro na coleta ou com dados indisponíveis ficam fora da soma e são
+// listados na cobertura do mês.
+func newAggregateYear(agencies []models.Agency, year int, monthlyInfo map[string][]models.AgencyMonthlyInfo, deflator *tables.Deflator) aggregate {
+	agencies = sortedAgencies(agencies)
+	result := aggregate{
+		Year:       year,
+		Agencies:   agencyIDs(agencies),
+		Months:     make([]aggregateMonth, 12),
+		PerAgency:  []aggregateAgency{},
+		Correction: deflator.Correction(),
+	}
+	monthly := make([]aggregator, 12)
+	for i := range result.Months {
+		result.Months[i] = aggregateMonth{
+			Month: i + 1,
+			Coverage: monthCoverage{
+				TotalAgencies:  len(agencies),
+				NoData:         []string{},
+				CrawlingErrors: []string{},
+			},
+		}
+	}
+	for _, a := range agencies {
+		byMonth := map[int]models.AgencyMonthlyInfo{}
+		for _, mi := range monthlyInfo[a.ID] {
+			if mi.Year == year && mi.Month >= 1 && mi.Month <= 12 {
+				byMonth[mi.Month] = mi
+			}
+		}
+		var agg aggregator
+		months := 0
+		for i := range result.Months {
+			m := &result.Months[i]
+			mi, ok := byMonth[m.Month]
+			switch {
+			case ok && mi.ProcInfo != nil && mi.ProcInfo.String() != "" && mi.ProcInfo.Status != 4:
+				m.Coverage.CrawlingErrors = append(m.Coverage.CrawlingErrors, a.ID)
+				continue
+			case !ok || mi.Summary == nil || (mi.ProcInfo != nil && mi.ProcInfo.String() != ""):
+				m.Coverage.NoData = append(m.Coverage.NoData, a.ID)
+				continue
+			}
+			f := deflator.Factor(year, m.Month)
+			m.Coverage.AgenciesWithData++
+			monthly[i].addMonth(mi.Summary, f)
+			agg.addMonth(mi.Summary, f)
+			months++
+		}
+		entry := aggregateAgency{ID: a.ID, Name: a.Name, MonthsWithData: months}
+		if months > 0 {
+			entry.Totals = agg.totals(agg.memberMonths / months)
+		}
+		result.PerAgency = append(result.PerAgency, entry)
+	}
+	for i := range result.Months {
+		result.Months[i].Totals = monthly[i].totals(monthly[i].memberMonths)
+	}
+	return result
+}
+
+// newAggregateSummary soma os resumos anuais dos órgãos em cada ano com dados
+// de algum deles. O número de membros do ano é a soma das médias mensais dos
+// órgãos. O resumo de cada órgão é corrigido pela média dos fatores dos seus
+// meses com dados no ano (monthsWithData, por órgão e ano).
+func newAggregateSummary(agencies []models.Agency, summaries map[string][]models.AnnualSummary, monthsWithData map[string]map[int][]int, deflator *tables.Deflator) aggregateSummary {
+	agencies = sortedAgencies(agencies)
+	result := aggregateSummary{
+		Agencies:   agencyIDs(agencies),
+		Years:      []aggregateAnnual{},
+		Correction: deflator.Correction(),
+	}
+	yearSet := map[int]struct{}{}
+	for _, a := range agencies {
+		for _, s := range summaries[a.ID] {
+			yearSet[s.Year] = struct{}{}
+		}
+	}
+	var years []int
+	for y := range yearSet {
+		years = append(years, y)
+	}
+	sort.Ints(years)
+	for _, year := range years {
+		y := aggregateAnnual{
+			Year: year,
+			Coverage: yearCoverage{
+				TotalAgencies: len(agencies),
+				NoData:        []string{},
+				Incomplete:    []string{},
+			},
+			PerAgency: []aggregateAgency{},
+		}
+		var total aggregator
+		members := 0
+		for _, a := range agencies {
+			s, ok := annualSummaryOf(summaries[a.ID], year)
+			if !ok {
+				y.Coverage.NoData = append(y.Coverage.NoData, a.ID)
+				continue
+			}
+			y.Coverage.AgenciesWithData++
+			if s.NumMonthsWithData < 12 {
+				y.Coverage.Incomplete = append(y.Coverage.Incomplete, a.ID)
+			}
+			f := deflator.YearFactor(year, monthsWithData[a.ID][year]...)
+			var agg aggregator
+			agg.addYear(s, f)
+			total.addYear(s, f)
+			members += s.AverageCount
+			y.PerAgency = append(y.PerAgency, aggregateAgency{
+				ID:             a.ID,
+				Name:           a.Name,
+				MonthsWithData: s.NumMonthsWithData,
+				Totals:         agg.totals(s.AverageCount),
+			})
+		}
+		y.Totals = total.totals(members)
+		result.Years = append(result.Years, y)
+	}
+	return result
+}
@@ -0,0 +1,232 @@
+package papi
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/dadosjusbr/api/distribution"
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/api/tables"
+	"github.com/dadosjusbr/storage/models"
+)
+
+const (
+	defaultAnomalyThreshold = 3.5
+	defaultAnomalyWindow    = 12
+	minAnomalyWindow        = 6
+	maxAnomalyWindow        = 36
+	// Anos anteriores usados na linha de base sazonal e quantos deles precisam ter dados.
+	seasonalYears        = 3
+	minSeasonalBaselines = 2
+	// Quantidade mínima de meses com dados na linha de base móvel.
+	minRollingBaselines = 6
+)
+
+// anomalyMetric - valor mensal avaliado pela detecção de anomalias. Nos meses
+// sazonais, o valor é comparado com o mesmo mês dos anos anteriores, e esses
+// meses não entram na linha de base móvel dos demais.
+type anomalyMetric struct {
+	name     string
+	seasonal []int
+	item     bool
+	value    func(*models.Summary) float64
+}
+
+// anomalyMetrics - totais, valores por membro e campos do resumo de rubricas.
+// A gratificação natalina costuma ser paga em duas parcelas, a primeira no meio
+// do ano ou em novembro e a segunda em dezembro, o que também eleva a
+// remuneração bruta de dezembro. As férias coletivas de janeiro e julho
+// concentram o pagamento do terço de férias.
+var anomalyMetrics = func() []anomalyMetric {
+	metrics := []anomalyMetric{
+		{name: "remuneracao_bruta", seasonal: []int{12}, value: func(s *models.Summary) float64 {
+			return s.Remunerations.Total
+		}},
+		{name: "remuneracao_bruta_por_membro", seasonal: []int{12}, value: func(s *models.Summary) float64 {
+			if s.Count == 0 {
+				return 0
+			}
+			return s.Remunerations.Total / float64(s.Count)
+		}},
+	}
+	seasonal := map[string][]int{
+		"gratificacao_natalina": {6, 7, 11, 12},
+		"ferias":                {1, 7},
+	}
+	for _, f := range itemSummaryFields {
+		f := f
+		metrics = append(metrics, anomalyMetric{
+			name:     f.name,
+			seasonal: seasonal[f.name],
+			item:     true,
+			value:    func(s *models.Summary) float64 { return f.value(s.ItemSummary) },
+		})
+	}
+	return metrics
+}()
+
+// findAnomalies avalia os meses do período de cada órgão. O escore é o desvio
+// em relação à mediana da linha de base dividido pelo desvio absoluto mediano
+// (MAD) escalado; se o MAD for zero, é usado o desvio absoluto médio. Para não
+// marcar variações irrelevantes, a escala tem um piso de 1% da mediana e, nas
+// rubricas, de 0,1% da mediana da remuneração bruta do órgão. monthlyInfo deve
+// incluir os anos anteriores usados nas linhas de base.
+func findAnomalies(p *period.Period, monthlyInfo []models.AgencyMonthlyInfo, threshold float64, window int, deflator *tables.Deflator) []anomaly {
+	series := map[string]map[period.YearMonth]*models.Summary{}
+	for _, mi := range monthlyInfo {
+		// Meses sem dados ou com erro na coleta ficam fora das linhas de base.
+		if mi.Summary == nil || (mi.ProcInfo != nil && mi.ProcInfo.String() != "") {
+			continue
+		}
+		if series[mi.AgencyID] == nil {
+			series[mi.AgencyID] = map[period.YearMonth]*models.Summary{}
+		}
+		series[mi.AgencyID][period.YearMonth{Year: mi.Year, Month: mi.Month}] = mi.Summary
+	}
+
+	result := []anomaly{}
+	for agency, summaries := range series {
+		value := func(m anomalyMetric, ym period.YearMonth) float64 {
+			return m.value(summaries[ym]) * deflator.Factor(ym.Year, ym.Month)
+		}
+		for _, ym := range p.Months() {
+			if _, ok := summaries[ym]; !ok {
+				continue
+			}
+			for _, m := range anomalyMetrics {
+				baseline, kind := anomalyBaseline(summaries, m, ym, window)
+				if baseline == nil {
+					continue
+				}
+				values := make([]float64, len(baseline))
+				totals := make([]float64, len(baseline))
+				for i, b := range baseline {
+					values[i] = value(m, b)
+					totals[i] = value(anomalyMetrics[0], b)
+				}
+				median, scale := robustScale(values)
+				floor := 0.01 * math.Abs(median)
+				if m.item {
+					totalMedian, _ := robustScale(totals)
+					floor = math.Max(floor, 0.001*totalMedian)
+				}
+				scale = math.Max(scale, floor)
+				if scale == 0 {
+					continue
+				}
+				x := value(m, ym)
+				score := (x - median) / scale
+				if math.Abs(score) < threshold {
+					continue
+				}
+				a := anomaly{
+					Agency:         agency,
+					Month:          ym.String(),
+					Metric:         m.name,
+					Value:          x,
+					Score:          score,
+					BaselineMedian: median,
+					ExpectedRange: expectedRange{
+						Min: math.Max(median-threshold*scale, 0),
+						Max: median + threshold*scale,
+					},
+					Baseline: kind,
+				}
+				for _, b := range baseline {
+					a.BaselineMonths = append(a.BaselineMonths, b.String())
+				}
+				if !m.item {
+					a.DrivingItem = drivingItem(ym, baseline, score > 0, value)
+				}
+				result = append(result, a)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		si, sj := math.Abs(result[i].Score), math.Abs(result[j].Score)
+		if si != sj {
+			return si > sj
+		}
+		if result[i].Agency != result[j].Agency {
+			return result[i].Agency < result[j].Agency
+		}
+		return result[i].Month < result[j].Month
+	})
+	return result
+}
+
+// anomalyBaseline retorna os meses da linha de base da métrica no mês ym, em
+// ordem cronológica, e o tipo da linha de base. Retorna nil se não há meses
+// suficientes para avaliar o mês.
+func anomalyBaseline(summaries map[period.YearMonth]*models.Summary, m anomalyMetric, ym period.YearMonth, window int) ([]period.YearMonth, string) {
+	var baseline []period.YearMonth
+	if slices.Contains(m.seasonal, ym.Month) {
+		for y := ym.Year - seasonalYears; y < ym.Year; y++ {
+			if _, ok := summaries[period.YearMonth{Year: y, Month: ym.Month}]; ok {
+				baseline = append(baseline, period.YearMonth{Year: y, Month: ym.Month})
+			}
+		}
+		if len(baseline) < minSeasonalBaselines {
+			return nil, ""
+		}
+		return baseline, "sazonal"
+	}
+	for i := window; i >= 1; i-- {
+		b := ym.AddMonths(-i)
+		if _, ok := summaries[b]; !ok || slices.Contains(m.seasonal, b.Month) {
+			continue
+		}
+		baseline = append(baseline, b)
+	}
+	if len(baseline) < minRollingBaselines {
+		return nil, ""
+	}
+	return baseline, "movel"
+}
+
+// robustScale retorna a mediana dos valores e o desvio absoluto mediano
+// escalado para ser comparável ao desvio padrão de uma distribuição normal. Se
+// o MAD for zero, usa o desvio absoluto médio escalado.
+func robustScale(values []float64) (float64, float64) {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	median := distribution.Percentile(sorted, 50)
+	deviations := make([]float64, len(values))
+	var sum float64
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+		sum += deviations[i]
+	}
+	sort.Float64s(deviations)
+	if mad := distribution.Percentile(deviations, 50); mad > 0 {
+		return median, 1.4826 * mad
+	}
+	return median, 1.2533 * sum / float64(len(values))
+}
+
+// drivingItem retorna a rubrica que mais se afastou da sua mediana na linha de
+// base, no mesmo sentido do desvio do total.
+func drivingItem(ym period.YearMonth, baseline []period.YearMonth, up bool, value func(anomalyMetric, period.YearMonth) float64) string {
+	best, bestDelta := "", 0.0
+	for _, m := range anomalyMetrics {
+		if !m.item {
+			continue
+		}
+		values := make([]float64, len(baseline))
+		for i, b := range baseline {
+			values[i] = value(m, b)
+		}
+		median, _ := robustScale(values)
+		delta := value(m, ym) - median
+		if !up {
+			delta = -delta
+		}
+		if delta > bestDelta {
+			best, bestDelta = m.name, delta
+		}
+	}
+	return best
+}
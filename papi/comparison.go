@@ -0,0 +1,101 @@
+package papi
+
+import (
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/api/tables"
+	"github.com/dadosjusbr/storage/models"
+)
+
+const (
+	maxComparedAgencies = 10
+
+	comparisonWithData = "com_dados"
+	comparisonNoData   = "sem_dados"
+	comparisonError    = "erro_coleta"
+)
+
+// newComparison alinha as séries dos órgãos aos meses do período. Meses com
+// erro na coleta ou com dados indisponíveis não têm valores.
+func newComparison(agencies []string, p *period.Period, monthlyInfo map[string][]models.AgencyMonthlyInfo, deflator *tables.Deflator) comparison {
+	months := p.Months()
+	result := comparison{
+		Agencies:         agencies,
+		Start:            months[0].String(),
+		End:              months[len(months)-1].String(),
+		Months:           make([]string, len(months)),
+		IncompleteMonths: []string{},
+		Series:           []agencyComparison{},
+		Correction:       deflator.Correction(),
+	}
+	incomplete := make([]bool, len(months))
+	for i, m := range months {
+		result.Months[i] = m.String()
+	}
+	for _, agency := range agencies {
+		byMonth := map[period.YearMonth]models.AgencyMonthlyInfo{}
+		for _, mi := range monthlyInfo[agency] {
+			if p.Contains(mi.Year, mi.Month) {
+				byMonth[period.YearMonth{Year: mi.Year, Month: mi.Month}] = mi
+			}
+		}
+		s := agencyComparison{
+			Agency:             agency,
+			Status:             make([]string, len(months)),
+			MissingMonths:      []string{},
+			MemberCount:        make([]*int, len(months)),
+			BaseRemuneration:   make([]*float64, len(months)),
+			OtherRemunerations: make([]*float64, len(months)),
+			Discounts:          make([]*float64, len(months)),
+			Remunerations:      make([]*float64, len(months)),
+			PerCapita:          make([]*float64, len(months)),
+			Score:              make([]*score, len(months)),
+			// As rubricas usam as mesmas regras das séries de rubricas.
+			Items: newItemSeries(agency, p, itemSummaryFields, monthlyInfo[agency], deflator).Items,
+		}
+		for i, m := range months {
+			mi, ok := byMonth[m]
+			switch {
+			case ok && mi.ProcInfo != nil && mi.ProcInfo.String() != "" && mi.ProcInfo.Status != 4:
+				s.Status[i] = comparisonError
+			case !ok || mi.Summary == nil || (mi.ProcInfo != nil && mi.ProcInfo.String() != ""):
+				s.Status[i] = comparisonNoData
+			default:
+				s.Status[i] = comparisonWithData
+			}
+			if s.Status[i] != comparisonWithData {
+				s.MissingMonths = append(s.MissingMonths, m.String())
+				incomplete[i] = true
+				continue
+			}
+			f := deflator.Factor(m.Year, m.Month)
+			count := mi.Summary.Count
+			s.MemberCount[i] = &count
+			s.BaseRemuneration[i] = scaledValue(mi.Summary.BaseRemuneration.Total, f)
+			s.OtherRemunerations[i] = scaledValue(mi.Summary.OtherRemunerations.Total, f)
+			s.Discounts[i] = scaledValue(mi.Summary.Discounts.Total, f)
+			s.Remunerations[i] = scaledValue(mi.Summary.Remunerations.Total, f)
+			if count > 0 {
+				s.PerCapita[i] = scaledValue(mi.Summary.Remunerations.Total/float64(count), f)
+			}
+			if mi.Score != nil {
+				s.Score[i] = &score{
+					Score:             mi.Score.Score,
+					CompletenessScore: mi.Score.CompletenessScore,
+					EasinessScore:     mi.Score.EasinessScore,
+				}
+			}
+		}
+		result.Series = append(result.Series, s)
+	}
+	for i, m := range result.Months {
+		if incomplete[i] {
+			result.IncompleteMonths = append(result.IncompleteMonths, m)
+		}
+	}
+	return result
+}
+
+func scaledValue(v, f float64) *float64 {
+	scaled := v * f
+	return &scaled
+}
@@ -0,0 +1,47 @@
+package papi
+
+import (
+	"github.com/dadosjusbr/api/tables"
+	"github.com/dadosjusbr/storage/models"
+)
+
+func (d dataSummary) scale(f float64) dataSummary {
+	return dataSummary{Max: d.Max * f, Min: d.Min * f, Average: d.Average * f, Total: d.Total * f}
+}
+
+// scale multiplica os valores monetários da distribuição por f. O coeficiente
+// de Gini não depende da escala dos valores.
+func (d remunerationDistribution) scale(f float64) remunerationDistribution {
+	return remunerationDistribution{Members: d.Members, Gross: d.Gross.scale(f), Net: d.Net.scale(f)}
+}
+
+func (s distributionStats) scale(f float64) distributionStats {
+	return distributionStats{
+		Median: s.Median * f,
+		P10:    s.P10 * f,
+		P25:    s.P25 * f,
+		P75:    s.P75 * f,
+		P90:    s.P90 * f,
+		P99:    s.P99 * f,
+		Gini:   s.Gini,
+	}
+}
+
+// correct aplica a correção monetária aos sumários do mês.
+func (mi *summaryzedMI) correct(d *tables.Deflator) {
+	if d == nil || mi.Summary == nil {
+		return
+	}
+	f := d.Factor(mi.Year, mi.Month)
+	s := &mi.Summary.MemberActive
+	s.BaseRemuneration = s.BaseRemuneration.scale(f)
+	s.OtherRemunerations = s.OtherRemunerations.scale(f)
+	s.Discounts = s.Discounts.scale(f)
+	s.Remunerations = s.Remunerations.scale(f)
+	s.ItemSummary = itemSummary(tables.ScaleItemSummary(models.ItemSummary(s.ItemSummary), f))
+	if mi.Distribution != nil {
+		dist := mi.Distribution.scale(f)
+		mi.Distribution = &dist
+	}
+	mi.Correction = d.Correction()
+}
@@ -0,0 +1,86 @@
+package papi
+
+import (
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/storage/models"
+)
+
+// Situações das células da matriz de cobertura.
+const (
+	coverageOK           = "ok"
+	coverageManual       = "coleta_manual"
+	coverageError        = "erro_coleta"
+	coverageUnavailable  = "indisponivel"
+	coverageNotCollected = "nao_coletado"
+)
+
+// coverageStatus classifica a coleta de um órgão em um mês. O status 4 é o
+// aviso dos coletores de que os dados estão indisponíveis ou malformados.
+func coverageStatus(mi models.AgencyMonthlyInfo) coverageCell {
+	switch {
+	case mi.ProcInfo != nil && mi.ProcInfo.String() != "":
+		status := mi.ProcInfo.Status
+		if status == 4 {
+			return coverageCell{Status: coverageUnavailable, CrawlerStatus: &status}
+		}
+		return coverageCell{Status: coverageError, CrawlerStatus: &status}
+	case mi.Summary == nil:
+		return coverageCell{Status: coverageNotCollected}
+	case mi.ManualCollection:
+		return coverageCell{Status: coverageManual}
+	default:
+		return coverageCell{Status: coverageOK}
+	}
+}
+
+func (t *coverageTotals) add(status string) {
+	switch status {
+	case coverageOK:
+		t.OK++
+	case coverageManual:
+		t.Manual++
+	case coverageError:
+		t.CrawlingError++
+	case coverageUnavailable:
+		t.Unavailable++
+	default:
+		t.NotCollected++
+	}
+}
+
+// newCoverage monta a matriz órgão × mês da situação das coletas no período,
+// com os totais de cada linha, de cada coluna e da matriz.
+func newCoverage(agencies []models.Agency, p *period.Period, monthlyInfo map[string][]models.AgencyMonthlyInfo) coverage {
+	months := p.Months()
+	result := coverage{
+		Start:       months[0].String(),
+		End:         months[len(months)-1].String(),
+		Months:      make([]string, len(months)),
+		Agencies:    []agencyCoverage{},
+		MonthTotals: make([]coverageTotals, len(months)),
+	}
+	for i, m := range months {
+		result.Months[i] = m.String()
+	}
+	for _, a := range sortedAgencies(agencies) {
+		byMonth := map[period.YearMonth]models.AgencyMonthlyInfo{}
+		for _, mi := range monthlyInfo[a.ID] {
+			if p.Contains(mi.Year, mi.Month) {
+				byMonth[period.YearMonth{Year: mi.Year, Month: mi.Month}] = mi
+			}
+		}
+		row := agencyCoverage{Agency: a.ID, Cells: make([]coverageCell, len(months))}
+		for i, m := range months {
+			cell := coverageCell{Status: coverageNotCollected}
+			if mi, ok := byMonth[m]; ok {
+				cell = coverageStatus(mi)
+			}
+			row.Cells[i] = cell
+			row.Totals.add(cell.Status)
+			result.MonthTotals[i].add(cell.Status)
+			result.Totals.add(cell.Status)
+		}
+		result.Agencies = append(result.Agencies, row)
+	}
+	return result
+}
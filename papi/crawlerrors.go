@@ -0,0 +1,147 @@
+package papi
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/storage/models"
+)
+
+const maxErrorMessageLen = 300
+
+var (
+	errorURLs    = regexp.MustCompile(`https?://\S+`)
+	errorHex     = regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`)
+	errorNumbers = regexp.MustCompile(`\d+`)
+)
+
+// normalizeErrorMessage reduz a saída de erro do coletor a uma mensagem que
+// permite agrupar falhas com a mesma causa: a linha do panic, nos coletores em
+// Go, ou a última linha não vazia, onde fica a exceção nos coletores em
+// Python. Endereços, números e espaços repetidos são removidos.
+func normalizeErrorMessage(stderr string) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	msg := ""
+	for i := len(lines) - 1; i >= 0; i-- {
+		if msg = strings.TrimSpace(lines[i]); msg != "" {
+			break
+		}
+	}
+	for _, l := range lines {
+		if strings.HasPrefix(l, "panic:") {
+			msg = l
+			break
+		}
+	}
+	msg = errorURLs.ReplaceAllString(msg, "<url>")
+	msg = errorHex.ReplaceAllString(msg, "<hex>")
+	msg = errorNumbers.ReplaceAllString(msg, "<n>")
+	return truncate(strings.Join(strings.Fields(msg), " "), maxErrorMessageLen)
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
+
+type crawlingErrorKey struct {
+	agency         string
+	status         int32
+	crawlerRepo    string
+	crawlerVersion string
+	message        string
+}
+
+// newCrawlingErrors agrupa as coletas com erro no período por órgão, status,
+// repositório e versão do coletor e mensagem de erro normalizada. Os grupos
+// vêm do mais frequente para o menos frequente.
+func newCrawlingErrors(p *period.Period, monthlyInfo []models.AgencyMonthlyInfo) crawlingErrors {
+	months := p.Months()
+	result := crawlingErrors{
+		Start:     months[0].String(),
+		End:       months[len(months)-1].String(),
+		Errors:    []crawlingErrorGroup{},
+		PerStatus: []crawlingErrorCount{},
+		PerAgency: []crawlingErrorCount{},
+	}
+	// Ordena por mês para que o exemplo seja o da primeira ocorrência.
+	sorted := append([]models.AgencyMonthlyInfo{}, monthlyInfo...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Year != sorted[j].Year {
+			return sorted[i].Year < sorted[j].Year
+		}
+		return sorted[i].Month < sorted[j].Month
+	})
+	groups := map[crawlingErrorKey]*crawlingErrorGroup{}
+	perStatus := map[int32]int{}
+	perAgency := map[string]int{}
+	for _, mi := range sorted {
+		if mi.ProcInfo == nil || mi.ProcInfo.String() == "" || !p.Contains(mi.Year, mi.Month) {
+			continue
+		}
+		key := crawlingErrorKey{
+			agency:         mi.AgencyID,
+			status:         mi.ProcInfo.Status,
+			crawlerRepo:    mi.CrawlerRepo,
+			crawlerVersion: mi.CrawlerVersion,
+			message:        normalizeErrorMessage(mi.ProcInfo.Stderr),
+		}
+		month := period.YearMonth{Year: mi.Year, Month: mi.Month}.String()
+		g, ok := groups[key]
+		if !ok {
+			g = &crawlingErrorGroup{
+				Agency:          key.agency,
+				Status:          key.status,
+				CrawlerRepo:     key.crawlerRepo,
+				CrawlerVersion:  key.crawlerVersion,
+				Message:         key.message,
+				Example:         truncate(strings.TrimSpace(mi.ProcInfo.Stderr), maxErrorMessageLen),
+				Cmd:             mi.ProcInfo.Cmd,
+				FirstOccurrence: month,
+			}
+			groups[key] = g
+		}
+		g.Count++
+		g.LastOccurrence = month
+		g.Months = append(g.Months, month)
+		result.Total++
+		perStatus[key.status]++
+		perAgency[key.agency]++
+	}
+	for _, g := range groups {
+		result.Errors = append(result.Errors, *g)
+	}
+	sort.Slice(result.Errors, func(i, j int) bool {
+		a, b := result.Errors[i], result.Errors[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Agency != b.Agency {
+			return a.Agency < b.Agency
+		}
+		return a.FirstOccurrence < b.FirstOccurrence
+	})
+	for status, n := range perStatus {
+		s := status
+		result.PerStatus = append(result.PerStatus, crawlingErrorCount{Status: &s, Count: n})
+	}
+	sort.Slice(result.PerStatus, func(i, j int) bool {
+		return *result.PerStatus[i].Status < *result.PerStatus[j].Status
+	})
+	for agency, n := range perAgency {
+		result.PerAgency = append(result.PerAgency, crawlingErrorCount{Agency: agency, Count: n})
+	}
+	sort.Slice(result.PerAgency, func(i, j int) bool {
+		a, b := result.PerAgency[i], result.PerAgency[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Agency < b.Agency
+	})
+	return result
+}
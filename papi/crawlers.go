@@ -0,0 +1,128 @@
+package papi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dadosjusbr/api/distribution"
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/storage/models"
+)
+
+type versionKey struct {
+	repo    string
+	version string
+}
+
+// newCrawlerHistory lista as versões do coletor e do parser usadas nas coletas
+// do órgão, na ordem em que passaram a ser usadas. Coletas sem repositório e
+// sem versão são apenas contadas.
+func newCrawlerHistory(agencyID string, collections []models.AgencyMonthlyInfo) crawlerHistory {
+	sorted := append([]models.AgencyMonthlyInfo{}, collections...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Year != sorted[j].Year {
+			return sorted[i].Year < sorted[j].Year
+		}
+		return sorted[i].Month < sorted[j].Month
+	})
+	crawlers, crawlerless := newVersionHistory(sorted, func(mi models.AgencyMonthlyInfo) versionKey {
+		return versionKey{mi.CrawlerRepo, mi.CrawlerVersion}
+	})
+	parsers, parserless := newVersionHistory(sorted, func(mi models.AgencyMonthlyInfo) versionKey {
+		return versionKey{mi.ParserRepo, mi.ParserVersion}
+	})
+	return crawlerHistory{
+		Agency:                agencyID,
+		Crawlers:              crawlers,
+		Parsers:               parsers,
+		WithoutCrawlerVersion: crawlerless,
+		WithoutParserVersion:  parserless,
+	}
+}
+
+// newVersionHistory agrupa as coletas, em ordem cronológica, pela versão
+// retornada por key.
+func newVersionHistory(collections []models.AgencyMonthlyInfo, key func(models.AgencyMonthlyInfo) versionKey) ([]versionUsage, int) {
+	var order []versionKey
+	byVersion := map[versionKey][]models.AgencyMonthlyInfo{}
+	unversioned := 0
+	for _, mi := range collections {
+		k := key(mi)
+		if k.repo == "" && k.version == "" {
+			unversioned++
+			continue
+		}
+		if _, ok := byVersion[k]; !ok {
+			order = append(order, k)
+		}
+		byVersion[k] = append(byVersion[k], mi)
+	}
+	versions := []versionUsage{}
+	for _, k := range order {
+		versions = append(versions, newVersionUsage(k, byVersion[k]))
+	}
+	return versions, unversioned
+}
+
+func newVersionUsage(k versionKey, collections []models.AgencyMonthlyInfo) versionUsage {
+	v := versionUsage{
+		Repo:      k.repo,
+		Version:   k.version,
+		CommitURL: commitURL(k.repo, k.version),
+		Periods:   []versionPeriod{},
+	}
+	var durations []float64
+	var last period.YearMonth
+	for i, mi := range collections {
+		m := period.YearMonth{Year: mi.Year, Month: mi.Month}
+		// Meses consecutivos formam um único período.
+		if i > 0 && m == last.AddMonths(1) {
+			v.Periods[len(v.Periods)-1].End = m.String()
+		} else {
+			v.Periods = append(v.Periods, versionPeriod{Start: m.String(), End: m.String()})
+		}
+		last = m
+		v.Collections++
+		switch {
+		case mi.ProcInfo != nil && mi.ProcInfo.String() != "" && mi.ProcInfo.Status == 4:
+			v.Unavailable++
+		case mi.ProcInfo != nil && mi.ProcInfo.String() != "":
+			v.Errors++
+		}
+		if mi.Duration > 0 {
+			durations = append(durations, mi.Duration)
+		}
+		if mi.CrawlingTimestamp != nil {
+			t := mi.CrawlingTimestamp.AsTime()
+			if v.FirstCrawl == nil || t.Before(*v.FirstCrawl) {
+				v.FirstCrawl = &t
+			}
+			if v.LastCrawl == nil || t.After(*v.LastCrawl) {
+				v.LastCrawl = &t
+			}
+		}
+	}
+	v.ErrorRate = float64(v.Errors) / float64(v.Collections)
+	if len(durations) > 0 {
+		sort.Float64s(durations)
+		total := 0.0
+		for _, d := range durations {
+			total += d
+		}
+		v.Duration = &durationStats{
+			Min:    durations[0],
+			Median: distribution.Percentile(durations, 50),
+			Mean:   total / float64(len(durations)),
+			Max:    durations[len(durations)-1],
+		}
+	}
+	return v
+}
+
+// commitURL retorna o link do commit da versão, para repositórios do GitHub.
+func commitURL(repo, version string) string {
+	if version == "" || !strings.HasPrefix(repo, "https://github.com/") {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(repo, "/"), ".git") + "/commit/" + version
+}
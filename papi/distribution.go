@@ -0,0 +1,56 @@
+package papi
+
+import (
+	"log"
+
+	"github.com/dadosjusbr/api/distribution"
+	"github.com/dadosjusbr/api/tables"
+)
+
+// DistributionSource calcula a distribuição das remunerações a partir dos zips
+// de remunerações, que a API pública não acessa diretamente. Os métodos
+// retornam nil quando não há dados para o órgão/período. A distribuição anual
+// recebe o deflator porque os pares membro/mês são corrigidos, cada um pelo
+// fator do seu mês, antes do cálculo dos percentis e do Gini.
+type DistributionSource interface {
+	AgencyMonthDistribution(agency string, year, month int) (*distribution.Distribution, error)
+	AgencyYearDistribution(agency string, year int, deflator *tables.Deflator) (*distribution.Distribution, error)
+}
+
+func newRemunerationDistribution(d *distribution.Distribution) *remunerationDistribution {
+	if d == nil {
+		return nil
+	}
+	return &remunerationDistribution{
+		Members: d.Members,
+		Gross:   newDistributionStats(d.Gross),
+		Net:     newDistributionStats(d.Net),
+	}
+}
+
+func newDistributionStats(s distribution.Stats) distributionStats {
+	return distributionStats{
+		Median: s.Median,
+		P10:    s.P10,
+		P25:    s.P25,
+		P75:    s.P75,
+		P90:    s.P90,
+		P99:    s.P99,
+		Gini:   s.Gini,
+	}
+}
+
+// monthDistribution retorna a distribuição das remunerações do órgão/mês. Como
+// a distribuição complementa os dados do mês, um erro aqui não impede a
+// resposta: a distribuição é apenas omitida.
+func (h handler) monthDistribution(agency string, year, month int) *remunerationDistribution {
+	if h.distributions == nil {
+		return nil
+	}
+	d, err := h.distributions.AgencyMonthDistribution(agency, year, month)
+	if err != nil {
+		log.Printf("[distribution] error getting distribution (orgao:%s ano:%d mes:%d): %q", agency, year, month, err)
+		return nil
+	}
+	return newRemunerationDistribution(d)
+}
@@ -0,0 +1,219 @@
+package papi
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dadosjusbr/storage/models"
+)
+
+// CollectionSource lista as coletas atuais com dados, da mais recente para a
+// mais antiga pela data da coleta, limitadas a limit. Assim, a recoleta de um
+// mês antigo também aparece no feed geral.
+type CollectionSource interface {
+	RecentCollections(limit int) ([]models.AgencyMonthlyInfo, error)
+}
+
+// feedSize é o número máximo de entradas de um feed.
+const feedSize = 50
+
+var monthNames = []string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}
+
+// atomFeed é um feed no formato Atom (RFC 4287).
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   atomAuthor  `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href  string `xml:"href,attr"`
+	Rel   string `xml:"rel,attr,omitempty"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+// atomEntry é a entrada de um órgão/mês. O id é o mesmo em todas as coletas do
+// mês e updated é o timestamp da última coleta, então uma recoleta aparece
+// como atualização da entrada.
+type atomEntry struct {
+	ID       string         `xml:"id"`
+	Title    string         `xml:"title"`
+	Updated  string         `xml:"updated"`
+	Links    []atomLink     `xml:"link"`
+	Category []atomCategory `xml:"category"`
+	Summary  atomText       `xml:"summary"`
+	Content  atomText       `xml:"content"`
+}
+
+// feedItem é uma coleta com dados de um órgão/mês.
+type feedItem struct {
+	agency models.Agency
+	mi     models.AgencyMonthlyInfo
+}
+
+// newFeedItems retorna as coletas com dados, da mais recente para a mais
+// antiga, limitadas a feedSize. Órgãos ausentes de agencies usam só o id.
+func newFeedItems(agencies []models.Agency, monthlyInfo map[string][]models.AgencyMonthlyInfo) []feedItem {
+	byID := map[string]models.Agency{}
+	for _, a := range agencies {
+		byID[a.ID] = a
+	}
+	var items []feedItem
+	for id, mis := range monthlyInfo {
+		for _, mi := range mis {
+			if mi.Summary == nil || mi.CrawlingTimestamp == nil || (mi.ProcInfo != nil && mi.ProcInfo.String() != "") {
+				continue
+			}
+			a, ok := byID[id]
+			if !ok {
+				a = models.Agency{ID: id}
+			}
+			items = append(items, feedItem{agency: a, mi: mi})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		ti, tj := items[i].mi.CrawlingTimestamp.AsTime(), items[j].mi.CrawlingTimestamp.AsTime()
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		if items[i].agency.ID != items[j].agency.ID {
+			return items[i].agency.ID < items[j].agency.ID
+		}
+		if items[i].mi.Year != items[j].mi.Year {
+			return items[i].mi.Year > items[j].mi.Year
+		}
+		return items[i].mi.Month > items[j].mi.Month
+	})
+	if len(items) > feedSize {
+		items = items[:feedSize]
+	}
+	return items
+}
+
+// formatNumber formata o número com separador de milhar e duas casas
+// decimais, como no site (1.234.567,89).
+func formatNumber(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, decimals := s[:len(s)-3], s[len(s)-2:]
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String() + "," + decimals
+}
+
+// feedTag retorna o id (tag URI, RFC 4151) de um recurso do feed.
+func (h handler) feedTag(resource string) string {
+	host := "dadosjusbr.org"
+	if u, err := url.Parse(h.siteURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	return fmt.Sprintf("tag:%s,2020:%s", host, resource)
+}
+
+func (h handler) newFeedEntry(it feedItem) atomEntry {
+	mi := it.mi
+	name := it.agency.Name
+	if name == "" {
+		name = strings.ToUpper(it.agency.ID)
+	}
+	month := fmt.Sprintf("%d/%d", mi.Month, mi.Year)
+	if mi.Month >= 1 && mi.Month <= 12 {
+		month = fmt.Sprintf("%s de %d", monthNames[mi.Month-1], mi.Year)
+	}
+	// O índice de transparência é exibido com vírgula decimal, como os valores.
+	score := ""
+	if mi.Score != nil {
+		score = strings.Replace(fmt.Sprintf("%.2f", mi.Score.Score), ".", ",", 1)
+	}
+	summary := fmt.Sprintf("Membros: %d. Remuneração total: R$ %s.", mi.Summary.Count, formatNumber(mi.Summary.Remunerations.Total))
+	if score != "" {
+		summary += fmt.Sprintf(" Índice de transparência: %s.", score)
+	}
+	siteURL := fmt.Sprintf("%s/orgao/%s/%d/%d", h.siteURL, it.agency.ID, mi.Year, mi.Month)
+	entry := atomEntry{
+		ID:      h.feedTag(fmt.Sprintf("coleta/%s/%d/%02d", it.agency.ID, mi.Year, mi.Month)),
+		Title:   fmt.Sprintf("%s: dados de %s", name, month),
+		Updated: mi.CrawlingTimestamp.AsTime().UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: siteURL, Rel: "alternate", Type: "text/html", Title: "Página do órgão no DadosJusBr"},
+		},
+		Category: []atomCategory{{Term: it.agency.ID, Label: name}},
+		Summary:  atomText{Type: "text", Body: summary},
+	}
+	var content strings.Builder
+	content.WriteString("<ul>")
+	fmt.Fprintf(&content, "<li>Membros: %d</li>", mi.Summary.Count)
+	fmt.Fprintf(&content, "<li>Remuneração total: R$ %s</li>", formatNumber(mi.Summary.Remunerations.Total))
+	if score != "" {
+		fmt.Fprintf(&content, "<li>Índice de transparência: %s</li>", score)
+	}
+	content.WriteString("</ul>")
+	fmt.Fprintf(&content, `<p><a href="%s">Ver no DadosJusBr</a>`, html.EscapeString(siteURL))
+	if mi.Package != nil && mi.Package.URL != "" {
+		packageURL := h.formatDownloadUrl(mi.Package.URL)
+		entry.Links = append(entry.Links, atomLink{Href: packageURL, Rel: "enclosure", Type: "application/zip", Title: "Pacote de dados"})
+		fmt.Fprintf(&content, ` | <a href="%s">Baixar o pacote de dados</a>`, html.EscapeString(packageURL))
+	}
+	content.WriteString("</p>")
+	entry.Content = atomText{Type: "html", Body: content.String()}
+	return entry
+}
+
+// newFeed cria o feed com as coletas. O feed é atualizado na data da coleta
+// mais recente, ou em now se não houver coletas.
+func (h handler) newFeed(id, title, selfURL string, items []feedItem, now time.Time) atomFeed {
+	feed := atomFeed{
+		ID:       h.feedTag(id),
+		Title:    title,
+		Subtitle: "Órgãos/meses coletados ou atualizados recentemente pelo DadosJusBr.",
+		Updated:  now.UTC().Format(time.RFC3339),
+		Links:    []atomLink{{Href: selfURL, Rel: "self", Type: "application/atom+xml"}},
+		Author:   atomAuthor{Name: "DadosJusBr", URI: h.siteURL},
+		Entries:  []atomEntry{},
+	}
+	if h.siteURL != "" {
+		feed.Links = append(feed.Links, atomLink{Href: h.siteURL, Rel: "alternate", Type: "text/html"})
+	}
+	for i, it := range items {
+		entry := h.newFeedEntry(it)
+		if i == 0 {
+			feed.Updated = entry.Updated
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
@@ -0,0 +1,97 @@
+package papi
+
+import (
+	"sort"
+
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/storage/models"
+)
+
+const (
+	// defaultPublicationDelay - meses, contados a partir do mês atual, em que
+	// os dados de um mês ainda podem não ter sido publicados. Com o padrão, em
+	// outubro os órgãos devem ter publicado os dados até agosto.
+	defaultPublicationDelay = 2
+	maxPublicationDelay     = 24
+)
+
+// lastMonthsWithData retorna, para cada órgão, o último mês com dados. Meses
+// com erro na coleta ou com dados indisponíveis não contam.
+func lastMonthsWithData(monthlyInfo map[string][]models.AgencyMonthlyInfo) map[string]period.YearMonth {
+	last := map[string]period.YearMonth{}
+	for agency, mis := range monthlyInfo {
+		for _, mi := range mis {
+			if mi.Summary == nil || (mi.ProcInfo != nil && mi.ProcInfo.String() != "") {
+				continue
+			}
+			m := period.YearMonth{Year: mi.Year, Month: mi.Month}
+			if l, ok := last[agency]; !ok || monthsBetween(l, m) > 0 {
+				last[agency] = m
+			}
+		}
+	}
+	return last
+}
+
+// monthsBetween retorna quantos meses to está depois de from.
+func monthsBetween(from, to period.YearMonth) int {
+	return (to.Year-from.Year)*12 + to.Month - from.Month
+}
+
+// newFreshness lista os órgãos cujo último mês com dados é anterior ao mês
+// esperado, que é o mês atual menos o prazo de publicação. Os órgãos que nunca
+// tiveram dados também são listados. Os atrasados vêm do mais atrasado para o
+// menos atrasado.
+func newFreshness(agencies []models.Agency, monthlyInfo map[string][]models.AgencyMonthlyInfo, current period.YearMonth, delay int) freshness {
+	expected := current.AddMonths(-delay)
+	result := freshness{
+		CurrentMonth:  current.String(),
+		Delay:         delay,
+		ExpectedMonth: expected.String(),
+		Late:          []lateAgency{},
+		UpToDate:      []string{},
+	}
+	last := lastMonthsWithData(monthlyInfo)
+	for _, a := range sortedAgencies(agencies) {
+		l, hasData := last[a.ID]
+		if hasData && monthsBetween(l, expected) <= 0 {
+			result.UpToDate = append(result.UpToDate, a.ID)
+			continue
+		}
+		late := lateAgency{
+			ID:           a.ID,
+			Name:         a.Name,
+			OmbudsmanURL: a.OmbudsmanURL,
+			Reasons:      []string{},
+		}
+		if hasData {
+			lastMonth := l.String()
+			monthsLate := monthsBetween(l, expected)
+			late.LastMonth = &lastMonth
+			late.MonthsLate = &monthsLate
+		}
+		// A falha pode ser da coleta, e não do órgão.
+		for _, mi := range monthlyInfo[a.ID] {
+			m := period.YearMonth{Year: mi.Year, Month: mi.Month}
+			if (!hasData || monthsBetween(l, m) > 0) && mi.ProcInfo != nil && mi.ProcInfo.String() != "" && mi.ProcInfo.Status != 4 {
+				late.CrawlingError = true
+			}
+		}
+		if n := len(a.Collecting); n > 0 {
+			c := a.Collecting[n-1]
+			late.Reasons = append(late.Reasons, c.Description...)
+			late.ReasonsTimestamp = c.Timestamp
+		}
+		result.Late = append(result.Late, late)
+	}
+	sort.SliceStable(result.Late, func(i, j int) bool {
+		a, b := result.Late[i].MonthsLate, result.Late[j].MonthsLate
+		switch {
+		case a == nil || b == nil:
+			return a == nil && b != nil
+		default:
+			return *a > *b
+		}
+	})
+	return result
+}
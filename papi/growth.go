@@ -0,0 +1,249 @@
+package papi
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/api/tables"
+	"github.com/dadosjusbr/storage/models"
+)
+
+const (
+	growthComplete = "completo"
+	growthPartial  = "parcial"
+	growthNoData   = "sem_dados"
+)
+
+// growthPoint - valores de um órgão em um mês ou ano. count é a quantidade de
+// membros (média mensal no ano) e countTotal a soma dos membros de cada mês,
+// usada no valor por membro.
+type growthPoint struct {
+	count      float64
+	countTotal float64
+	total      float64
+	months     int
+}
+
+// growthData - valores de cada órgão em um mês ou ano.
+type growthData map[string]growthPoint
+
+// sum soma os valores dos órgãos informados.
+func (d growthData) sum(agencies []string) growthValues {
+	var v growthValues
+	var countTotal float64
+	for _, a := range agencies {
+		p := d[a]
+		v.MemberCount += p.count
+		v.Total += p.total
+		countTotal += p.countTotal
+	}
+	if countTotal > 0 {
+		v.PerCapita = v.Total / countTotal
+	}
+	return v
+}
+
+func (d growthData) agencies() []string {
+	var agencies []string
+	for a := range d {
+		agencies = append(agencies, a)
+	}
+	sort.Strings(agencies)
+	return agencies
+}
+
+// compare compara os valores dos órgãos com dados nos dois períodos. Retorna
+// nil se nenhum órgão tem dados nos dois.
+func compare(current, previous growthData, comparedTo string) *growthComparison {
+	var common []string
+	partial := false
+	for _, a := range current.agencies() {
+		p, ok := previous[a]
+		if !ok {
+			partial = true
+			continue
+		}
+		if p.months != current[a].months {
+			partial = true
+		}
+		common = append(common, a)
+	}
+	if len(common) == 0 {
+		return nil
+	}
+	if len(common) != len(previous) {
+		partial = true
+	}
+	cur, prev := current.sum(common), previous.sum(common)
+	return &growthComparison{
+		ComparedTo:  comparedTo,
+		Agencies:    len(common),
+		Partial:     partial,
+		MemberCount: newGrowthVariation(cur.MemberCount, prev.MemberCount),
+		Total:       newGrowthVariation(cur.Total, prev.Total),
+		PerCapita:   newGrowthVariation(cur.PerCapita, prev.PerCapita),
+	}
+}
+
+func newGrowthVariation(current, previous float64) growthVariation {
+	v := growthVariation{Absolute: current - previous}
+	if previous != 0 {
+		pct := v.Absolute / previous * 100
+		v.Percentage = &pct
+	}
+	return v
+}
+
+// yearPoint soma os valores mensais de um órgão em um ano.
+func yearPoint(months []growthPoint) growthPoint {
+	var p growthPoint
+	for _, m := range months {
+		p.countTotal += m.countTotal
+		p.total += m.total
+		p.months++
+	}
+	if p.months > 0 {
+		p.count = p.countTotal / float64(p.months)
+	}
+	return p
+}
+
+// compareYears compara os valores de um ano com os do anterior considerando,
+// em cada órgão, apenas os meses com dados nos dois anos. A comparação é
+// parcial se algum mês ou órgão com dados em só um dos anos foi excluído.
+func compareYears(current, previous map[string]map[int]growthPoint, comparedTo string) *growthComparison {
+	cur, prev := growthData{}, growthData{}
+	partial := false
+	for a, months := range current {
+		var c, p []growthPoint
+		for m, pt := range months {
+			if ppt, ok := previous[a][m]; ok {
+				c = append(c, pt)
+				p = append(p, ppt)
+			}
+		}
+		if len(c) != len(months) || len(p) != len(previous[a]) {
+			partial = true
+		}
+		if len(c) > 0 {
+			cur[a], prev[a] = yearPoint(c), yearPoint(p)
+		}
+	}
+	for a := range previous {
+		if _, ok := current[a]; !ok {
+			partial = true
+		}
+	}
+	cmp := compare(cur, prev, comparedTo)
+	if cmp != nil && partial {
+		cmp.Partial = true
+	}
+	return cmp
+}
+
+// newGrowth calcula as variações mês a mês e ano a ano dos órgãos no período.
+// monthlyInfo deve incluir todas as coletas atuais, inclusive as que falharam,
+// dos anos do período e do ano anterior ao início, usado nas primeiras
+// comparações. Meses com erro na coleta não têm dados. Os valores anuais somam
+// os meses com dados, cada um corrigido pelo fator do próprio mês.
+func newGrowth(agencies []string, p *period.Period, monthlyInfo []models.AgencyMonthlyInfo, deflator *tables.Deflator) growth {
+	months := map[period.YearMonth]growthData{}
+	errored := map[period.YearMonth][]string{}
+	years := map[int]map[string]map[int]growthPoint{}
+	for _, mi := range monthlyInfo {
+		m := period.YearMonth{Year: mi.Year, Month: mi.Month}
+		if mi.ProcInfo != nil && mi.ProcInfo.String() != "" {
+			// O status 4 informa que os dados estão indisponíveis, o que é tratado como ausência de dados.
+			if mi.ProcInfo.Status != 4 {
+				errored[m] = append(errored[m], mi.AgencyID)
+			}
+			continue
+		}
+		if mi.Summary == nil {
+			continue
+		}
+		if months[m] == nil {
+			months[m] = growthData{}
+		}
+		count := float64(mi.Summary.Count)
+		pt := growthPoint{
+			count:      count,
+			countTotal: count,
+			total:      deflator.Apply(mi.Summary.Remunerations.Total, mi.Year, mi.Month),
+			months:     1,
+		}
+		months[m][mi.AgencyID] = pt
+		if years[mi.Year] == nil {
+			years[mi.Year] = map[string]map[int]growthPoint{}
+		}
+		if years[mi.Year][mi.AgencyID] == nil {
+			years[mi.Year][mi.AgencyID] = map[int]growthPoint{}
+		}
+		years[mi.Year][mi.AgencyID][mi.Month] = pt
+	}
+
+	result := growth{
+		Agencies:   agencies,
+		Start:      period.YearMonth{Year: p.Start.Year(), Month: int(p.Start.Month())}.String(),
+		End:        period.YearMonth{Year: p.End.Year(), Month: int(p.End.Month())}.String(),
+		Months:     []growthMonth{},
+		Years:      []growthYear{},
+		Correction: deflator.Correction(),
+	}
+	for _, m := range p.Months() {
+		data := months[m]
+		gm := growthMonth{Month: m.String(), ErrorAgencies: errored[m]}
+		sort.Strings(gm.ErrorAgencies)
+		for _, a := range agencies {
+			if _, ok := data[a]; !ok && !slices.Contains(gm.ErrorAgencies, a) {
+				gm.MissingAgencies = append(gm.MissingAgencies, a)
+			}
+		}
+		switch {
+		case len(data) == 0:
+			gm.Status = growthNoData
+		case len(data) < len(agencies):
+			gm.Status = growthPartial
+		default:
+			gm.Status = growthComplete
+		}
+		if len(data) > 0 {
+			v := data.sum(data.agencies())
+			gm.Values = &v
+			prev := period.YearMonth{Year: m.Year, Month: m.Month - 1}
+			if prev.Month == 0 {
+				prev = period.YearMonth{Year: m.Year - 1, Month: 12}
+			}
+			gm.MonthOverMonth = compare(data, months[prev], prev.String())
+			lastYear := period.YearMonth{Year: m.Year - 1, Month: m.Month}
+			gm.YearOverYear = compare(data, months[lastYear], lastYear.String())
+		}
+		result.Months = append(result.Months, gm)
+	}
+
+	for y := p.Start.Year(); y <= p.End.Year(); y++ {
+		data := growthData{}
+		for a, ms := range years[y] {
+			var pts []growthPoint
+			for _, pt := range ms {
+				pts = append(pts, pt)
+			}
+			data[a] = yearPoint(pts)
+		}
+		gy := growthYear{Year: y}
+		for _, pt := range data {
+			gy.MonthsWithData += pt.months
+		}
+		gy.Complete = gy.MonthsWithData == 12*len(agencies)
+		if len(data) > 0 {
+			v := data.sum(data.agencies())
+			gy.Values = &v
+			gy.YearOverYear = compareYears(years[y], years[y-1], fmt.Sprint(y-1))
+		}
+		result.Years = append(result.Years, gy)
+	}
+	return result
+}
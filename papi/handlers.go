@@ -1,14 +1,24 @@
 package papi
 
 import (
+	"bytes"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/exp/slices"
 
+	"github.com/dadosjusbr/api/classification"
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/api/tables"
+	"github.com/dadosjusbr/api/taxonomy"
+	"github.com/dadosjusbr/api/webhook"
 	"github.com/dadosjusbr/storage"
 	"github.com/dadosjusbr/storage/models"
 	"github.com/labstack/echo/v4"
@@ -18,13 +28,27 @@ type handler struct {
 	client         *storage.Client
 	dadosJusURL    string
 	packageRepoURL string
+	flagsURL       string // endereço base das bandeiras das UFs
+	siteURL        string // endereço do site, usado nos links dos feeds
+	distributions  DistributionSource
+	collections    CollectionSource
+	webhooks       *webhook.Dispatcher
 }
 
-func NewHandler(client *storage.Client, dadosJusURL, packageRepoURL string) *handler {
+// NewHandler cria o handler da API pública. Se distributions for nil, as
+// respostas não incluem a distribuição das remunerações. Se collections for
+// nil, o feed geral responde 503. Se webhooks for nil, as rotas de webhooks
+// respondem 503.
+func NewHandler(client *storage.Client, dadosJusURL, packageRepoURL, flagsURL, siteURL string, distributions DistributionSource, collections CollectionSource, webhooks *webhook.Dispatcher) *handler {
 	return &handler{
 		client:         client,
 		dadosJusURL:    dadosJusURL,
 		packageRepoURL: packageRepoURL,
+		flagsURL:       flagsURL,
+		siteURL:        strings.TrimSuffix(siteURL, "/"),
+		distributions:  distributions,
+		collections:    collections,
+		webhooks:       webhooks,
 	}
 }
 
@@ -266,7 +290,7 @@ func (h handler) GetMonthlyInfo(c echo.Context) error {
 
 //	@ID				GetMonthlyInfo
 //	@Tags			public_api
-//	@Description	Busca um dado mensal de um órgão
+//	@Description	Busca um dado mensal de um órgão. Com distribuicao=true, o bloco distribuicao traz a mediana, os percentis e o coeficiente de Gini das remunerações bruta e líquida, calculados a partir dos dados de remuneração do mês. O cálculo lê o zip de remunerações, então a resposta é mais lenta.
 //	@Produce		json
 //	@Success		200		{object}	summaryzedMI	"Requisição bem sucedida"
 //	@Failure		400		{string}	string			"Parâmetros inválidos"
@@ -274,18 +298,32 @@ func (h handler) GetMonthlyInfo(c echo.Context) error {
 //	@Param			ano		path		int				true	"Ano"
 //	@Param			orgao	path		string			true	"Órgão"
 //	@Param			mes		path		int				true	"Mês"
+//	@Param			corrigir	query		string			false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base		query		string			false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Param			distribuicao	query		bool			false	"Inclui a distribuição das remunerações do mês. O padrão é false."
 //	@Router			/v2/dados/{orgao}/{ano}/{mes} [get]
 func (h handler) V2GetMonthlyInfo(c echo.Context) error {
 	year, err := strconv.Atoi(c.Param("ano"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%d inválido", year))
 	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 
 	agencyName := strings.ToLower(c.Param("orgao"))
 	month, err := strconv.Atoi(c.Param("mes"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro mes=%d inválido", month))
 	}
+	withDistribution := false
+	if qp := c.QueryParam("distribuicao"); qp != "" {
+		withDistribution, err = strconv.ParseBool(qp)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro distribuicao=%s inválido", qp))
+		}
+	}
 
 	var monthlyInfo *models.AgencyMonthlyInfo
 	monthlyInfo, _, err = h.client.Db.GetOMA(month, year, agencyName)
@@ -375,6 +413,9 @@ func (h handler) V2GetMonthlyInfo(c echo.Context) error {
 				},
 				ManualCollection: monthlyInfo.ManualCollection,
 			}
+		if withDistribution {
+			sumMI.Distribution = h.monthDistribution(agencyName, year, month)
+		}
 		//O status 4 informa que os dados estão indisponíveis. Ao removê-los dos resultados da API, garantimos que eles sejam exibidos como se não houvesse dados.
 	} else if monthlyInfo.ProcInfo.Status != 4 {
 		sumMI = summaryzedMI{
@@ -394,6 +435,7 @@ func (h handler) V2GetMonthlyInfo(c echo.Context) error {
 	} else {
 		return c.NoContent(http.StatusNoContent)
 	}
+	sumMI.correct(deflator)
 	return c.JSON(http.StatusOK, sumMI)
 }
 
@@ -406,12 +448,18 @@ func (h handler) V2GetMonthlyInfo(c echo.Context) error {
 //	@Failure		404		{string}	string			"Não existem dados para os parâmetros informados"
 //	@Param			ano		path		int				true	"Ano"
 //	@Param			orgao	path		string			true	"Órgão"
+//	@Param			corrigir	query		string			false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base		query		string			false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
 //	@Router			/v2/dados/{orgao}/{ano} [get]
 func (h handler) GetMonthlyInfosByYear(c echo.Context) error {
 	year, err := strconv.Atoi(c.Param("ano"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%d inválido", year))
 	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 
 	agencyName := strings.ToLower(c.Param("orgao"))
 	var monthlyInfo map[string][]models.AgencyMonthlyInfo
@@ -528,9 +576,54 @@ func (h handler) GetMonthlyInfosByYear(c echo.Context) error {
 			}
 		}
 	}
+	for i := range sumMI {
+		sumMI[i].correct(deflator)
+	}
 	return c.JSON(http.StatusOK, sumMI)
 }
 
+//	@ID				GetAgencyYearDistribution
+//	@Tags			public_api
+//	@Description	Busca a distribuição das remunerações bruta e líquida de um órgão em um ano: mediana, percentis (p10, p25, p75, p90 e p99) e coeficiente de Gini. Cada par membro/mês com dados no ano é uma observação.
+//	@Produce		json
+//	@Param			orgao						path		string					true	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Param			ano							path		int						true	"Ano"
+//	@Param			corrigir					query		string					false	"Índice para correção monetária dos valores. Valor aceito: ipca. A remuneração de cada par membro/mês é corrigida antes do cálculo das estatísticas."
+//	@Param			base						query		string					false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200							{object}	agencyYearDistribution	"Requisição bem sucedida."
+//	@Failure		400							{string}	string					"Parâmetros inválidos."
+//	@Failure		404							{string}	string					"Não existem dados para os parâmetros informados."
+//	@Failure		500							{string}	string					"Erro interno do servidor."
+//	@Router			/v2/distribuicao/{orgao}/{ano}	[get]
+func (h handler) V2GetAgencyYearDistribution(c echo.Context) error {
+	year, err := strconv.Atoi(c.Param("ano"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%s inválido", c.Param("ano")))
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	agencyName := strings.ToLower(c.Param("orgao"))
+	if h.distributions == nil {
+		return c.JSON(http.StatusNotFound, "Não existem dados para os parâmetros informados")
+	}
+	d, err := h.distributions.AgencyYearDistribution(agencyName, year, deflator)
+	if err != nil {
+		log.Printf("[distribution] error getting distribution (orgao:%s ano:%d): %q", agencyName, year, err)
+		return c.JSON(http.StatusInternalServerError, "Erro calculando a distribuição das remunerações")
+	}
+	if d == nil {
+		return c.JSON(http.StatusNotFound, "Não existem dados para os parâmetros informados")
+	}
+	return c.JSON(http.StatusOK, agencyYearDistribution{
+		AgencyID:     agencyName,
+		Year:         year,
+		Distribution: newRemunerationDistribution(d),
+		Correction:   deflator.Correction(),
+	})
+}
+
 //	@ID				GetAggregateIndexesWithParams
 //	@Tags			public_api
 //	@Description	Busca as informações de índices de um grupo ou órgão específico.
@@ -549,24 +642,14 @@ func (h handler) V2GetAggregateIndexesWithParams(c echo.Context) error {
 	agregado := c.QueryParam("agregado")
 	detalhe := c.QueryParam("detalhe")
 
-	groupMap := map[string]string{
-		"justica-eleitoral":    "Eleitoral",
-		"ministerios-publicos": "Ministério",
-		"justica-estadual":     "Estadual",
-		"justica-do-trabalho":  "Trabalho",
-		"justica-federal":      "Federal",
-		"justica-militar":      "Militar",
-		"justica-superior":     "Superior",
-		"conselhos-de-justica": "Conselho",
-	}
-
 	// porJurisdicao tbm será usada para verificar a possibilidade de uma BadRequest
 	var porJurisdicao bool
 
 	// Verificamos se o parâmetro é válido.
 	if param == "grupo" {
-		if _, porJurisdicao = groupMap[valor]; porJurisdicao {
-			valor = groupMap[valor]
+		if g, ok := taxonomy.GroupBySlug(valor); ok {
+			porJurisdicao = true
+			valor = g.Jurisdiction
 		} else {
 			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Jurisdição inválida: %s.", valor))
 		}
@@ -805,9 +888,15 @@ func (h handler) V2GetAggregateIndexes(c echo.Context) error {
 //	@Success		200					{object}	allAgencyInformation	"Requisição bem sucedida."
 //	@Failure		400					{string}	string					"Requisição inválida."
 //	@Param			orgao				path		string					true	"órgão"
+//	@Param			corrigir			query		string					false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base				query		string					false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
 //	@Router			/v2/dados/{orgao} 	[get]
 func (h handler) V2GetAllAgencyInformation(c echo.Context) error {
 	agency := strings.ToLower(c.Param("orgao"))
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 
 	ag, err := h.client.Db.GetAgency(agency)
 	if err != nil {
@@ -895,6 +984,9 @@ func (h handler) V2GetAllAgencyInformation(c echo.Context) error {
 		aggregateCompletenessScore += c.Score.CompletenessScore
 		aggregateEasinessScore += c.Score.EasinessScore
 	}
+	for i := range result {
+		result[i].correct(deflator)
+	}
 
 	var collect []collecting
 	for _, c := range ag.Collecting {
@@ -926,6 +1018,960 @@ func (h handler) V2GetAllAgencyInformation(c echo.Context) error {
 	return c.JSON(http.StatusOK, agencyInfo)
 }
 
+//	@ID				GetItemSeries
+//	@Tags			public_api
+//	@Description	Busca as séries mensais das rubricas (resumo_rubricas) de um órgão, com o total e o valor por membro de cada mês.
+//	@Produce		json,text/csv
+//	@Success		200						{object}	itemSeries	"Requisição bem sucedida."
+//	@Failure		400						{string}	string		"Parâmetros inválidos."
+//	@Failure		404						{string}	string		"Órgão não encontrado."
+//	@Failure		500						{string}	string		"Erro interno do servidor."
+//	@Param			orgao					path		string		true	"ID do órgão. Exemplos: tjal, tjpb, mppb."
+//	@Param			inicio					query		string		true	"Mês inicial, no formato AAAA-MM. Exemplo: 2018-01. O período tem no máximo 120 meses."
+//	@Param			fim						query		string		true	"Mês final, no formato AAAA-MM. Exemplo: 2023-12."
+//	@Param			rubricas				query		string		false	"Rubricas separadas por vírgula. O padrão são todas. Exemplo: auxilio_saude,licenca_premio."
+//	@Param			formato					query		string		false	"Formato da resposta: json (padrão) ou csv."
+//	@Param			corrigir				query		string		false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base					query		string		false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Router			/v2/rubricas/{orgao}	[get]
+func (h handler) V2GetItemSeries(c echo.Context) error {
+	agencyID := strings.ToLower(c.Param("orgao"))
+	p, err := period.New(c.QueryParam("inicio"), c.QueryParam("fim"), maxPeriodMonths)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	fields, err := selectItemFields(c.QueryParam("rubricas"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	format := c.QueryParam("formato")
+	if format != "" && format != "json" && format != "csv" {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro formato=%s inválido", format))
+	}
+	if _, err := h.client.Db.GetAgency(agencyID); err != nil {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Órgão não encontrado: %s", strings.ToUpper(agencyID)))
+	}
+
+	var monthlyInfo []models.AgencyMonthlyInfo
+	for year := p.Start.Year(); year <= p.End.Year(); year++ {
+		mis, err := h.client.Db.GetMonthlyInfo([]models.Agency{{ID: agencyID}}, year)
+		if err != nil {
+			log.Printf("[item series] error getting monthly info (orgao:%s ano:%d): %q", agencyID, year, err)
+			return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais do órgão")
+		}
+		monthlyInfo = append(monthlyInfo, mis[agencyID]...)
+	}
+	series := newItemSeries(agencyID, p, fields, monthlyInfo, deflator)
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		if err := writeItemSeriesCSV(&buf, series); err != nil {
+			log.Printf("[item series] error writing csv (orgao:%s): %q", agencyID, err)
+			return c.JSON(http.StatusInternalServerError, "Erro gerando o csv")
+		}
+		c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=dadosjusbr-rubricas-%s.csv", agencyID))
+		return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	}
+	return c.JSON(http.StatusOK, series)
+}
+
+// Número máximo de meses dos períodos informados por inicio e fim. As séries têm
+// um valor por mês, então o limite também limita o tamanho das respostas.
+const maxPeriodMonths = 120
+
+//	@ID				GetItemDictionary
+//	@Tags			public_api
+//	@Description	Retorna o dicionário versionado usado para classificar as rubricas (detalhamento_contracheque) em categorias.
+//	@Produce		json
+//	@Success		200							{object}	classification.Dictionary	"Requisição bem sucedida."
+//	@Router			/v2/rubricas/dicionario 	[get]
+func (h handler) V2GetItemDictionary(c echo.Context) error {
+	return c.JSON(http.StatusOK, classification.Default())
+}
+
+//	@ID				GetAgencyGrowth
+//	@Tags			public_api
+//	@Description	Calcula a variação absoluta e percentual da remuneração bruta total, da remuneração bruta por membro e da quantidade de membros de um órgão, mês a mês e ano a ano. Meses sem dados ou com erro na coleta são sinalizados e não são comparados com outros meses; a variação mensal sempre compara com o mês imediatamente anterior. A variação anual de cada ano compara apenas os meses com dados nos dois anos.
+//	@Produce		json
+//	@Param			orgao						path		string	true	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Param			inicio						query		string	true	"Mês inicial, no formato AAAA-MM. Exemplo: 2023-01. O período tem no máximo 120 meses."
+//	@Param			fim							query		string	true	"Mês final, no formato AAAA-MM. Exemplo: 2023-12."
+//	@Param			corrigir					query		string	false	"Índice para correção monetária dos valores, para calcular o crescimento real. Valor aceito: ipca."
+//	@Param			base						query		string	false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200							{object}	growth	"Requisição bem sucedida."
+//	@Failure		400							{string}	string	"Parâmetros inválidos."
+//	@Failure		404							{string}	string	"Órgão não encontrado."
+//	@Failure		500							{string}	string	"Erro interno do servidor."
+//	@Router			/v2/crescimento/{orgao}		[get]
+func (h handler) V2GetAgencyGrowth(c echo.Context) error {
+	agencyID := strings.ToLower(c.Param("orgao"))
+	if _, err := h.client.Db.GetAgency(agencyID); err != nil {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Órgão não encontrado: %s", strings.ToUpper(agencyID)))
+	}
+	return h.growth(c, "", []string{agencyID})
+}
+
+//	@ID				GetGroupGrowth
+//	@Tags			public_api
+//	@Description	Calcula a variação absoluta e percentual da remuneração bruta total, da remuneração bruta por membro e da quantidade de membros de um grupo de órgãos, mês a mês e ano a ano. As variações consideram apenas os órgãos com dados nos dois períodos comparados; os órgãos sem dados ou com erro na coleta são listados em cada mês. A variação anual de cada ano compara, em cada órgão, apenas os meses com dados nos dois anos.
+//	@Produce		json
+//	@Param			grupo						path		string	true	"Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos."
+//	@Param			inicio						query		string	true	"Mês inicial, no formato AAAA-MM. Exemplo: 2023-01. O período tem no máximo 120 meses."
+//	@Param			fim							query		string	true	"Mês final, no formato AAAA-MM. Exemplo: 2023-12."
+//	@Param			corrigir					query		string	false	"Índice para correção monetária dos valores, para calcular o crescimento real. Valor aceito: ipca."
+//	@Param			base						query		string	false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200							{object}	growth	"Requisição bem sucedida."
+//	@Failure		400							{string}	string	"Parâmetros inválidos."
+//	@Failure		404							{string}	string	"Grupo não encontrado."
+//	@Failure		500							{string}	string	"Erro interno do servidor."
+//	@Router			/v2/crescimento/grupo/{grupo}	[get]
+func (h handler) V2GetGroupGrowth(c echo.Context) error {
+	group := strings.ToLower(c.Param("grupo"))
+	g, ok := taxonomy.GroupBySlug(group)
+	if !ok {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s", group))
+	}
+	strAgencies, err := h.client.Db.GetOPJ(g.Jurisdiction)
+	if err != nil {
+		log.Printf("[growth] error getting agencies of group '%s': %q", g.Jurisdiction, err)
+		return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Erro buscando os órgãos do grupo %s", group))
+	}
+	var agencies []string
+	for _, a := range strAgencies {
+		agencies = append(agencies, a.ID)
+	}
+	return h.growth(c, group, agencies)
+}
+
+func (h handler) growth(c echo.Context, group string, agencies []string) error {
+	p, err := period.New(c.QueryParam("inicio"), c.QueryParam("fim"), maxPeriodMonths)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	// O ano anterior ao início é usado nas primeiras comparações.
+	collections, err := h.agencyCollections(agencies, p.Start.Year()-1, p.End.Year())
+	if err != nil {
+		log.Printf("[growth] error getting collections (orgaos:%v): %q", agencies, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais")
+	}
+	var monthlyInfo []models.AgencyMonthlyInfo
+	for _, a := range agencies {
+		monthlyInfo = append(monthlyInfo, collections[a]...)
+	}
+	g := newGrowth(agencies, p, monthlyInfo, deflator)
+	g.Group = group
+	return c.JSON(http.StatusOK, g)
+}
+
+// agencyCollections retorna as coletas atuais dos órgãos nos anos de
+// firstYear a lastYear, inclusive as que falharam. GetMonthlyInfo descarta as
+// coletas com procinfo, então os meses com erro na coleta são lidos daqui.
+func (h handler) agencyCollections(agencies []string, firstYear, lastYear int) (map[string][]models.AgencyMonthlyInfo, error) {
+	collections := map[string][]models.AgencyMonthlyInfo{}
+	for _, a := range agencies {
+		all, err := h.client.Db.GetAllAgencyCollection(a)
+		if err != nil {
+			return nil, fmt.Errorf("error getting collections of %s: %w", a, err)
+		}
+		for _, mi := range all {
+			if mi.Year >= firstYear && mi.Year <= lastYear {
+				collections[a] = append(collections[a], mi)
+			}
+		}
+	}
+	return collections, nil
+}
+
+//	@ID				GetAnomalies
+//	@Tags			public_api
+//	@Description	Lista os órgãos/meses cuja remuneração bruta total, remuneração bruta por membro ou rubricas do resumo de rubricas se desviam fortemente da linha de base do próprio órgão. A linha de base é a mediana dos meses anteriores (janela móvel) ou, nos meses com picos sazonais esperados, como a gratificação natalina em dezembro, a mediana do mesmo mês nos anos anteriores. A pontuação é um escore z robusto, baseado no desvio absoluto mediano. Anomalias na remuneração bruta indicam a rubrica que mais contribuiu para o desvio.
+//	@Produce		json
+//	@Param			orgao			query		string		false	"ID do órgão. Exemplos: tjal, tjba, mppb. Informe orgao ou grupo."
+//	@Param			grupo			query		string		false	"Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos."
+//	@Param			inicio			query		string		true	"Mês inicial, no formato AAAA-MM. Exemplo: 2023-01. O período tem no máximo 120 meses."
+//	@Param			fim				query		string		true	"Mês final, no formato AAAA-MM. Exemplo: 2023-12."
+//	@Param			limiar			query		number		false	"Pontuação mínima, em módulo, para um valor ser considerado anômalo. Padrão: 3.5."
+//	@Param			janela			query		int			false	"Quantidade de meses anteriores na linha de base móvel, entre 6 e 36. Padrão: 12."
+//	@Param			corrigir		query		string		false	"Índice para correção monetária dos valores antes da comparação. Valor aceito: ipca."
+//	@Param			base			query		string		false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200				{object}	anomalies	"Requisição bem sucedida."
+//	@Failure		400				{string}	string		"Parâmetros inválidos."
+//	@Failure		404				{string}	string		"Órgão ou grupo não encontrado."
+//	@Failure		500				{string}	string		"Erro interno do servidor."
+//	@Router			/v2/anomalias	[get]
+func (h handler) V2GetAnomalies(c echo.Context) error {
+	p, err := period.New(c.QueryParam("inicio"), c.QueryParam("fim"), maxPeriodMonths)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	threshold := defaultAnomalyThreshold
+	if qp := c.QueryParam("limiar"); qp != "" {
+		threshold, err = strconv.ParseFloat(qp, 64)
+		if err != nil || threshold <= 0 {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro limiar=%s inválido", qp))
+		}
+	}
+	window := defaultAnomalyWindow
+	if qp := c.QueryParam("janela"); qp != "" {
+		window, err = strconv.Atoi(qp)
+		if err != nil || window < minAnomalyWindow || window > maxAnomalyWindow {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro janela=%s inválido. Use um valor entre %d e %d", qp, minAnomalyWindow, maxAnomalyWindow))
+		}
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	agencyID := strings.ToLower(c.QueryParam("orgao"))
+	group := strings.ToLower(c.QueryParam("grupo"))
+	var agencies []string
+	switch {
+	case agencyID != "" && group != "":
+		return c.JSON(http.StatusBadRequest, "Informe apenas um dos parâmetros orgao ou grupo")
+	case agencyID != "":
+		if _, err := h.client.Db.GetAgency(agencyID); err != nil {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Órgão não encontrado: %s", strings.ToUpper(agencyID)))
+		}
+		agencies = []string{agencyID}
+	case group != "":
+		g, ok := taxonomy.GroupBySlug(group)
+		if !ok {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s", group))
+		}
+		strAgencies, err := h.client.Db.GetOPJ(g.Jurisdiction)
+		if err != nil {
+			log.Printf("[anomalies] error getting agencies of group '%s': %q", g.Jurisdiction, err)
+			return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Erro buscando os órgãos do grupo %s", group))
+		}
+		for _, a := range strAgencies {
+			agencies = append(agencies, a.ID)
+		}
+	default:
+		return c.JSON(http.StatusBadRequest, "Informe o parâmetro orgao ou grupo")
+	}
+
+	var strAgencies []models.Agency
+	for _, a := range agencies {
+		strAgencies = append(strAgencies, models.Agency{ID: a})
+	}
+	// As linhas de base usam até três anos antes do início do período.
+	var monthlyInfo []models.AgencyMonthlyInfo
+	for year := p.Start.Year() - seasonalYears; year <= p.End.Year(); year++ {
+		mis, err := h.client.Db.GetMonthlyInfo(strAgencies, year)
+		if err != nil {
+			log.Printf("[anomalies] error getting monthly info (orgaos:%v ano:%d): %q", agencies, year, err)
+			return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais")
+		}
+		for _, a := range agencies {
+			monthlyInfo = append(monthlyInfo, mis[a]...)
+		}
+	}
+	months := p.Months()
+	return c.JSON(http.StatusOK, anomalies{
+		Group:      group,
+		Agencies:   agencies,
+		Start:      months[0].String(),
+		End:        months[len(months)-1].String(),
+		Threshold:  threshold,
+		Window:     window,
+		Anomalies:  findAnomalies(p, monthlyInfo, threshold, window, deflator),
+		Correction: deflator.Correction(),
+	})
+}
+
+//	@ID				CompareAgencies
+//	@Tags			public_api
+//	@Description	Retorna as séries mensais de vários órgãos alinhadas aos meses do período: totais de remuneração, remuneração bruta por membro, quantidade de membros, resumo de rubricas e índices de transparência. Os meses sem dados ou com erro na coleta ficam com valores nulos e são listados por órgão e em meses_incompletos.
+//	@Produce		json
+//	@Param			orgaos			query		string		true	"IDs dos órgãos, separados por vírgula (no máximo 10). Exemplo: tjpb,tjpe,tjrn."
+//	@Param			inicio			query		string		true	"Mês inicial, no formato AAAA-MM. Exemplo: 2020-01. O período tem no máximo 120 meses."
+//	@Param			fim				query		string		true	"Mês final, no formato AAAA-MM. Exemplo: 2023-12."
+//	@Param			corrigir		query		string		false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base			query		string		false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200				{object}	comparison	"Requisição bem sucedida."
+//	@Failure		400				{string}	string		"Parâmetros inválidos."
+//	@Failure		404				{string}	string		"Órgão não encontrado."
+//	@Failure		500				{string}	string		"Erro interno do servidor."
+//	@Router			/v2/comparar	[get]
+func (h handler) V2CompareAgencies(c echo.Context) error {
+	var agencies []string
+	for _, a := range strings.Split(c.QueryParam("orgaos"), ",") {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a != "" && !slices.Contains(agencies, a) {
+			agencies = append(agencies, a)
+		}
+	}
+	if len(agencies) == 0 || len(agencies) > maxComparedAgencies {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Informe de 1 a %d órgãos no parâmetro orgaos", maxComparedAgencies))
+	}
+	p, err := period.New(c.QueryParam("inicio"), c.QueryParam("fim"), maxPeriodMonths)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	for _, a := range agencies {
+		if _, err := h.client.Db.GetAgency(a); err != nil {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Órgão não encontrado: %s", strings.ToUpper(a)))
+		}
+	}
+	monthlyInfo, err := h.agencyCollections(agencies, p.Start.Year(), p.End.Year())
+	if err != nil {
+		log.Printf("[compare agencies] error getting collections (orgaos:%v): %q", agencies, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais")
+	}
+	return c.JSON(http.StatusOK, newComparison(agencies, p, monthlyInfo, deflator))
+}
+
+//	@ID				GetRemunerationRanking
+//	@Tags			public_api
+//	@Description	Ordena os órgãos por uma métrica do resumo anual, do maior para o menor valor, com a posição, o valor, o percentil e a variação de posição em relação ao ano anterior. Órgãos com menos de 12 meses de dados no ano são sinalizados e, por padrão, ficam sem posição no fim da lista, pois seus totais não são comparáveis aos dos demais.
+//	@Produce		json
+//	@Param			ano						query		int		true	"Ano. Exemplo: 2023."
+//	@Param			metrica					query		string	false	"Métrica do resumo anual. Padrão: remuneracoes_por_membro. Valores aceitos: descontos, descontos_por_membro, num_membros, outras_remuneracoes, outras_remuneracoes_por_membro, remuneracao_base, remuneracao_base_por_membro, remuneracoes, remuneracoes_por_membro."
+//	@Param			grupo					query		string	false	"Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos. Se omitido, todos os órgãos são ordenados."
+//	@Param			incluir_incompletos		query		bool	false	"Se verdadeiro, os órgãos com ano incompleto também recebem posição. Padrão: falso."
+//	@Success		200						{object}	ranking	"Requisição bem sucedida."
+//	@Failure		400						{string}	string	"Parâmetros inválidos."
+//	@Failure		404						{string}	string	"Grupo não encontrado."
+//	@Failure		500						{string}	string	"Erro interno do servidor."
+//	@Router			/v2/ranking/remuneracao	[get]
+func (h handler) V2GetRemunerationRanking(c echo.Context) error {
+	year, err := strconv.Atoi(c.QueryParam("ano"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%s inválido", c.QueryParam("ano")))
+	}
+	metric := c.QueryParam("metrica")
+	if metric == "" {
+		metric = "remuneracoes_por_membro"
+	}
+	if _, ok := rankingMetrics[metric]; !ok {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro metrica=%s inválido. Valores aceitos: %s", metric, rankingMetricNames()))
+	}
+	includeIncomplete := false
+	if qp := c.QueryParam("incluir_incompletos"); qp != "" {
+		includeIncomplete, err = strconv.ParseBool(qp)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro incluir_incompletos=%s inválido", qp))
+		}
+	}
+
+	group := strings.ToLower(c.QueryParam("grupo"))
+	var agencies []models.Agency
+	if group != "" {
+		g, ok := taxonomy.GroupBySlug(group)
+		if !ok {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s", group))
+		}
+		agencies, err = h.client.Db.GetOPJ(g.Jurisdiction)
+	} else {
+		agencies, err = h.client.Db.GetAllAgencies()
+	}
+	if err != nil {
+		log.Printf("[ranking] error getting agencies (grupo:%s): %q", group, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	summaries := map[string][]models.AnnualSummary{}
+	for _, a := range agencies {
+		s, err := h.client.Db.GetAnnualSummary(a.ID)
+		if err != nil {
+			log.Printf("[ranking] error getting annual summary (orgao:%s): %q", a.ID, err)
+			return c.JSON(http.StatusInternalServerError, "Erro buscando os dados anuais")
+		}
+		summaries[a.ID] = s
+	}
+	r := newRanking(agencies, summaries, year, metric, includeIncomplete)
+	r.Group = group
+	return c.JSON(http.StatusOK, r)
+}
+
+//	@ID				GetStateSummary
+//	@Tags			public_api
+//	@Description	Soma os resumos anuais de todos os órgãos de uma UF, ano a ano, com os totais de cada órgão e a cobertura: quantos órgãos têm dados no ano e quais têm menos de 12 meses de dados. O número de membros do ano é a soma das médias mensais dos órgãos.
+//	@Produce		json
+//	@Param			uf					path		string				true	"Sigla da UF. Exemplos: PB, SP."
+//	@Param			corrigir			query		string				false	"Índice para correção monetária dos valores. Valor aceito: ipca. Os valores anuais de cada órgão são corrigidos pela média dos fatores dos seus meses com dados no ano."
+//	@Param			base				query		string				false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200					{object}	aggregateSummary	"Requisição bem sucedida."
+//	@Failure		400					{string}	string				"Parâmetros inválidos."
+//	@Failure		404					{string}	string				"UF não encontrada ou sem órgãos."
+//	@Failure		500					{string}	string				"Erro interno do servidor."
+//	@Router			/v2/uf/{uf}/resumo	[get]
+func (h handler) V2GetStateSummary(c echo.Context) error {
+	uf, ok := taxonomy.UFByCode(c.Param("uf"))
+	if !ok {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("UF não encontrada: %s", c.Param("uf")))
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	agencies, err := h.client.Db.GetAgenciesByUF(uf.Code)
+	if err != nil {
+		log.Printf("[state summary] error getting agencies (uf:%s): %q", uf.Code, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	if len(agencies) == 0 {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Nenhum órgão encontrado para a UF: %s", uf.Code))
+	}
+	summaries := map[string][]models.AnnualSummary{}
+	for _, a := range agencies {
+		s, err := h.client.Db.GetAnnualSummary(a.ID)
+		if err != nil {
+			log.Printf("[state summary] error getting annual summary (orgao:%s): %q", a.ID, err)
+			return c.JSON(http.StatusInternalServerError, "Erro buscando os dados anuais")
+		}
+		summaries[a.ID] = s
+	}
+	// Os resumos anuais são corrigidos pela média dos fatores dos meses com
+	// dados, que são os meses que compõem os totais do ano.
+	monthsWithData := map[string]map[int][]int{}
+	if deflator != nil {
+		for _, a := range agencies {
+			collections, err := h.client.Db.GetAllAgencyCollection(a.ID)
+			if err != nil {
+				log.Printf("[state summary] error getting collections (orgao:%s): %q", a.ID, err)
+				return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais")
+			}
+			monthsWithData[a.ID] = map[int][]int{}
+			for _, mi := range collections {
+				if mi.Summary != nil && (mi.ProcInfo == nil || mi.ProcInfo.String() == "") {
+					monthsWithData[a.ID][mi.Year] = append(monthsWithData[a.ID][mi.Year], mi.Month)
+				}
+			}
+		}
+	}
+	s := newAggregateSummary(agencies, summaries, monthsWithData, deflator)
+	s.UF = uf.Code
+	return c.JSON(http.StatusOK, s)
+}
+
+//	@ID				GetStateYear
+//	@Tags			public_api
+//	@Description	Soma os totais mensais, o número de membros e o resumo de rubricas de todos os órgãos de uma UF em cada mês do ano, com os totais do ano de cada órgão e a cobertura de cada mês: quantos órgãos têm dados, quais não têm e quais tiveram erro na coleta. Meses sem dados ou com erro na coleta ficam fora da soma.
+//	@Produce		json
+//	@Param			uf					path		string		true	"Sigla da UF. Exemplos: PB, SP."
+//	@Param			ano					path		int			true	"Ano"
+//	@Param			corrigir			query		string		false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base				query		string		false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200					{object}	aggregate	"Requisição bem sucedida."
+//	@Failure		400					{string}	string		"Parâmetros inválidos."
+//	@Failure		404					{string}	string		"UF não encontrada ou sem órgãos."
+//	@Failure		500					{string}	string		"Erro interno do servidor."
+//	@Router			/v2/uf/{uf}/{ano}	[get]
+func (h handler) V2GetStateYear(c echo.Context) error {
+	uf, ok := taxonomy.UFByCode(c.Param("uf"))
+	if !ok {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("UF não encontrada: %s", c.Param("uf")))
+	}
+	year, err := strconv.Atoi(c.Param("ano"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%s inválido", c.Param("ano")))
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	agencies, err := h.client.Db.GetAgenciesByUF(uf.Code)
+	if err != nil {
+		log.Printf("[state year] error getting agencies (uf:%s): %q", uf.Code, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	if len(agencies) == 0 {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Nenhum órgão encontrado para a UF: %s", uf.Code))
+	}
+	monthlyInfo, err := h.agencyCollections(agencyIDs(agencies), year, year)
+	if err != nil {
+		log.Printf("[state year] error getting collections (uf:%s ano:%d): %q", uf.Code, year, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais")
+	}
+	a := newAggregateYear(agencies, year, monthlyInfo, deflator)
+	a.UF = uf.Code
+	return c.JSON(http.StatusOK, a)
+}
+
+//	@ID				GetGroupYear
+//	@Tags			public_api
+//	@Description	Soma os totais mensais, o número de membros e o resumo de rubricas dos órgãos de um grupo em cada mês do ano, com os valores por membro, os totais do ano de cada órgão e a cobertura de cada mês: quantos órgãos têm dados, quais não têm e quais tiveram erro na coleta. Meses sem dados ou com erro na coleta ficam fora da soma.
+//	@Produce		json
+//	@Param			grupo					path		string		true	"Grupo de órgãos. Exemplos: justica-do-trabalho, ministerios-publicos."
+//	@Param			ano						path		int			true	"Ano"
+//	@Param			corrigir				query		string		false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base					query		string		false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200						{object}	aggregate	"Requisição bem sucedida."
+//	@Failure		400						{string}	string		"Parâmetros inválidos."
+//	@Failure		404						{string}	string		"Grupo não encontrado."
+//	@Failure		500						{string}	string		"Erro interno do servidor."
+//	@Router			/v2/grupo/{grupo}/{ano}	[get]
+func (h handler) V2GetGroupYear(c echo.Context) error {
+	group := strings.ToLower(c.Param("grupo"))
+	g, ok := taxonomy.GroupBySlug(group)
+	if !ok {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s", group))
+	}
+	year, err := strconv.Atoi(c.Param("ano"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%s inválido", c.Param("ano")))
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	agencies, err := h.client.Db.GetOPJ(g.Jurisdiction)
+	if err != nil {
+		log.Printf("[group year] error getting agencies (grupo:%s): %q", group, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	monthlyInfo, err := h.agencyCollections(agencyIDs(agencies), year, year)
+	if err != nil {
+		log.Printf("[group year] error getting collections (grupo:%s ano:%d): %q", group, year, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais")
+	}
+	a := newAggregateYear(agencies, year, monthlyInfo, deflator)
+	a.Group = group
+	return c.JSON(http.StatusOK, a)
+}
+
+//	@ID				GetGroups
+//	@Tags			public_api
+//	@Description	Lista os grupos de órgãos (jurisdições), com o nome usado nas URLs, o nome de exibição e o número de órgãos de cada grupo, total e por entidade.
+//	@Produce		json
+//	@Success		200			{object}	[]groupInfo	"Requisição bem sucedida."
+//	@Failure		500			{string}	string		"Erro interno do servidor."
+//	@Router			/v2/grupos	[get]
+func (h handler) V2GetGroups(c echo.Context) error {
+	agencies, err := h.client.Db.GetAllAgencies()
+	if err != nil {
+		log.Printf("[groups] error getting agencies: %q", err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	return c.JSON(http.StatusOK, newGroupInfos(agencies))
+}
+
+//	@ID				GetUFs
+//	@Tags			public_api
+//	@Description	Lista as unidades federativas, com a sigla, o nome usado nas URLs, o nome, a região, a bandeira e o número de órgãos de cada UF, total e por entidade.
+//	@Produce		json
+//	@Success		200			{object}	[]ufInfo	"Requisição bem sucedida."
+//	@Failure		500			{string}	string		"Erro interno do servidor."
+//	@Router			/v2/ufs		[get]
+func (h handler) V2GetUFs(c echo.Context) error {
+	agencies, err := h.client.Db.GetAllAgencies()
+	if err != nil {
+		log.Printf("[ufs] error getting agencies: %q", err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	return c.JSON(http.StatusOK, newUFInfos(agencies, h.flagsURL))
+}
+
+//	@ID				GetCoverage
+//	@Tags			public_api
+//	@Description	Monta a matriz órgão × mês da situação das coletas no período. Cada célula é ok, coleta_manual, erro_coleta (com o status informado pelo coletor), indisponivel (status 4: dados indisponíveis ou malformados) ou nao_coletado. Inclui os totais de cada órgão, de cada mês e da matriz.
+//	@Produce		json
+//	@Param			inicio			query		string		true	"Mês inicial, no formato AAAA-MM. O período tem no máximo 120 meses."
+//	@Param			fim				query		string		true	"Mês final, no formato AAAA-MM."
+//	@Param			grupo			query		string		false	"Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos. Se omitido, todos os órgãos são incluídos."
+//	@Success		200				{object}	coverage	"Requisição bem sucedida."
+//	@Failure		400				{string}	string		"Parâmetros inválidos."
+//	@Failure		404				{string}	string		"Grupo não encontrado."
+//	@Failure		500				{string}	string		"Erro interno do servidor."
+//	@Router			/v2/cobertura	[get]
+func (h handler) V2GetCoverage(c echo.Context) error {
+	p, err := period.New(c.QueryParam("inicio"), c.QueryParam("fim"), maxPeriodMonths)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	group := strings.ToLower(c.QueryParam("grupo"))
+	var agencies []models.Agency
+	if group != "" {
+		g, ok := taxonomy.GroupBySlug(group)
+		if !ok {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s", group))
+		}
+		agencies, err = h.client.Db.GetOPJ(g.Jurisdiction)
+	} else {
+		agencies, err = h.client.Db.GetAllAgencies()
+	}
+	if err != nil {
+		log.Printf("[coverage] error getting agencies (grupo:%s): %q", group, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	monthlyInfo, err := h.agencyCollections(agencyIDs(agencies), p.Start.Year(), p.End.Year())
+	if err != nil {
+		log.Printf("[coverage] error getting collections (grupo:%s): %q", group, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais")
+	}
+	cov := newCoverage(agencies, p, monthlyInfo)
+	cov.Group = group
+	return c.JSON(http.StatusOK, cov)
+}
+
+//	@ID				GetCrawlingErrors
+//	@Tags			public_api
+//	@Description	Agrupa as coletas com erro no período por órgão, status, repositório e versão do coletor e mensagem de erro normalizada (última linha da saída de erro, sem endereços e números), com a quantidade de ocorrências, a primeira e a última ocorrência e um exemplo da saída de erro. Os grupos vêm do mais frequente para o menos frequente. Inclui as coletas com status 4 (dados indisponíveis ou malformados).
+//	@Produce		json
+//	@Param			orgao		query		string			false	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Param			grupo		query		string			false	"Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos. Se orgao e grupo forem omitidos, todos os órgãos são incluídos."
+//	@Param			inicio		query		string			true	"Mês inicial, no formato AAAA-MM. O período tem no máximo 120 meses."
+//	@Param			fim			query		string			true	"Mês final, no formato AAAA-MM."
+//	@Success		200			{object}	crawlingErrors	"Requisição bem sucedida."
+//	@Failure		400			{string}	string			"Parâmetros inválidos."
+//	@Failure		404			{string}	string			"Órgão ou grupo não encontrado."
+//	@Failure		500			{string}	string			"Erro interno do servidor."
+//	@Router			/v2/erros	[get]
+func (h handler) V2GetCrawlingErrors(c echo.Context) error {
+	p, err := period.New(c.QueryParam("inicio"), c.QueryParam("fim"), maxPeriodMonths)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	agencyID := strings.ToLower(c.QueryParam("orgao"))
+	group := strings.ToLower(c.QueryParam("grupo"))
+	var agencies []models.Agency
+	switch {
+	case agencyID != "" && group != "":
+		return c.JSON(http.StatusBadRequest, "Informe apenas um dos parâmetros orgao ou grupo")
+	case agencyID != "":
+		if _, err := h.client.Db.GetAgency(agencyID); err != nil {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Órgão não encontrado: %s", strings.ToUpper(agencyID)))
+		}
+		agencies = []models.Agency{{ID: agencyID}}
+	case group != "":
+		g, ok := taxonomy.GroupBySlug(group)
+		if !ok {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s", group))
+		}
+		agencies, err = h.client.Db.GetOPJ(g.Jurisdiction)
+	default:
+		agencies, err = h.client.Db.GetAllAgencies()
+	}
+	if err != nil {
+		log.Printf("[crawling errors] error getting agencies (grupo:%s): %q", group, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	collections, err := h.agencyCollections(agencyIDs(agencies), p.Start.Year(), p.End.Year())
+	if err != nil {
+		log.Printf("[crawling errors] error getting collections (orgao:%s grupo:%s): %q", agencyID, group, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais")
+	}
+	var monthlyInfo []models.AgencyMonthlyInfo
+	for _, a := range agencies {
+		monthlyInfo = append(monthlyInfo, collections[a.ID]...)
+	}
+	errs := newCrawlingErrors(p, monthlyInfo)
+	errs.Agency = agencyID
+	errs.Group = group
+	return c.JSON(http.StatusOK, errs)
+}
+
+//	@ID				GetCrawlerHistory
+//	@Tags			public_api
+//	@Description	Lista as versões do coletor e do parser usadas nas coletas de um órgão, na ordem em que passaram a ser usadas, com os períodos de meses coletados por cada versão, a quantidade de coletas, a taxa de erro, estatísticas da duração das coletas e o link do commit. Coletas com status 4 (dados indisponíveis ou malformados) não contam como erro.
+//	@Produce		json
+//	@Param			orgao					path		string			true	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Success		200						{object}	crawlerHistory	"Requisição bem sucedida."
+//	@Failure		404						{string}	string			"Órgão não encontrado."
+//	@Failure		500						{string}	string			"Erro interno do servidor."
+//	@Router			/v2/coletores/{orgao}	[get]
+func (h handler) V2GetCrawlerHistory(c echo.Context) error {
+	agency := strings.ToLower(c.Param("orgao"))
+	if _, err := h.client.Db.GetAgency(agency); err != nil {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Órgão não encontrado: %s", strings.ToUpper(agency)))
+	}
+	collections, err := h.client.Db.GetAllAgencyCollection(agency)
+	if err != nil {
+		log.Printf("[crawler history] error getting collections (orgao:%s): %q", agency, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando as coletas")
+	}
+	return c.JSON(http.StatusOK, newCrawlerHistory(agency, collections))
+}
+
+//	@ID				GetFreshness
+//	@Tags			public_api
+//	@Description	Lista os órgãos atrasados: aqueles cujo último mês com dados é anterior ao mês esperado (o mês atual menos o prazo de publicação). Para cada órgão, informa quantos meses de atraso, o último mês com dados, se houve erro na nossa coleta depois dele, os motivos registrados na última verificação da coleta e o link da ouvidoria. Órgãos que nunca tiveram dados aparecem primeiro, sem meses de atraso.
+//	@Produce		json
+//	@Param			grupo				query		string		false	"Grupo de órgãos. Exemplos: justica-estadual, ministerios-publicos. Se omitido, todos os órgãos são incluídos."
+//	@Param			prazo				query		int			false	"Prazo de publicação, em meses, entre 0 e 24. Padrão: 2 (em outubro, espera-se que os dados de agosto já estejam publicados)."
+//	@Success		200					{object}	freshness	"Requisição bem sucedida."
+//	@Failure		400					{string}	string		"Parâmetros inválidos."
+//	@Failure		404					{string}	string		"Grupo não encontrado."
+//	@Failure		500					{string}	string		"Erro interno do servidor."
+//	@Router			/v2/atualizacao		[get]
+func (h handler) V2GetFreshness(c echo.Context) error {
+	delay := defaultPublicationDelay
+	if qp := c.QueryParam("prazo"); qp != "" {
+		var err error
+		delay, err = strconv.Atoi(qp)
+		if err != nil || delay < 0 || delay > maxPublicationDelay {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro prazo=%s inválido. Use um valor entre 0 e %d", qp, maxPublicationDelay))
+		}
+	}
+	group := strings.ToLower(c.QueryParam("grupo"))
+	var agencies []models.Agency
+	var err error
+	if group != "" {
+		g, ok := taxonomy.GroupBySlug(group)
+		if !ok {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s", group))
+		}
+		agencies, err = h.client.Db.GetOPJ(g.Jurisdiction)
+	} else {
+		agencies, err = h.client.Db.GetAllAgencies()
+	}
+	if err != nil {
+		log.Printf("[freshness] error getting agencies (grupo:%s): %q", group, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	now := time.Now()
+	monthlyInfo, err := h.agencyCollections(agencyIDs(agencies), 0, now.Year())
+	if err != nil {
+		log.Printf("[freshness] error getting collections (grupo:%s): %q", group, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os dados mensais")
+	}
+	f := newFreshness(agencies, monthlyInfo, period.YearMonth{Year: now.Year(), Month: int(now.Month())}, delay)
+	f.Group = group
+	return c.JSON(http.StatusOK, f)
+}
+
+//	@ID				CreateWebhook
+//	@Tags			public_api
+//	@Description	Cria uma assinatura de webhook. A URL informada recebe um POST a cada coleta nova (tipo nova_coleta) ou recoleta (tipo recoleta) de um órgão/mês, com os números do resumo e, nas recoletas, a variação em relação à coleta anterior. A assinatura pode ser restrita a um órgão ou a um grupo; sem nenhum dos dois, recebe os eventos de todos os órgãos. O corpo de cada POST é assinado com o segredo retornado aqui, que não é exibido de novo: o cabeçalho X-DadosJusBr-Signature traz sha256=<HMAC-SHA256 do corpo, em hexadecimal>. Respostas 2xx confirmam a entrega; erros de rede, 429 e 5xx são tentados de novo nas rodadas seguintes, com espera crescente entre as tentativas. O id da assinatura é necessário para consultá-la, testá-la e apagá-la. Antes de criar a assinatura, a URL recebe um evento do tipo verificacao com o campo desafio e deve responder com status 2xx trazendo o desafio no corpo; URLs que apontam para endereços internos não são aceitas. As entregas não seguem redirecionamentos. A criação é limitada a algumas requisições por hora por IP.
+//	@Accept			json
+//	@Produce		json
+//	@Param			assinatura			body		webhookRequest			true	"URL e filtro da assinatura."
+//	@Success		201					{object}	webhook.Subscription	"Assinatura criada."
+//	@Failure		400					{string}	string					"Parâmetros inválidos ou URL que não respondeu à verificação."
+//	@Failure		404					{string}	string					"Órgão ou grupo não encontrado."
+//	@Failure		429					{string}	string					"Muitas requisições."
+//	@Failure		500					{string}	string					"Erro interno do servidor."
+//	@Failure		503					{string}	string					"Webhooks indisponíveis."
+//	@Router			/v2/webhooks		[post]
+func (h handler) V2CreateWebhook(c echo.Context) error {
+	if h.webhooks == nil {
+		return c.JSON(http.StatusServiceUnavailable, "Webhooks indisponíveis")
+	}
+	var req webhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, "Corpo da requisição inválido")
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro url=%s inválido", req.URL))
+	}
+	if req.Agency != "" && req.Group != "" {
+		return c.JSON(http.StatusBadRequest, "Informe o órgão ou o grupo, não ambos")
+	}
+	agency := strings.ToLower(req.Agency)
+	if agency != "" {
+		if _, err := h.client.Db.GetAgency(agency); err != nil {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Órgão não encontrado: %s", strings.ToUpper(agency)))
+		}
+	}
+	group := strings.ToLower(req.Group)
+	if group != "" {
+		if _, ok := taxonomy.GroupBySlug(group); !ok {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s", req.Group))
+		}
+	}
+	s, err := h.webhooks.Subscribe(req.URL, agency, group)
+	if errors.Is(err, webhook.ErrInvalidURL) {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro url=%s inválido: o host deve ter um endereço público", req.URL))
+	}
+	if errors.Is(err, webhook.ErrVerificationFailed) {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("A URL %s não respondeu à verificação com o desafio enviado", req.URL))
+	}
+	if err != nil {
+		log.Printf("[webhooks] error creating subscription (url:%s): %q", req.URL, err)
+		return c.JSON(http.StatusInternalServerError, "Erro criando a assinatura")
+	}
+	return c.JSON(http.StatusCreated, s)
+}
+
+// webhookError responde os erros das rotas de uma assinatura.
+func webhookError(c echo.Context, id string, err error) error {
+	if errors.Is(err, webhook.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Assinatura não encontrada: %s", id))
+	}
+	log.Printf("[webhooks] error (id:%s): %q", id, err)
+	return c.JSON(http.StatusInternalServerError, "Erro buscando a assinatura")
+}
+
+//	@ID				GetWebhook
+//	@Tags			public_api
+//	@Description	Busca uma assinatura de webhook. O segredo não é retornado.
+//	@Produce		json
+//	@Param			id					path		string					true	"ID da assinatura, retornado na criação."
+//	@Success		200					{object}	webhook.Subscription	"Requisição bem sucedida."
+//	@Failure		404					{string}	string					"Assinatura não encontrada."
+//	@Failure		500					{string}	string					"Erro interno do servidor."
+//	@Failure		503					{string}	string					"Webhooks indisponíveis."
+//	@Router			/v2/webhooks/{id}	[get]
+func (h handler) V2GetWebhook(c echo.Context) error {
+	if h.webhooks == nil {
+		return c.JSON(http.StatusServiceUnavailable, "Webhooks indisponíveis")
+	}
+	id := c.Param("id")
+	s, err := h.webhooks.Subscription(id)
+	if err != nil {
+		return webhookError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, s)
+}
+
+//	@ID				DeleteWebhook
+//	@Tags			public_api
+//	@Description	Apaga uma assinatura de webhook e o seu registro de entregas.
+//	@Param			id					path		string	true	"ID da assinatura, retornado na criação."
+//	@Success		204					{string}	string	"Assinatura apagada."
+//	@Failure		404					{string}	string	"Assinatura não encontrada."
+//	@Failure		500					{string}	string	"Erro interno do servidor."
+//	@Failure		503					{string}	string	"Webhooks indisponíveis."
+//	@Router			/v2/webhooks/{id}	[delete]
+func (h handler) V2DeleteWebhook(c echo.Context) error {
+	if h.webhooks == nil {
+		return c.JSON(http.StatusServiceUnavailable, "Webhooks indisponíveis")
+	}
+	id := c.Param("id")
+	if err := h.webhooks.Unsubscribe(id); err != nil {
+		return webhookError(c, id, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+//	@ID				GetWebhookDeliveries
+//	@Tags			public_api
+//	@Description	Lista as últimas tentativas de entrega de uma assinatura de webhook, da mais recente para a mais antiga.
+//	@Produce		json
+//	@Param			id							path		string				true	"ID da assinatura, retornado na criação."
+//	@Param			limite						query		int					false	"Número de entregas, entre 1 e 500. Padrão: 50."
+//	@Success		200							{array}		webhook.Delivery	"Requisição bem sucedida."
+//	@Failure		400							{string}	string				"Parâmetros inválidos."
+//	@Failure		404							{string}	string				"Assinatura não encontrada."
+//	@Failure		500							{string}	string				"Erro interno do servidor."
+//	@Failure		503							{string}	string				"Webhooks indisponíveis."
+//	@Router			/v2/webhooks/{id}/entregas	[get]
+func (h handler) V2GetWebhookDeliveries(c echo.Context) error {
+	if h.webhooks == nil {
+		return c.JSON(http.StatusServiceUnavailable, "Webhooks indisponíveis")
+	}
+	limit := 50
+	if l := c.QueryParam("limite"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > 500 {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro limite=%s inválido", l))
+		}
+	}
+	id := c.Param("id")
+	deliveries, err := h.webhooks.Deliveries(id, limit)
+	if err != nil {
+		return webhookError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+//	@ID				PingWebhook
+//	@Tags			public_api
+//	@Description	Envia um evento de teste (tipo teste) para a URL da assinatura, uma única vez, e retorna o resultado da entrega. Só URLs verificadas na criação da assinatura recebem o teste, e o envio é limitado a algumas requisições por hora por IP.
+//	@Produce		json
+//	@Param			id							path		string				true	"ID da assinatura, retornado na criação."
+//	@Success		200							{object}	webhook.Delivery	"Requisição bem sucedida. O campo entregue indica se a URL respondeu com 2xx."
+//	@Failure		404							{string}	string				"Assinatura não encontrada."
+//	@Failure		429							{string}	string				"Muitas requisições."
+//	@Failure		500							{string}	string				"Erro interno do servidor."
+//	@Failure		503							{string}	string				"Webhooks indisponíveis."
+//	@Router			/v2/webhooks/{id}/teste		[post]
+func (h handler) V2PingWebhook(c echo.Context) error {
+	if h.webhooks == nil {
+		return c.JSON(http.StatusServiceUnavailable, "Webhooks indisponíveis")
+	}
+	id := c.Param("id")
+	d, err := h.webhooks.Ping(id)
+	if err != nil {
+		return webhookError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, d)
+}
+
+//	@ID				GetFeed
+//	@Tags			public_api
+//	@Description	Feed Atom com os órgãos/meses coletados ou atualizados recentemente, do mais recente para o mais antigo (até 50 entradas). Cada entrada traz o número de membros, a remuneração total e o índice de transparência do mês, e os links para a página do órgão no site e para o pacote de dados. Uma recoleta atualiza a entrada do mês (mesmo id, novo updated). As coletas são ordenadas pela data da coleta, qualquer que seja o mês a que se referem.
+//	@Produce		xml
+//	@Success		200					{string}	string	"Feed Atom."
+//	@Failure		500					{string}	string	"Erro interno do servidor."
+//	@Failure		503					{string}	string	"Feed indisponível."
+//	@Router			/v2/feed.atom		[get]
+func (h handler) V2GetFeed(c echo.Context) error {
+	if h.collections == nil {
+		return c.JSON(http.StatusServiceUnavailable, "Feed indisponível")
+	}
+	agencies, err := h.client.Db.GetAllAgencies()
+	if err != nil {
+		log.Printf("[feed] error getting agencies: %q", err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando os órgãos")
+	}
+	collections, err := h.collections.RecentCollections(feedSize)
+	if err != nil {
+		log.Printf("[feed] error getting recent collections: %q", err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando as coletas")
+	}
+	monthlyInfo := map[string][]models.AgencyMonthlyInfo{}
+	for _, mi := range collections {
+		monthlyInfo[mi.AgencyID] = append(monthlyInfo[mi.AgencyID], mi)
+	}
+	feed := h.newFeed("coletas", "DadosJusBr: coletas recentes", feedURL(c), newFeedItems(agencies, monthlyInfo), time.Now())
+	return atomResponse(c, feed)
+}
+
+//	@ID				GetAgencyFeed
+//	@Tags			public_api
+//	@Description	Feed Atom com os meses coletados ou atualizados recentemente de um órgão, do mais recente para o mais antigo (até 50 entradas). Cada entrada traz o número de membros, a remuneração total e o índice de transparência do mês, e os links para a página do órgão no site e para o pacote de dados. Uma recoleta atualiza a entrada do mês (mesmo id, novo updated).
+//	@Produce		xml
+//	@Param			orgao						path		string	true	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Success		200							{string}	string	"Feed Atom."
+//	@Failure		404							{string}	string	"Órgão não encontrado."
+//	@Failure		500							{string}	string	"Erro interno do servidor."
+//	@Router			/v2/orgao/{orgao}/feed.atom	[get]
+func (h handler) V2GetAgencyFeed(c echo.Context) error {
+	agencyID := strings.ToLower(c.Param("orgao"))
+	agency, err := h.client.Db.GetAgency(agencyID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Órgão não encontrado: %s", strings.ToUpper(agencyID)))
+	}
+	collections, err := h.client.Db.GetAllAgencyCollection(agencyID)
+	if err != nil {
+		log.Printf("[feed] error getting collections (orgao:%s): %q", agencyID, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando as coletas")
+	}
+	items := newFeedItems([]models.Agency{*agency}, map[string][]models.AgencyMonthlyInfo{agency.ID: collections})
+	title := fmt.Sprintf("DadosJusBr: coletas recentes de %s", agency.Name)
+	feed := h.newFeed("coletas/"+agency.ID, title, feedURL(c), items, time.Now())
+	return atomResponse(c, feed)
+}
+
+func feedURL(c echo.Context) string {
+	return fmt.Sprintf("%s://%s%s", c.Scheme(), c.Request().Host, c.Request().URL.Path)
+}
+
+func atomResponse(c echo.Context, feed atomFeed) error {
+	b, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		log.Printf("[feed] error marshaling feed: %q", err)
+		return c.JSON(http.StatusInternalServerError, "Erro gerando o feed")
+	}
+	return c.Blob(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), b...))
+}
+
 func (h handler) formatDownloadUrl(url string) string {
 	return strings.Replace(url, h.packageRepoURL, h.dadosJusURL, -1)
 }
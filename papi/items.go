@@ -0,0 +1,140 @@
+package papi
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/api/tables"
+	"github.com/dadosjusbr/storage/models"
+)
+
+type itemSummaryField struct {
+	name  string // nome do campo no JSON de itemSummary
+	value func(models.ItemSummary) float64
+}
+
+// Campos de itemSummary, na ordem em que aparecem no JSON.
+var itemSummaryFields = []itemSummaryField{
+	{"auxilio_alimentacao", func(s models.ItemSummary) float64 { return s.FoodAllowance }},
+	{"licenca_premio", func(s models.ItemSummary) float64 { return s.BonusLicense }},
+	{"indenizacao_de_ferias", func(s models.ItemSummary) float64 { return s.VacationCompensation }},
+	{"ferias", func(s models.ItemSummary) float64 { return s.Vacation }},
+	{"gratificacao_natalina", func(s models.ItemSummary) float64 { return s.ChristmasBonus }},
+	{"licenca_compensatoria", func(s models.ItemSummary) float64 { return s.CompensatoryLicense }},
+	{"auxilio_saude", func(s models.ItemSummary) float64 { return s.HealthAllowance }},
+	{"outras", func(s models.ItemSummary) float64 { return s.Others }},
+}
+
+// selectItemFields retorna os campos listados no parâmetro rubricas
+// (separados por vírgula), ou todos os campos se ele estiver vazio.
+func selectItemFields(qp string) ([]itemSummaryField, error) {
+	if qp == "" {
+		return itemSummaryFields, nil
+	}
+	var fields []itemSummaryField
+	for _, name := range strings.Split(qp, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, f := range itemSummaryFields {
+			if f.name == name {
+				fields = append(fields, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("rubrica inválida: '%s'", name)
+		}
+	}
+	return fields, nil
+}
+
+// newItemSeries monta as séries mensais das rubricas, alinhadas aos meses do
+// período. Meses sem dados ficam com valor nulo.
+func newItemSeries(agencyID string, p *period.Period, fields []itemSummaryField, monthlyInfo []models.AgencyMonthlyInfo, deflator *tables.Deflator) itemSeries {
+	summaries := map[period.YearMonth]*models.Summary{}
+	for _, mi := range monthlyInfo {
+		if mi.Summary == nil || !p.Contains(mi.Year, mi.Month) {
+			continue
+		}
+		// Meses com erro na coleta não têm dados.
+		if mi.ProcInfo != nil && mi.ProcInfo.String() != "" {
+			continue
+		}
+		summaries[period.YearMonth{Year: mi.Year, Month: mi.Month}] = mi.Summary
+	}
+
+	months := p.Months()
+	series := itemSeries{
+		Agency:      agencyID,
+		Start:       months[0].String(),
+		End:         months[len(months)-1].String(),
+		Months:      make([]string, len(months)),
+		MemberCount: make([]*int, len(months)),
+		Items:       make([]itemSeriesValues, len(fields)),
+		Correction:  deflator.Correction(),
+	}
+	for j, f := range fields {
+		series.Items[j] = itemSeriesValues{
+			Item:      f.name,
+			Total:     make([]*float64, len(months)),
+			PerCapita: make([]*float64, len(months)),
+		}
+	}
+	for i, m := range months {
+		series.Months[i] = m.String()
+		s, ok := summaries[m]
+		if !ok {
+			continue
+		}
+		count := s.Count
+		series.MemberCount[i] = &count
+		for j, f := range fields {
+			total := deflator.Apply(f.value(s.ItemSummary), m.Year, m.Month)
+			series.Items[j].Total[i] = &total
+			if count > 0 {
+				perCapita := total / float64(count)
+				series.Items[j].PerCapita[i] = &perCapita
+			}
+		}
+	}
+	return series
+}
+
+// writeItemSeriesCSV escreve as séries em formato largo: uma linha por mês e,
+// para cada rubrica, uma coluna com o total e outra com o valor por membro.
+func writeItemSeriesCSV(w io.Writer, s itemSeries) error {
+	cw := csv.NewWriter(w)
+	header := []string{"mes", "num_membros"}
+	for _, item := range s.Items {
+		header = append(header, item.Item, item.Item+"_por_membro")
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i, m := range s.Months {
+		record := []string{m, ""}
+		if s.MemberCount[i] != nil {
+			record[1] = strconv.Itoa(*s.MemberCount[i])
+		}
+		for _, item := range s.Items {
+			record = append(record, formatCSVValue(item.Total[i]), formatCSVValue(item.PerCapita[i]))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCSVValue(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 2, 64)
+}
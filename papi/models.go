@@ -1,5 +1,11 @@
 package papi
 
+import (
+	"time"
+
+	"github.com/dadosjusbr/api/tables"
+)
+
 type backup struct {
 	URL  string `json:"url,omitempty"`
 	Hash string `json:"hash,omitempty"`
@@ -72,23 +78,25 @@ type miError struct {
 }
 
 type summaryzedMI struct {
-	AgencyID         string     `json:"id_orgao,omitempty"`
-	Month            int        `json:"mes,omitempty"`
-	Year             int        `json:"ano,omitempty"`
-	Summary          *summaries `json:"sumarios,omitempty"`
-	Package          *backup    `json:"pacote_de_dados,omitempty"`
-	Metadata         *metadata  `json:"metadados,omitempty"`
-	Score            *score     `json:"indice_transparencia,omitempty"`
-	Collect          *collect   `json:"dados_coleta,omitempty"`
-	ManualCollection bool       `json:"coleta_manual"`
-	Error            *miError   `json:"error,omitempty"`
+	AgencyID         string                    `json:"id_orgao,omitempty"`
+	Month            int                       `json:"mes,omitempty"`
+	Year             int                       `json:"ano,omitempty"`
+	Summary          *summaries                `json:"sumarios,omitempty"`
+	Package          *backup                   `json:"pacote_de_dados,omitempty"`
+	Metadata         *metadata                 `json:"metadados,omitempty"`
+	Score            *score                    `json:"indice_transparencia,omitempty"`
+	Collect          *collect                  `json:"dados_coleta,omitempty"`
+	ManualCollection bool                      `json:"coleta_manual"`
+	Error            *miError                  `json:"error,omitempty"`
+	Correction       *tables.Correction        `json:"correcao_monetaria,omitempty"`
+	Distribution     *remunerationDistribution `json:"distribuicao,omitempty"`
 }
 
 type agency struct {
 	ID            string       `json:"id_orgao,omitempty"`   // 'trt13'
 	Name          string       `json:"nome,omitempty"`       // 'Tribunal Regional do Trabalho 13° Região'
-	Type          string       `json:"jurisdicao,omitempty"` // "R" for Regional, "M" for Municipal, "F" for Federal, "E" for State.
-	Entity        string       `json:"entidade,omitempty"`   // "J" For Judiciário, "M" for Ministério Público, "P" for Procuradorias and "D" for Defensorias.
+	Type          string       `json:"jurisdicao,omitempty"` // Jurisdiction of the agency's group, e.g. "Estadual", "Trabalho". See taxonomy.Groups.
+	Entity        string       `json:"entidade,omitempty"`   // Kind of institution, e.g. "Tribunal", "Ministério". See taxonomy.Entities.
 	UF            string       `json:"uf,omitempty"`         // Short code for federative unity.
 	URL           string       `json:"url,omitempty"`        // Link for state url
 	Collecting    []collecting `json:"coletando,omitempty"`
@@ -142,3 +150,402 @@ type allAgencyInformation struct {
 	Score             *score         `json:"indice_transparencia,omitempty"`
 	Collections       []summaryzedMI `json:"coletas"`
 }
+
+// itemSeries - séries mensais de rubricas de um órgão, alinhadas aos meses do período
+type itemSeries struct {
+	Agency      string             `json:"orgao"`
+	Start       string             `json:"inicio"`
+	End         string             `json:"fim"`
+	Months      []string           `json:"meses"`       // AAAA-MM
+	MemberCount []*int             `json:"num_membros"` // nulo nos meses sem dados
+	Items       []itemSeriesValues `json:"rubricas"`
+	Correction  *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+type itemSeriesValues struct {
+	Item      string     `json:"rubrica"`
+	Total     []*float64 `json:"total"`
+	PerCapita []*float64 `json:"por_membro"`
+}
+
+// remunerationDistribution - distribuição das remunerações dos membros de um
+// órgão em um mês ou ano, calculada a partir dos zips de remunerações. Cada par
+// membro/mês é uma observação.
+type remunerationDistribution struct {
+	Members int               `json:"num_observacoes"`
+	Gross   distributionStats `json:"remuneracao_bruta"`
+	Net     distributionStats `json:"remuneracao_liquida"`
+}
+
+type distributionStats struct {
+	Median float64 `json:"mediana"`
+	P10    float64 `json:"p10"`
+	P25    float64 `json:"p25"`
+	P75    float64 `json:"p75"`
+	P90    float64 `json:"p90"`
+	P99    float64 `json:"p99"`
+	Gini   float64 `json:"gini"`
+}
+
+// agencyYearDistribution - distribuição das remunerações de um órgão em um ano
+type agencyYearDistribution struct {
+	AgencyID     string                    `json:"id_orgao"`
+	Year         int                       `json:"ano"`
+	Distribution *remunerationDistribution `json:"distribuicao"`
+	Correction   *tables.Correction        `json:"correcao_monetaria,omitempty"`
+}
+
+// growth - variação mensal e anual dos totais de um órgão ou grupo de órgãos.
+// As variações comparam apenas os órgãos com dados nos dois períodos.
+type growth struct {
+	Group      string             `json:"grupo,omitempty"`
+	Agencies   []string           `json:"orgaos"`
+	Start      string             `json:"inicio"`
+	End        string             `json:"fim"`
+	Months     []growthMonth      `json:"meses"`
+	Years      []growthYear       `json:"anos"`
+	Correction *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+type growthMonth struct {
+	Month           string            `json:"mes"`                        // AAAA-MM
+	Status          string            `json:"situacao"`                   // completo, parcial ou sem_dados
+	MissingAgencies []string          `json:"orgaos_sem_dados,omitempty"` // órgãos sem coleta no mês
+	ErrorAgencies   []string          `json:"orgaos_com_erro,omitempty"`  // órgãos cuja coleta do mês falhou
+	Values          *growthValues     `json:"valores,omitempty"`
+	MonthOverMonth  *growthComparison `json:"variacao_mensal,omitempty"` // em relação ao mês anterior; ausente se não há dados em um dos meses
+	YearOverYear    *growthComparison `json:"variacao_anual,omitempty"`  // em relação ao mesmo mês do ano anterior
+}
+
+type growthYear struct {
+	Year           int               `json:"ano"`
+	MonthsWithData int               `json:"meses_com_dados"` // soma dos meses com dados de cada órgão
+	Complete       bool              `json:"completo"`        // todos os órgãos têm os 12 meses
+	Values         *growthValues     `json:"valores,omitempty"`
+	YearOverYear   *growthComparison `json:"variacao_anual,omitempty"`
+}
+
+// growthValues - num_membros é a quantidade de membros no mês ou a média
+// mensal no ano; o valor por membro é a média mensal por membro.
+type growthValues struct {
+	MemberCount float64 `json:"num_membros"`
+	Total       float64 `json:"remuneracao_bruta"`
+	PerCapita   float64 `json:"remuneracao_bruta_por_membro"`
+}
+
+type growthComparison struct {
+	ComparedTo  string          `json:"comparado_com"` // AAAA-MM ou AAAA
+	Agencies    int             `json:"num_orgaos_comparados"`
+	Partial     bool            `json:"parcial"` // a comparação exclui órgãos sem dados em um dos períodos ou, entre anos, meses com dados em só um dos anos
+	MemberCount growthVariation `json:"num_membros"`
+	Total       growthVariation `json:"remuneracao_bruta"`
+	PerCapita   growthVariation `json:"remuneracao_bruta_por_membro"`
+}
+
+type growthVariation struct {
+	Absolute   float64  `json:"absoluta"`
+	Percentage *float64 `json:"percentual"` // nulo quando o valor anterior é zero
+}
+
+// anomalies - órgãos/meses cujos valores se desviam da linha de base do órgão
+type anomalies struct {
+	Group      string             `json:"grupo,omitempty"`
+	Agencies   []string           `json:"orgaos"`
+	Start      string             `json:"inicio"`
+	End        string             `json:"fim"`
+	Threshold  float64            `json:"limiar"` // pontuação mínima, em módulo, para um valor ser anômalo
+	Window     int                `json:"janela"` // meses da linha de base móvel
+	Anomalies  []anomaly          `json:"anomalias"`
+	Correction *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+type anomaly struct {
+	Agency         string        `json:"orgao"`
+	Month          string        `json:"mes"`     // AAAA-MM
+	Metric         string        `json:"metrica"` // remuneracao_bruta, remuneracao_bruta_por_membro ou um campo de resumo_rubricas
+	Value          float64       `json:"valor"`
+	Score          float64       `json:"pontuacao"` // escore z robusto: desvio da mediana em unidades de desvio absoluto mediano
+	BaselineMedian float64       `json:"mediana_referencia"`
+	ExpectedRange  expectedRange `json:"faixa_esperada"`
+	Baseline       string        `json:"referencia"`       // movel (meses anteriores) ou sazonal (mesmo mês dos anos anteriores)
+	BaselineMonths []string      `json:"meses_referencia"` // meses usados na linha de base
+	DrivingItem    string        `json:"rubrica_principal,omitempty"`
+}
+
+type expectedRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// comparison - séries mensais de vários órgãos, alinhadas aos meses do período
+type comparison struct {
+	Agencies         []string           `json:"orgaos"`
+	Start            string             `json:"inicio"`
+	End              string             `json:"fim"`
+	Months           []string           `json:"meses"`             // AAAA-MM
+	IncompleteMonths []string           `json:"meses_incompletos"` // meses em que algum órgão não tem dados
+	Series           []agencyComparison `json:"series"`
+	Correction       *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+// agencyComparison - séries de um órgão. Os valores são nulos nos meses sem dados.
+type agencyComparison struct {
+	Agency             string             `json:"orgao"`
+	Status             []string           `json:"situacao"` // com_dados, sem_dados ou erro_coleta, para cada mês
+	MissingMonths      []string           `json:"meses_sem_dados"`
+	MemberCount        []*int             `json:"num_membros"`
+	BaseRemuneration   []*float64         `json:"remuneracao_base"`
+	OtherRemunerations []*float64         `json:"outras_remuneracoes"`
+	Discounts          []*float64         `json:"descontos"`
+	Remunerations      []*float64         `json:"remuneracao_bruta"`
+	PerCapita          []*float64         `json:"remuneracao_bruta_por_membro"`
+	Items              []itemSeriesValues `json:"resumo_rubricas"`
+	Score              []*score           `json:"indice_transparencia"`
+}
+
+// ranking - órgãos ordenados por uma métrica do resumo anual, do maior para o menor valor
+type ranking struct {
+	Year              int            `json:"ano"`
+	Metric            string         `json:"metrica"`
+	Group             string         `json:"grupo,omitempty"`
+	IncludeIncomplete bool           `json:"inclui_incompletos"` // se os órgãos com menos de 12 meses de dados recebem posição
+	Agencies          []rankingEntry `json:"orgaos"`
+	NoData            []string       `json:"orgaos_sem_dados"`
+}
+
+type rankingEntry struct {
+	AgencyID         string   `json:"id_orgao"`
+	Name             string   `json:"nome"`
+	Position         *int     `json:"posicao"` // nula para órgãos com ano incompleto, se não forem incluídos
+	Value            float64  `json:"valor"`
+	Percentile       *float64 `json:"percentil"` // 100 para o primeiro colocado e 0 para o último
+	MonthsWithData   int      `json:"meses_com_dados"`
+	Incomplete       bool     `json:"ano_incompleto"`
+	PreviousPosition *int     `json:"posicao_ano_anterior"`
+	PositionChange   *int     `json:"variacao_posicao"` // positiva quando o órgão subiu no ranking
+}
+
+// aggregate - dados mensais de um conjunto de órgãos (uma UF ou um grupo) somados em cada mês do ano
+type aggregate struct {
+	UF         string             `json:"uf,omitempty"`
+	Group      string             `json:"grupo,omitempty"`
+	Year       int                `json:"ano"`
+	Agencies   []string           `json:"orgaos"`
+	Months     []aggregateMonth   `json:"meses"`
+	PerAgency  []aggregateAgency  `json:"por_orgao"` // totais do ano de cada órgão
+	Correction *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+type aggregateMonth struct {
+	Month    int             `json:"mes"`
+	Totals   aggregateTotals `json:"totais"`
+	Coverage monthCoverage   `json:"cobertura"`
+}
+
+// aggregateSummary - resumos anuais de um conjunto de órgãos somados em cada ano
+type aggregateSummary struct {
+	UF         string             `json:"uf,omitempty"`
+	Group      string             `json:"grupo,omitempty"`
+	Agencies   []string           `json:"orgaos"`
+	Years      []aggregateAnnual  `json:"anos"`
+	Correction *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+type aggregateAnnual struct {
+	Year      int               `json:"ano"`
+	Totals    aggregateTotals   `json:"totais"`
+	Coverage  yearCoverage      `json:"cobertura"`
+	PerAgency []aggregateAgency `json:"por_orgao"`
+}
+
+// aggregateTotals - valores somados. Nos anos, num_membros é a média mensal e
+// os valores por membro são médias mensais.
+type aggregateTotals struct {
+	MemberCount                 int         `json:"num_membros"`
+	BaseRemuneration            float64     `json:"remuneracao_base"`
+	BaseRemunerationPerCapita   float64     `json:"remuneracao_base_por_membro"`
+	OtherRemunerations          float64     `json:"outras_remuneracoes"`
+	OtherRemunerationsPerCapita float64     `json:"outras_remuneracoes_por_membro"`
+	Discounts                   float64     `json:"descontos"`
+	DiscountsPerCapita          float64     `json:"descontos_por_membro"`
+	Remunerations               float64     `json:"remuneracoes"`
+	RemunerationsPerCapita      float64     `json:"remuneracoes_por_membro"`
+	ItemSummary                 itemSummary `json:"resumo_rubricas"`
+}
+
+// monthCoverage - quantos órgãos do conjunto têm dados no mês
+type monthCoverage struct {
+	AgenciesWithData int      `json:"orgaos_com_dados"`
+	TotalAgencies    int      `json:"total_orgaos"`
+	NoData           []string `json:"orgaos_sem_dados"`
+	CrawlingErrors   []string `json:"orgaos_com_erro_coleta"`
+}
+
+// yearCoverage - quantos órgãos do conjunto têm dados no ano
+type yearCoverage struct {
+	AgenciesWithData int      `json:"orgaos_com_dados"`
+	TotalAgencies    int      `json:"total_orgaos"`
+	NoData           []string `json:"orgaos_sem_dados"`
+	Incomplete       []string `json:"orgaos_ano_incompleto"` // órgãos com menos de 12 meses de dados
+}
+
+type aggregateAgency struct {
+	ID             string          `json:"id_orgao"`
+	Name           string          `json:"nome"`
+	MonthsWithData int             `json:"meses_com_dados"`
+	Totals         aggregateTotals `json:"totais"`
+}
+
+// groupInfo - grupo de órgãos da taxonomia
+type groupInfo struct {
+	Slug         string        `json:"grupo"` // nome usado nas URLs, ex: justica-estadual
+	Label        string        `json:"nome"`
+	Jurisdiction string        `json:"jurisdicao"` // valor do campo jurisdicao dos órgãos do grupo
+	AgencyCount  int           `json:"num_orgaos"`
+	Entities     []entityCount `json:"entidades"`
+}
+
+// ufInfo - unidade federativa da taxonomia
+type ufInfo struct {
+	Code        string        `json:"uf"`   // sigla, ex: PB
+	Slug        string        `json:"slug"` // nome usado nas URLs, ex: pb
+	Name        string        `json:"nome"`
+	Region      string        `json:"regiao"`
+	FlagURL     string        `json:"bandeira,omitempty"`
+	AgencyCount int           `json:"num_orgaos"`
+	Entities    []entityCount `json:"entidades"`
+}
+
+type entityCount struct {
+	Code        string `json:"entidade"` // valor do campo entidade dos órgãos, ex: Tribunal
+	Label       string `json:"nome"`
+	AgencyCount int    `json:"num_orgaos"`
+}
+
+// coverage - matriz órgão × mês da situação das coletas
+type coverage struct {
+	Group       string           `json:"grupo,omitempty"`
+	Start       string           `json:"inicio"`
+	End         string           `json:"fim"`
+	Months      []string         `json:"meses"` // AAAA-MM
+	Agencies    []agencyCoverage `json:"orgaos"`
+	MonthTotals []coverageTotals `json:"totais_por_mes"` // alinhados aos meses
+	Totals      coverageTotals   `json:"totais"`
+}
+
+type agencyCoverage struct {
+	Agency string         `json:"id_orgao"`
+	Cells  []coverageCell `json:"meses"` // alinhadas aos meses
+	Totals coverageTotals `json:"totais"`
+}
+
+type coverageCell struct {
+	Status        string `json:"situacao"`                // ok, coleta_manual, erro_coleta, indisponivel ou nao_coletado
+	CrawlerStatus *int32 `json:"status_coleta,omitempty"` // status informado pelo coletor, em erro_coleta e indisponivel
+}
+
+type coverageTotals struct {
+	OK            int `json:"ok"`
+	Manual        int `json:"coleta_manual"`
+	CrawlingError int `json:"erro_coleta"`
+	Unavailable   int `json:"indisponivel"`
+	NotCollected  int `json:"nao_coletado"`
+}
+
+// crawlingErrors - coletas com erro no período, agrupadas pela causa
+type crawlingErrors struct {
+	Group     string               `json:"grupo,omitempty"`
+	Agency    string               `json:"orgao,omitempty"`
+	Start     string               `json:"inicio"`
+	End       string               `json:"fim"`
+	Total     int                  `json:"total_erros"`
+	Errors    []crawlingErrorGroup `json:"erros"`
+	PerStatus []crawlingErrorCount `json:"por_status"`
+	PerAgency []crawlingErrorCount `json:"por_orgao"`
+}
+
+// crawlingErrorGroup - coletas com erro de um órgão com o mesmo status, coletor e mensagem normalizada
+type crawlingErrorGroup struct {
+	Agency          string   `json:"id_orgao"`
+	Status          int32    `json:"status"`
+	CrawlerRepo     string   `json:"repositorio_coletor"`
+	CrawlerVersion  string   `json:"versao_coletor"`
+	Message         string   `json:"mensagem"` // última linha da saída de erro, sem endereços e números
+	Example         string   `json:"exemplo"`  // saída de erro da primeira ocorrência
+	Cmd             string   `json:"cmd"`
+	Count           int      `json:"ocorrencias"`
+	FirstOccurrence string   `json:"primeira_ocorrencia"` // AAAA-MM
+	LastOccurrence  string   `json:"ultima_ocorrencia"`   // AAAA-MM
+	Months          []string `json:"meses"`
+}
+
+type crawlingErrorCount struct {
+	Status *int32 `json:"status,omitempty"`
+	Agency string `json:"id_orgao,omitempty"`
+	Count  int    `json:"ocorrencias"`
+}
+
+// crawlerHistory - versões do coletor e do parser usadas nas coletas de um órgão
+type crawlerHistory struct {
+	Agency                string         `json:"id_orgao"`
+	Crawlers              []versionUsage `json:"coletores"`
+	Parsers               []versionUsage `json:"parsers"`
+	WithoutCrawlerVersion int            `json:"coletas_sem_versao_coletor"`
+	WithoutParserVersion  int            `json:"coletas_sem_versao_parser"`
+}
+
+// versionUsage - uso de uma versão do coletor ou do parser
+type versionUsage struct {
+	Repo        string          `json:"repositorio"`
+	Version     string          `json:"versao"`
+	CommitURL   string          `json:"commit,omitempty"` // link do commit, para repositórios do GitHub
+	Periods     []versionPeriod `json:"periodos"`
+	Collections int             `json:"coletas"`
+	Errors      int             `json:"coletas_com_erro"`
+	Unavailable int             `json:"coletas_indisponiveis"` // status 4: dados indisponíveis ou malformados, não contam como erro
+	ErrorRate   float64         `json:"taxa_erro"`
+	Duration    *durationStats  `json:"duracao_segundos,omitempty"` // duração total das coletas, em segundos
+	FirstCrawl  *time.Time      `json:"primeira_coleta,omitempty"`
+	LastCrawl   *time.Time      `json:"ultima_coleta,omitempty"`
+}
+
+// versionPeriod - meses consecutivos coletados com a mesma versão
+type versionPeriod struct {
+	Start string `json:"inicio"` // AAAA-MM
+	End   string `json:"fim"`    // AAAA-MM
+}
+
+type durationStats struct {
+	Min    float64 `json:"min"`
+	Median float64 `json:"mediana"`
+	Mean   float64 `json:"media"`
+	Max    float64 `json:"max"`
+}
+
+// freshness - órgãos que ainda não publicaram os dados dos meses esperados
+type freshness struct {
+	Group         string       `json:"grupo,omitempty"`
+	CurrentMonth  string       `json:"mes_atual"`    // AAAA-MM
+	Delay         int          `json:"prazo_meses"`  // prazo de publicação, em meses
+	ExpectedMonth string       `json:"mes_esperado"` // último mês que já deveria ter dados: mes_atual - prazo_meses
+	Late          []lateAgency `json:"orgaos_atrasados"`
+	UpToDate      []string     `json:"orgaos_em_dia"`
+}
+
+type lateAgency struct {
+	ID               string   `json:"id_orgao"`
+	Name             string   `json:"nome"`
+	LastMonth        *string  `json:"ultimo_mes_com_dados"` // AAAA-MM, nulo se o órgão nunca teve dados
+	MonthsLate       *int     `json:"meses_atraso"`         // nulo se o órgão nunca teve dados
+	CrawlingError    bool     `json:"erro_coleta"`          // se algum mês posterior ao último com dados teve erro na coleta
+	Reasons          []string `json:"motivos"`              // motivos informados na última verificação da coleta do órgão
+	ReasonsTimestamp *int64   `json:"motivos_timestamp,omitempty"`
+	OmbudsmanURL     string   `json:"ouvidoria,omitempty"`
+}
+
+// webhookRequest é o corpo da criação de uma assinatura de webhook.
+type webhookRequest struct {
+	URL    string `json:"url"`
+	Agency string `json:"orgao,omitempty"` // se preenchido, recebe somente os eventos do órgão
+	Group  string `json:"grupo,omitempty"` // se preenchido, recebe somente os eventos do grupo (ex.: justica-estadual)
+}
@@ -1,12 +1,21 @@
 package papi
 
 import (
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/dadosjusbr/api/classification"
+	"github.com/dadosjusbr/api/distribution"
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/api/tables"
+	"github.com/dadosjusbr/api/webhook"
+	"github.com/dadosjusbr/proto/coleta"
 	"github.com/dadosjusbr/storage"
 	"github.com/dadosjusbr/storage/models"
 	"github.com/dadosjusbr/storage/repo/database"
@@ -14,6 +23,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestGetAgencyById(t *testing.T) {
@@ -54,7 +64,7 @@ func (g getAgencyById) testGetAgencyByIdWhenAgencyExists(t *testing.T) {
 	ctx.SetParamValues(agencyId)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler := NewHandler(client, "", "")
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
 	handler.V2GetAgencyById(ctx)
 
 	expectedHttpCode := 200
@@ -96,7 +106,7 @@ func (g getAgencyById) testGetAgencyByIdWhenAgencyDoesNotExist(t *testing.T) {
 	ctx.SetParamValues(agencyId)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler := NewHandler(client, "", "")
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
 	handler.V2GetAgencyById(ctx)
 
 	expectedHttpCode := 404
@@ -168,7 +178,7 @@ func (g getAllAgencies) testGetAllAgenciesWhenAgenciesExists(t *testing.T) {
 	ctx := e.NewContext(request, recoder)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler := NewHandler(client, "", "")
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
 	handler.V2GetAllAgencies(ctx)
 
 	expectedHttpCode := 200
@@ -233,7 +243,7 @@ func (g getAllAgencies) testGetAllAgenciesWhenAgenciesDoesNotExist(t *testing.T)
 	ctx := e.NewContext(request, recoder)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler := NewHandler(client, "", "")
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
 	handler.V2GetAllAgencies(ctx)
 
 	expectedHttpCode := 200
@@ -242,3 +252,1683 @@ func (g getAllAgencies) testGetAllAgenciesWhenAgenciesDoesNotExist(t *testing.T)
 	assert.Equal(t, expectedHttpCode, recoder.Code)
 	assert.Equal(t, expectedJson, strings.Trim(recoder.Body.String(), "\n"))
 }
+
+func TestGetMonthlyInfosByYear(t *testing.T) {
+	tests := getMonthlyInfosByYear{}
+	t.Run("Test GetMonthlyInfosByYear with IPCA correction", tests.testWithIPCACorrection)
+	t.Run("Test GetMonthlyInfosByYear with invalid correction", tests.testWithInvalidCorrection)
+}
+
+type getMonthlyInfosByYear struct{}
+
+func (g getMonthlyInfosByYear) testWithIPCACorrection(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	monthlyInfos := map[string][]models.AgencyMonthlyInfo{
+		"tjal": {
+			{
+				AgencyID: "tjal",
+				Month:    1,
+				Year:     2020,
+				Summary: &models.Summary{
+					Count:            10,
+					BaseRemuneration: models.DataSummary{Max: 2000, Min: 500, Average: 1000, Total: 10000},
+					Remunerations:    models.DataSummary{Max: 2000, Min: 500, Average: 1000, Total: 10000},
+					ItemSummary:      models.ItemSummary{FoodAllowance: 100},
+				},
+				Package:  &models.Backup{URL: "https://dadosjusbr.org/tjal-2020-1.zip"},
+				Meta:     &models.Meta{},
+				Score:    &models.Score{},
+				ProcInfo: &coleta.ProcInfo{},
+			},
+		},
+	}
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetMonthlyInfo([]models.Agency{{ID: "tjal"}}, 2020).Return(monthlyInfos, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(
+		http.MethodGet,
+		"/v2/dados/:orgao/:ano?corrigir=ipca&base=2020-02",
+		nil,
+	)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao", "ano")
+	ctx.SetParamValues("tjal", "2020")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.GetMonthlyInfosByYear(ctx)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got []summaryzedMI
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Len(t, got, 1)
+	// IPCA de fevereiro de 2020: 0,25%.
+	s := got[0].Summary.MemberActive
+	assert.Equal(t, 10, s.Count)
+	assert.InDelta(t, 10025, s.BaseRemuneration.Total, 1e-6)
+	assert.InDelta(t, 2005, s.BaseRemuneration.Max, 1e-6)
+	assert.InDelta(t, 1002.5, s.Remunerations.Average, 1e-6)
+	assert.InDelta(t, 100.25, s.ItemSummary.FoodAllowance, 1e-6)
+	assert.Equal(t, &tables.Correction{Index: "ipca", Base: "2020-02", TableVersion: tables.IPCA().Version}, got[0].Correction)
+}
+
+func (g getMonthlyInfosByYear) testWithInvalidCorrection(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(
+		http.MethodGet,
+		"/v2/dados/:orgao/:ano?corrigir=igpm",
+		nil,
+	)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao", "ano")
+	ctx.SetParamValues("tjal", "2020")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.GetMonthlyInfosByYear(ctx)
+
+	expectedJson := `"parâmetro corrigir 'igpm' é inválido! Valores aceitos: ipca"`
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, expectedJson, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestGetMonthlyInfo(t *testing.T) {
+	tests := getMonthlyInfo{}
+	t.Run("Test GetMonthlyInfo with distribution", tests.testWithDistribution)
+	t.Run("Test GetMonthlyInfo when distribution fails", tests.testWhenDistributionFails)
+	t.Run("Test GetMonthlyInfo without distribution", tests.testWithoutDistribution)
+}
+
+type getMonthlyInfo struct{}
+
+// fakeDistributions retorna sempre a mesma distribuição, ou err se informado,
+// e guarda o deflator recebido e a quantidade de chamadas.
+type fakeDistributions struct {
+	dist     *distribution.Distribution
+	err      error
+	calls    int
+	deflator *tables.Deflator
+}
+
+func (f *fakeDistributions) AgencyMonthDistribution(agency string, year, month int) (*distribution.Distribution, error) {
+	f.calls++
+	return f.dist, f.err
+}
+
+func (f *fakeDistributions) AgencyYearDistribution(agency string, year int, deflator *tables.Deflator) (*distribution.Distribution, error) {
+	f.calls++
+	f.deflator = deflator
+	return f.dist, f.err
+}
+
+var testDistribution = distribution.New([]float64{10000, 20000, 30000, 40000, 50000}, []float64{8000, 16000, 24000, 32000, 40000})
+
+func (g getMonthlyInfo) request(t *testing.T, distributions DistributionSource, query string) *httptest.ResponseRecorder {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	monthlyInfo := &models.AgencyMonthlyInfo{
+		AgencyID: "tjal",
+		Month:    1,
+		Year:     2020,
+		Summary:  &models.Summary{Count: 5},
+		Package:  &models.Backup{},
+		Meta:     &models.Meta{},
+		Score:    &models.Score{},
+		ProcInfo: &coleta.ProcInfo{},
+	}
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetOMA(1, 2020, "tjal").Return(monthlyInfo, nil, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/dados/:orgao/:ano/:mes?corrigir=ipca&base=2020-02"+query, nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao", "ano", "mes")
+	ctx.SetParamValues("tjal", "2020", "1")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", distributions, nil, nil)
+	handler.V2GetMonthlyInfo(ctx)
+	return recorder
+}
+
+func (g getMonthlyInfo) testWithDistribution(t *testing.T) {
+	recorder := g.request(t, &fakeDistributions{dist: &testDistribution}, "&distribuicao=true")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got summaryzedMI
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.NotNil(t, got.Distribution)
+	// IPCA de fevereiro de 2020: 0,25%. O Gini não é corrigido.
+	d := got.Distribution
+	assert.Equal(t, 5, d.Members)
+	assert.InDelta(t, 30075, d.Gross.Median, 1e-6)
+	assert.InDelta(t, 24060, d.Net.Median, 1e-6)
+	assert.InDelta(t, 10025*1.4, d.Gross.P10, 1e-6)
+	assert.InDelta(t, testDistribution.Gross.Gini, d.Gross.Gini, 1e-9)
+}
+
+func (g getMonthlyInfo) testWhenDistributionFails(t *testing.T) {
+	recorder := g.request(t, &fakeDistributions{err: fmt.Errorf("s3 unavailable")}, "&distribuicao=true")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got summaryzedMI
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Nil(t, got.Distribution)
+	assert.Equal(t, 5, got.Summary.MemberActive.Count)
+}
+
+func (g getMonthlyInfo) testWithoutDistribution(t *testing.T) {
+	distributions := &fakeDistributions{dist: &testDistribution}
+	recorder := g.request(t, distributions, "")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got summaryzedMI
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Nil(t, got.Distribution)
+	// Sem distribuicao=true, os zips de remunerações não são lidos.
+	assert.Equal(t, 0, distributions.calls)
+}
+
+func TestGetAgencyYearDistribution(t *testing.T) {
+	tests := getAgencyYearDistribution{}
+	t.Run("Test GetAgencyYearDistribution", tests.testDistribution)
+	t.Run("Test GetAgencyYearDistribution with correction", tests.testCorrection)
+	t.Run("Test GetAgencyYearDistribution without data", tests.testWithoutData)
+}
+
+type getAgencyYearDistribution struct{}
+
+func (g getAgencyYearDistribution) request(t *testing.T, distributions DistributionSource, query string) *httptest.ResponseRecorder {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/distribuicao/:orgao/:ano"+query, nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao", "ano")
+	ctx.SetParamValues("TJAL", "2020")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", distributions, nil, nil)
+	handler.V2GetAgencyYearDistribution(ctx)
+	return recorder
+}
+
+func (g getAgencyYearDistribution) testDistribution(t *testing.T) {
+	recorder := g.request(t, &fakeDistributions{dist: &testDistribution}, "")
+
+	expectedJson := `
+		{
+			"id_orgao": "tjal",
+			"ano": 2020,
+			"distribuicao": {
+				"num_observacoes": 5,
+				"remuneracao_bruta": {"mediana": 30000, "p10": 14000, "p25": 20000, "p75": 40000, "p90": 46000, "p99": 49600, "gini": 0.2666666666666666},
+				"remuneracao_liquida": {"mediana": 24000, "p10": 11200, "p25": 16000, "p75": 32000, "p90": 36800, "p99": 39680, "gini": 0.2666666666666666}
+			}
+		}
+	`
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, expectedJson, recorder.Body.String())
+}
+
+func (g getAgencyYearDistribution) testCorrection(t *testing.T) {
+	distributions := &fakeDistributions{dist: &testDistribution}
+	recorder := g.request(t, distributions, "?corrigir=ipca&base=2020-02")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got agencyYearDistribution
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	// A correção é aplicada a cada par membro/mês pela fonte da distribuição;
+	// o handler não escala as estatísticas já calculadas.
+	assert.NotNil(t, distributions.deflator)
+	assert.Equal(t, "2020-02", distributions.deflator.Base())
+	assert.Equal(t, testDistribution.Gross.Median, got.Distribution.Gross.Median)
+	assert.Equal(t, "2020-02", got.Correction.Base)
+}
+
+func (g getAgencyYearDistribution) testWithoutData(t *testing.T) {
+	for _, distributions := range []DistributionSource{nil, &fakeDistributions{}} {
+		recorder := g.request(t, distributions, "")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestGetGrowth(t *testing.T) {
+	tests := getGrowth{}
+	t.Run("Test growth with gaps and errored collections", tests.testGaps)
+	t.Run("Test GetAgencyGrowth", tests.testAgencyGrowth)
+	t.Run("Test GetGroupGrowth when group does not exist", tests.testGroupNotFound)
+}
+
+type getGrowth struct{}
+
+func growthMI(agency string, year, month, count int, total float64) models.AgencyMonthlyInfo {
+	return models.AgencyMonthlyInfo{
+		AgencyID: agency,
+		Year:     year,
+		Month:    month,
+		Summary:  &models.Summary{Count: count, Remunerations: models.DataSummary{Total: total}},
+		ProcInfo: &coleta.ProcInfo{},
+	}
+}
+
+func (g getGrowth) testGaps(t *testing.T) {
+	p, _ := period.New("2020-01", "2020-03", 0)
+	monthlyInfo := []models.AgencyMonthlyInfo{
+		growthMI("tjal", 2019, 3, 10, 80000),
+		growthMI("tjal", 2019, 12, 10, 100000),
+		growthMI("tjba", 2019, 12, 20, 300000),
+		growthMI("tjal", 2020, 1, 11, 121000),
+		{AgencyID: "tjba", Year: 2020, Month: 1, ProcInfo: &coleta.ProcInfo{Status: 2, Stderr: "timeout"}},
+		growthMI("tjal", 2020, 3, 10, 100000),
+		growthMI("tjba", 2020, 3, 20, 200000),
+	}
+	got := newGrowth([]string{"tjal", "tjba"}, p, monthlyInfo, nil)
+
+	assert.Len(t, got.Months, 3)
+	jan := got.Months[0]
+	assert.Equal(t, "parcial", jan.Status)
+	assert.Equal(t, []string{"tjba"}, jan.ErrorAgencies)
+	assert.Empty(t, jan.MissingAgencies)
+	assert.Equal(t, &growthValues{MemberCount: 11, Total: 121000, PerCapita: 11000}, jan.Values)
+	// A variação mensal considera apenas o tjal, único órgão com dados nos dois meses.
+	assert.Equal(t, "2019-12", jan.MonthOverMonth.ComparedTo)
+	assert.Equal(t, 1, jan.MonthOverMonth.Agencies)
+	assert.True(t, jan.MonthOverMonth.Partial)
+	assert.Equal(t, 21000.0, jan.MonthOverMonth.Total.Absolute)
+	assert.InDelta(t, 21, *jan.MonthOverMonth.Total.Percentage, 1e-9)
+	assert.InDelta(t, 10, *jan.MonthOverMonth.PerCapita.Percentage, 1e-9)
+	assert.Nil(t, jan.YearOverYear)
+
+	feb := got.Months[1]
+	assert.Equal(t, "sem_dados", feb.Status)
+	assert.Equal(t, []string{"tjal", "tjba"}, feb.MissingAgencies)
+	assert.Nil(t, feb.Values)
+
+	// Março não é comparado com janeiro: o mês anterior (fevereiro) não tem dados.
+	mar := got.Months[2]
+	assert.Equal(t, "completo", mar.Status)
+	assert.Equal(t, 30.0, mar.Values.MemberCount)
+	assert.Nil(t, mar.MonthOverMonth)
+
+	assert.Len(t, got.Years, 1)
+	year := got.Years[0]
+	assert.Equal(t, 3, year.MonthsWithData)
+	assert.False(t, year.Complete)
+	assert.Equal(t, &growthValues{MemberCount: 30.5, Total: 421000, PerCapita: 421000.0 / 41}, year.Values)
+	// A variação anual compara apenas março, único mês com dados do tjal nos dois anos.
+	assert.Equal(t, 1, year.YearOverYear.Agencies)
+	assert.True(t, year.YearOverYear.Partial)
+	assert.Equal(t, 20000.0, year.YearOverYear.Total.Absolute)
+	assert.InDelta(t, 25, *year.YearOverYear.Total.Percentage, 1e-9)
+	assert.Equal(t, 0.0, year.YearOverYear.MemberCount.Absolute)
+}
+
+func (g getGrowth) testAgencyGrowth(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetAgency("tjal").Return(&models.Agency{ID: "tjal"}, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjal").Return([]models.AgencyMonthlyInfo{
+		growthMI("tjal", 2018, 2, 10, 90000),
+		growthMI("tjal", 2019, 2, 10, 100000),
+		growthMI("tjal", 2020, 1, 10, 100000),
+		growthMI("tjal", 2020, 2, 10, 110000),
+	}, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/crescimento/:orgao?inicio=2020-02&fim=2020-02", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao")
+	ctx.SetParamValues("tjal")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetAgencyGrowth(ctx)
+
+	expectedJson := `
+		{
+			"orgaos": ["tjal"],
+			"inicio": "2020-02",
+			"fim": "2020-02",
+			"meses": [
+				{
+					"mes": "2020-02",
+					"situacao": "completo",
+					"valores": {"num_membros": 10, "remuneracao_bruta": 110000, "remuneracao_bruta_por_membro": 11000},
+					"variacao_mensal": {
+						"comparado_com": "2020-01",
+						"num_orgaos_comparados": 1,
+						"parcial": false,
+						"num_membros": {"absoluta": 0, "percentual": 0},
+						"remuneracao_bruta": {"absoluta": 10000, "percentual": 10},
+						"remuneracao_bruta_por_membro": {"absoluta": 1000, "percentual": 10}
+					},
+					"variacao_anual": {
+						"comparado_com": "2019-02",
+						"num_orgaos_comparados": 1,
+						"parcial": false,
+						"num_membros": {"absoluta": 0, "percentual": 0},
+						"remuneracao_bruta": {"absoluta": 10000, "percentual": 10},
+						"remuneracao_bruta_por_membro": {"absoluta": 1000, "percentual": 10}
+					}
+				}
+			],
+			"anos": [
+				{
+					"ano": 2020,
+					"meses_com_dados": 2,
+					"completo": false,
+					"valores": {"num_membros": 10, "remuneracao_bruta": 210000, "remuneracao_bruta_por_membro": 10500},
+					"variacao_anual": {
+						"comparado_com": "2019",
+						"num_orgaos_comparados": 1,
+						"parcial": true,
+						"num_membros": {"absoluta": 0, "percentual": 0},
+						"remuneracao_bruta": {"absoluta": 10000, "percentual": 10},
+						"remuneracao_bruta_por_membro": {"absoluta": 1000, "percentual": 10}
+					}
+				}
+			]
+		}
+	`
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, expectedJson, recorder.Body.String())
+}
+
+func (g getGrowth) testGroupNotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/crescimento/grupo/:grupo?inicio=2020-01&fim=2020-12", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("grupo")
+	ctx.SetParamValues("justica-municipal")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetGroupGrowth(ctx)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, `"Grupo não encontrado: justica-municipal"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestGetAnomalies(t *testing.T) {
+	tests := getAnomalies{}
+	t.Run("Test spikes are flagged and seasonal spikes are not", tests.testFindAnomalies)
+	t.Run("Test months without enough baseline are not evaluated", tests.testShortBaseline)
+	t.Run("Test GetAnomalies without agency or group", tests.testWithoutAgencyOrGroup)
+	t.Run("Test GetAnomalies with invalid window", tests.testInvalidWindow)
+}
+
+type getAnomalies struct{}
+
+// anomalySeries gera os meses de 2018 a 2020 do tjal, com 100 membros,
+// variação pequena na remuneração, gratificação natalina em dezembro e uma
+// licença-prêmio de R$ 300 mil em maio de 2020.
+func anomalySeries() []models.AgencyMonthlyInfo {
+	var mis []models.AgencyMonthlyInfo
+	for year := 2018; year <= 2020; year++ {
+		for month := 1; month <= 12; month++ {
+			items := models.ItemSummary{FoodAllowance: 50000 + float64(month*100)}
+			if month == 12 {
+				items.ChristmasBonus = 500000 + float64(year-2018)*10000
+			}
+			if year == 2020 && month == 5 {
+				items.BonusLicense = 300000
+			}
+			total := 1000000 + float64((month*7)%5)*2000 + items.FoodAllowance + items.ChristmasBonus + items.BonusLicense
+			mis = append(mis, models.AgencyMonthlyInfo{
+				AgencyID: "tjal",
+				Year:     year,
+				Month:    month,
+				Summary: &models.Summary{
+					Count:         100,
+					Remunerations: models.DataSummary{Total: total},
+					ItemSummary:   items,
+				},
+				ProcInfo: &coleta.ProcInfo{},
+			})
+		}
+	}
+	return mis
+}
+
+func (g getAnomalies) testFindAnomalies(t *testing.T) {
+	p, _ := period.New("2020-01", "2020-12", 0)
+	found := findAnomalies(p, anomalySeries(), defaultAnomalyThreshold, defaultAnomalyWindow, nil)
+
+	var metrics []string
+	for _, a := range found {
+		assert.Equal(t, "2020-05", a.Month, a.Metric)
+		metrics = append(metrics, a.Metric)
+	}
+	assert.ElementsMatch(t, []string{"remuneracao_bruta", "remuneracao_bruta_por_membro", "licenca_premio"}, metrics)
+
+	for _, a := range found {
+		if a.Metric != "remuneracao_bruta" {
+			continue
+		}
+		assert.Equal(t, "movel", a.Baseline)
+		assert.Len(t, a.BaselineMonths, 11) // dezembro fica fora da linha de base móvel
+		assert.Equal(t, "licenca_premio", a.DrivingItem)
+		assert.Greater(t, a.Score, defaultAnomalyThreshold)
+		assert.Less(t, a.ExpectedRange.Max, a.Value)
+		assert.Greater(t, a.ExpectedRange.Max, a.BaselineMedian)
+	}
+}
+
+func (g getAnomalies) testShortBaseline(t *testing.T) {
+	p, _ := period.New("2018-01", "2018-12", 0)
+	assert.Empty(t, findAnomalies(p, anomalySeries(), defaultAnomalyThreshold, defaultAnomalyWindow, nil))
+}
+
+func (g getAnomalies) request(t *testing.T, query string) *httptest.ResponseRecorder {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/anomalias?"+query, nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetAnomalies(ctx)
+	return recorder
+}
+
+func (g getAnomalies) testWithoutAgencyOrGroup(t *testing.T) {
+	recorder := g.request(t, "inicio=2020-01&fim=2020-12")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"Informe o parâmetro orgao ou grupo"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func (g getAnomalies) testInvalidWindow(t *testing.T) {
+	recorder := g.request(t, "orgao=tjal&inicio=2020-01&fim=2020-12&janela=3")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"Parâmetro janela=3 inválido. Use um valor entre 6 e 36"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestCompareAgencies(t *testing.T) {
+	tests := compareAgencies{}
+	t.Run("Test CompareAgencies aligns the series", tests.testAlignedSeries)
+	t.Run("Test CompareAgencies with too many agencies", tests.testTooManyAgencies)
+}
+
+type compareAgencies struct{}
+
+func (g compareAgencies) testAlignedSeries(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	tjpb := growthMI("tjpb", 2020, 1, 10, 200000)
+	tjpb.Summary.ItemSummary.FoodAllowance = 10000
+	tjpb.Score = &models.Score{Score: 0.5, CompletenessScore: 0.4, EasinessScore: 0.6}
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetAgency("tjpb").Return(&models.Agency{ID: "tjpb"}, nil).Times(1)
+	dbMock.EXPECT().GetAgency("tjpe").Return(&models.Agency{ID: "tjpe"}, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjpb").Return([]models.AgencyMonthlyInfo{
+		growthMI("tjpb", 2019, 12, 10, 190000), tjpb, growthMI("tjpb", 2020, 2, 10, 210000),
+	}, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjpe").Return([]models.AgencyMonthlyInfo{
+		{AgencyID: "tjpe", Year: 2020, Month: 1, ProcInfo: &coleta.ProcInfo{Status: 2, Stderr: "timeout"}},
+	}, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/comparar?orgaos=TJPB,tjpe,tjpb&inicio=2020-01&fim=2020-02", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2CompareAgencies(ctx)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got comparison
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, []string{"tjpb", "tjpe"}, got.Agencies)
+	assert.Equal(t, []string{"2020-01", "2020-02"}, got.Months)
+	assert.Equal(t, []string{"2020-01", "2020-02"}, got.IncompleteMonths)
+	assert.Len(t, got.Series, 2)
+
+	pb := got.Series[0]
+	assert.Equal(t, []string{"com_dados", "com_dados"}, pb.Status)
+	assert.Empty(t, pb.MissingMonths)
+	assert.Equal(t, 210000.0, *pb.Remunerations[1])
+	assert.Equal(t, 20000.0, *pb.PerCapita[0])
+	assert.Equal(t, &score{Score: 0.5, CompletenessScore: 0.4, EasinessScore: 0.6}, pb.Score[0])
+	assert.Nil(t, pb.Score[1])
+	assert.Equal(t, "auxilio_alimentacao", pb.Items[0].Item)
+	assert.Equal(t, 1000.0, *pb.Items[0].PerCapita[0])
+
+	pe := got.Series[1]
+	assert.Equal(t, []string{"erro_coleta", "sem_dados"}, pe.Status)
+	assert.Equal(t, []string{"2020-01", "2020-02"}, pe.MissingMonths)
+	assert.Equal(t, []*int{nil, nil}, pe.MemberCount)
+	assert.Equal(t, []*float64{nil, nil}, pe.Items[0].Total)
+}
+
+func (g compareAgencies) testTooManyAgencies(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/comparar?orgaos=a,b,c,d,e,f,g,h,i,j,k&inicio=2020-01&fim=2020-02", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2CompareAgencies(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"Informe de 1 a 10 órgãos no parâmetro orgaos"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestGetRemunerationRanking(t *testing.T) {
+	tests := getRemunerationRanking{}
+	t.Run("Test ranking with incomplete years", tests.testRanking)
+	t.Run("Test ranking including incomplete years", tests.testIncludeIncomplete)
+	t.Run("Test GetRemunerationRanking with invalid metric", tests.testInvalidMetric)
+}
+
+type getRemunerationRanking struct{}
+
+func (g getRemunerationRanking) request(t *testing.T, query string) *httptest.ResponseRecorder {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	agencies := []models.Agency{{ID: "tjal", Name: "TJAL"}, {ID: "tjba", Name: "TJBA"}, {ID: "tjpb", Name: "TJPB"}, {ID: "tjpe", Name: "TJPE"}}
+	summaries := map[string][]models.AnnualSummary{
+		"tjal": {
+			{Year: 2022, RemunerationsPerCapita: 30000, NumMonthsWithData: 12},
+			{Year: 2023, RemunerationsPerCapita: 40000, NumMonthsWithData: 12},
+		},
+		"tjba": {
+			{Year: 2022, RemunerationsPerCapita: 35000, NumMonthsWithData: 12},
+			{Year: 2023, RemunerationsPerCapita: 38000, NumMonthsWithData: 12},
+		},
+		"tjpb": {
+			{Year: 2023, RemunerationsPerCapita: 50000, NumMonthsWithData: 5},
+		},
+	}
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetOPJ("Estadual").Return(agencies, nil).Times(1)
+	for _, a := range agencies {
+		dbMock.EXPECT().GetAnnualSummary(a.ID).Return(summaries[a.ID], nil).Times(1)
+	}
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/ranking/remuneracao?"+query, nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetRemunerationRanking(ctx)
+	return recorder
+}
+
+func (g getRemunerationRanking) testRanking(t *testing.T) {
+	recorder := g.request(t, "ano=2023&grupo=justica-estadual")
+
+	expectedJson := `
+		{
+			"ano": 2023,
+			"metrica": "remuneracoes_por_membro",
+			"grupo": "justica-estadual",
+			"inclui_incompletos": false,
+			"orgaos": [
+				{"id_orgao": "tjal", "nome": "TJAL", "posicao": 1, "valor": 40000, "percentil": 100, "meses_com_dados": 12, "ano_incompleto": false, "posicao_ano_anterior": 2, "variacao_posicao": 1},
+				{"id_orgao": "tjba", "nome": "TJBA", "posicao": 2, "valor": 38000, "percentil": 0, "meses_com_dados": 12, "ano_incompleto": false, "posicao_ano_anterior": 1, "variacao_posicao": -1},
+				{"id_orgao": "tjpb", "nome": "TJPB", "posicao": null, "valor": 50000, "percentil": null, "meses_com_dados": 5, "ano_incompleto": true, "posicao_ano_anterior": null, "variacao_posicao": null}
+			],
+			"orgaos_sem_dados": ["tjpe"]
+		}
+	`
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, expectedJson, recorder.Body.String())
+}
+
+func (g getRemunerationRanking) testIncludeIncomplete(t *testing.T) {
+	recorder := g.request(t, "ano=2023&grupo=justica-estadual&incluir_incompletos=true")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got ranking
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, "tjpb", got.Agencies[0].AgencyID)
+	assert.Equal(t, 1, *got.Agencies[0].Position)
+	assert.True(t, got.Agencies[0].Incomplete)
+	assert.Equal(t, 50.0, *got.Agencies[1].Percentile)
+}
+
+func (g getRemunerationRanking) testInvalidMetric(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/ranking/remuneracao?ano=2023&metrica=media", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetRemunerationRanking(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Parâmetro metrica=media inválido")
+}
+
+func TestGetStateAggregates(t *testing.T) {
+	tests := getStateAggregates{}
+	t.Run("Test GetStateYear", tests.testYear)
+	t.Run("Test GetStateSummary", tests.testSummary)
+	t.Run("Test GetStateSummary with correction", tests.testSummaryCorrection)
+	t.Run("Test GetStateYear with unknown UF", tests.testUnknownUF)
+}
+
+type getStateAggregates struct{}
+
+var stateAgencies = []models.Agency{{ID: "tjpb", Name: "TJPB"}, {ID: "mppb", Name: "MPPB"}}
+
+func (g getStateAggregates) request(t *testing.T, path string, mock func(dbMock *database.MockInterface), handle func(handler, echo.Context) error, params ...string) *httptest.ResponseRecorder {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	mock(dbMock)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames(params[:len(params)/2]...)
+	ctx.SetParamValues(params[len(params)/2:]...)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handle(*NewHandler(client, "", "", "", "", nil, nil, nil), ctx)
+	return recorder
+}
+
+func (g getStateAggregates) testYear(t *testing.T) {
+	tjpb1 := growthMI("tjpb", 2023, 1, 10, 300000)
+	tjpb1.Summary.ItemSummary = models.ItemSummary{FoodAllowance: 10000}
+	mppb1 := growthMI("mppb", 2023, 1, 5, 100000)
+	mppb1.Summary.ItemSummary = models.ItemSummary{FoodAllowance: 5000}
+	monthlyInfo := map[string][]models.AgencyMonthlyInfo{
+		"tjpb": {growthMI("tjpb", 2022, 12, 10, 900000), tjpb1, growthMI("tjpb", 2023, 2, 10, 200000)},
+		"mppb": {mppb1, {AgencyID: "mppb", Year: 2023, Month: 2, ProcInfo: &coleta.ProcInfo{Status: 2, Stderr: "timeout"}}},
+	}
+	recorder := g.request(t, "/v2/uf/pb/2023", func(dbMock *database.MockInterface) {
+		dbMock.EXPECT().GetAgenciesByUF("PB").Return(stateAgencies, nil).Times(1)
+		for _, a := range stateAgencies {
+			dbMock.EXPECT().GetAllAgencyCollection(a.ID).Return(monthlyInfo[a.ID], nil).Times(1)
+		}
+	}, handler.V2GetStateYear, "uf", "ano", "pb", "2023")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got aggregate
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, "PB", got.UF)
+	assert.Equal(t, []string{"mppb", "tjpb"}, got.Agencies)
+	assert.Len(t, got.Months, 12)
+
+	jan := got.Months[0]
+	assert.Equal(t, 15, jan.Totals.MemberCount)
+	assert.Equal(t, 400000.0, jan.Totals.Remunerations)
+	assert.InDelta(t, 400000.0/15, jan.Totals.RemunerationsPerCapita, 1e-9)
+	assert.Equal(t, 15000.0, jan.Totals.ItemSummary.FoodAllowance)
+	assert.Equal(t, monthCoverage{AgenciesWithData: 2, TotalAgencies: 2, NoData: []string{}, CrawlingErrors: []string{}}, jan.Coverage)
+
+	feb := got.Months[1]
+	assert.Equal(t, 10, feb.Totals.MemberCount)
+	assert.Equal(t, 200000.0, feb.Totals.Remunerations)
+	assert.Equal(t, monthCoverage{AgenciesWithData: 1, TotalAgencies: 2, NoData: []string{}, CrawlingErrors: []string{"mppb"}}, feb.Coverage)
+
+	assert.Equal(t, []string{"mppb", "tjpb"}, got.Months[2].Coverage.NoData)
+	assert.Equal(t, 0.0, got.Months[2].Totals.RemunerationsPerCapita)
+
+	assert.Equal(t, "tjpb", got.PerAgency[1].ID)
+	assert.Equal(t, 2, got.PerAgency[1].MonthsWithData)
+	assert.Equal(t, 10, got.PerAgency[1].Totals.MemberCount)
+	assert.Equal(t, 500000.0, got.PerAgency[1].Totals.Remunerations)
+	assert.Equal(t, 25000.0, got.PerAgency[1].Totals.RemunerationsPerCapita)
+}
+
+func (g getStateAggregates) testSummary(t *testing.T) {
+	summaries := map[string][]models.AnnualSummary{
+		"tjpb": {
+			{Year: 2022, AverageCount: 10, TotalCount: 120, Remunerations: 3600000, NumMonthsWithData: 12},
+			{Year: 2023, AverageCount: 10, TotalCount: 60, Remunerations: 2000000, NumMonthsWithData: 6},
+		},
+		"mppb": {
+			{Year: 2023, AverageCount: 5, TotalCount: 60, Remunerations: 1000000, NumMonthsWithData: 12, ItemSummary: models.ItemSummary{HealthAllowance: 1200}},
+		},
+	}
+	recorder := g.request(t, "/v2/uf/pb/resumo", func(dbMock *database.MockInterface) {
+		dbMock.EXPECT().GetAgenciesByUF("PB").Return(stateAgencies, nil).Times(1)
+		for _, a := range stateAgencies {
+			dbMock.EXPECT().GetAnnualSummary(a.ID).Return(summaries[a.ID], nil).Times(1)
+		}
+	}, handler.V2GetStateSummary, "uf", "pb")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got aggregateSummary
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, "PB", got.UF)
+	assert.Len(t, got.Years, 2)
+
+	y2022 := got.Years[0]
+	assert.Equal(t, 2022, y2022.Year)
+	assert.Equal(t, []string{"mppb"}, y2022.Coverage.NoData)
+	assert.Equal(t, 1, y2022.Coverage.AgenciesWithData)
+	assert.Len(t, y2022.PerAgency, 1)
+
+	y2023 := got.Years[1]
+	assert.Equal(t, 15, y2023.Totals.MemberCount)
+	assert.Equal(t, 3000000.0, y2023.Totals.Remunerations)
+	assert.Equal(t, 25000.0, y2023.Totals.RemunerationsPerCapita)
+	assert.Equal(t, 1200.0, y2023.Totals.ItemSummary.HealthAllowance)
+	assert.Equal(t, yearCoverage{AgenciesWithData: 2, TotalAgencies: 2, NoData: []string{}, Incomplete: []string{"tjpb"}}, y2023.Coverage)
+	assert.Equal(t, "mppb", y2023.PerAgency[0].ID)
+	assert.Equal(t, 5, y2023.PerAgency[0].Totals.MemberCount)
+}
+
+func (g getStateAggregates) testSummaryCorrection(t *testing.T) {
+	summaries := map[string][]models.AnnualSummary{
+		"tjpb": {{Year: 2023, AverageCount: 10, TotalCount: 60, Remunerations: 2000000, NumMonthsWithData: 6}},
+		"mppb": {{Year: 2023, AverageCount: 5, TotalCount: 60, Remunerations: 1000000, NumMonthsWithData: 12}},
+	}
+	collections := map[string][]models.AgencyMonthlyInfo{}
+	for m := 1; m <= 12; m++ {
+		collections["mppb"] = append(collections["mppb"], growthMI("mppb", 2023, m, 5, 1000000/12.0))
+		if m <= 6 {
+			collections["tjpb"] = append(collections["tjpb"], growthMI("tjpb", 2023, m, 10, 2000000/6.0))
+		} else {
+			collections["tjpb"] = append(collections["tjpb"], models.AgencyMonthlyInfo{AgencyID: "tjpb", Year: 2023, Month: m, ProcInfo: &coleta.ProcInfo{Status: 2, Stderr: "timeout"}})
+		}
+	}
+	recorder := g.request(t, "/v2/uf/pb/resumo?corrigir=ipca&base=2023-12", func(dbMock *database.MockInterface) {
+		dbMock.EXPECT().GetAgenciesByUF("PB").Return(stateAgencies, nil).Times(1)
+		for _, a := range stateAgencies {
+			dbMock.EXPECT().GetAnnualSummary(a.ID).Return(summaries[a.ID], nil).Times(1)
+			dbMock.EXPECT().GetAllAgencyCollection(a.ID).Return(collections[a.ID], nil).Times(1)
+		}
+	}, handler.V2GetStateSummary, "uf", "pb")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got aggregateSummary
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	d, _ := tables.NewDeflator("ipca", "2023-12")
+	// Os valores do tjpb são corrigidos só pelos fatores dos meses com dados.
+	tjpb, mppb := 2000000*d.YearFactor(2023, 1, 2, 3, 4, 5, 6), 1000000*d.YearFactor(2023)
+	assert.Equal(t, "mppb", got.Years[0].PerAgency[0].ID)
+	assert.InDelta(t, mppb, got.Years[0].PerAgency[0].Totals.Remunerations, 1e-6)
+	assert.InDelta(t, tjpb, got.Years[0].PerAgency[1].Totals.Remunerations, 1e-6)
+	assert.InDelta(t, tjpb+mppb, got.Years[0].Totals.Remunerations, 1e-6)
+	assert.NotEqual(t, 2000000*d.YearFactor(2023), got.Years[0].PerAgency[1].Totals.Remunerations)
+}
+
+func (g getStateAggregates) testUnknownUF(t *testing.T) {
+	recorder := g.request(t, "/v2/uf/xx/2023", func(dbMock *database.MockInterface) {},
+		handler.V2GetStateYear, "uf", "ano", "xx", "2023")
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGetGroupYear(t *testing.T) {
+	tests := getGroupYear{}
+	t.Run("Test GetGroupYear", tests.testGroupYear)
+	t.Run("Test GetGroupYear with unknown group", tests.testUnknownGroup)
+}
+
+type getGroupYear struct{}
+
+func (g getGroupYear) testGroupYear(t *testing.T) {
+	agencies := []models.Agency{{ID: "trt13", Name: "TRT13"}, {ID: "trt1", Name: "TRT1"}}
+	monthlyInfo := map[string][]models.AgencyMonthlyInfo{
+		"trt1":  {growthMI("trt1", 2023, 1, 100, 4000000), {AgencyID: "trt1", Year: 2023, Month: 3, ProcInfo: &coleta.ProcInfo{Status: 2, Stderr: "timeout"}}},
+		"trt13": {growthMI("trt13", 2023, 1, 50, 1000000), {AgencyID: "trt13", Year: 2023, Month: 2, ProcInfo: &coleta.ProcInfo{Status: 4, Stderr: "indisponível"}}},
+	}
+	recorder := getStateAggregates{}.request(t, "/v2/grupo/justica-do-trabalho/2023", func(dbMock *database.MockInterface) {
+		dbMock.EXPECT().GetOPJ("Trabalho").Return(agencies, nil).Times(1)
+		for _, a := range agencies {
+			dbMock.EXPECT().GetAllAgencyCollection(a.ID).Return(monthlyInfo[a.ID], nil).Times(1)
+		}
+	}, handler.V2GetGroupYear, "grupo", "ano", "justica-do-trabalho", "2023")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got aggregate
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, "justica-do-trabalho", got.Group)
+	assert.Empty(t, got.UF)
+	assert.Equal(t, []string{"trt1", "trt13"}, got.Agencies)
+	assert.Equal(t, 150, got.Months[0].Totals.MemberCount)
+	assert.Equal(t, 5000000.0, got.Months[0].Totals.Remunerations)
+	assert.InDelta(t, 5000000.0/150, got.Months[0].Totals.RemunerationsPerCapita, 1e-9)
+	assert.Equal(t, 2, got.Months[0].Coverage.AgenciesWithData)
+	// Dados indisponíveis não são erro de coleta.
+	assert.Equal(t, monthCoverage{TotalAgencies: 2, NoData: []string{"trt1", "trt13"}, CrawlingErrors: []string{}}, got.Months[1].Coverage)
+	assert.Equal(t, monthCoverage{TotalAgencies: 2, NoData: []string{"trt13"}, CrawlingErrors: []string{"trt1"}}, got.Months[2].Coverage)
+}
+
+func (g getGroupYear) testUnknownGroup(t *testing.T) {
+	recorder := getStateAggregates{}.request(t, "/v2/grupo/justica-desportiva/2023", func(dbMock *database.MockInterface) {},
+		handler.V2GetGroupYear, "grupo", "ano", "justica-desportiva", "2023")
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGetTaxonomy(t *testing.T) {
+	tests := getTaxonomy{}
+	t.Run("Test GetGroups", tests.testGroups)
+	t.Run("Test GetUFs", tests.testUFs)
+}
+
+type getTaxonomy struct{}
+
+var taxonomyAgencies = []models.Agency{
+	{ID: "tjpb", Type: "Estadual", Entity: "Tribunal", UF: "PB"},
+	{ID: "mppb", Type: "Ministério", Entity: "Ministério", UF: "PB"},
+	{ID: "trt13", Type: "Trabalho", Entity: "Tribunal", UF: "PB"},
+	{ID: "tjal", Type: "Estadual", Entity: "Tribunal", UF: "AL"},
+	{ID: "cnj", Type: "Conselho", Entity: "Conselho"},
+}
+
+func (g getTaxonomy) request(t *testing.T, path string, handle func(handler, echo.Context) error) *httptest.ResponseRecorder {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetAllAgencies().Return(taxonomyAgencies, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handle(*NewHandler(client, "", "", "https://example.com/bandeiras/", "", nil, nil, nil), ctx)
+	return recorder
+}
+
+func (g getTaxonomy) testGroups(t *testing.T) {
+	recorder := g.request(t, "/v2/grupos", handler.V2GetGroups)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got []groupInfo
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Len(t, got, 8)
+	assert.Equal(t, groupInfo{
+		Slug:         "justica-estadual",
+		Label:        "Justiça Estadual",
+		Jurisdiction: "Estadual",
+		AgencyCount:  2,
+		Entities:     []entityCount{{Code: "Tribunal", Label: "Tribunal", AgencyCount: 2}},
+	}, got[0])
+	for _, group := range got {
+		if group.Slug == "justica-militar" {
+			assert.Equal(t, 0, group.AgencyCount)
+			assert.Empty(t, group.Entities)
+		}
+	}
+}
+
+func (g getTaxonomy) testUFs(t *testing.T) {
+	recorder := g.request(t, "/v2/ufs", handler.V2GetUFs)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got []ufInfo
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Len(t, got, 27)
+	for _, uf := range got {
+		if uf.Code == "PB" {
+			assert.Equal(t, ufInfo{
+				Code:        "PB",
+				Slug:        "pb",
+				Name:        "Paraíba",
+				Region:      "Nordeste",
+				FlagURL:     "https://example.com/bandeiras/pb.svg",
+				AgencyCount: 3,
+				Entities: []entityCount{
+					{Code: "Tribunal", Label: "Tribunal", AgencyCount: 2},
+					{Code: "Ministério", Label: "Ministério Público", AgencyCount: 1},
+				},
+			}, uf)
+		}
+	}
+}
+
+func TestGetCoverage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	agencies := []models.Agency{{ID: "tjpb"}, {ID: "tjal"}}
+	manual := growthMI("tjal", 2023, 1, 10, 100000)
+	manual.ManualCollection = true
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetOPJ("Estadual").Return(agencies, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjpb").Return([]models.AgencyMonthlyInfo{
+		growthMI("tjpb", 2022, 11, 10, 100000),
+		growthMI("tjpb", 2022, 12, 10, 100000),
+		{AgencyID: "tjpb", Year: 2023, Month: 1, ProcInfo: &coleta.ProcInfo{Status: 4, Stderr: "dados indisponíveis"}},
+		{AgencyID: "tjpb", Year: 2023, Month: 2, ProcInfo: &coleta.ProcInfo{Status: 2, Stderr: "timeout"}},
+	}, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjal").Return([]models.AgencyMonthlyInfo{manual}, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/cobertura?inicio=2022-12&fim=2023-02&grupo=justica-estadual", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetCoverage(ctx)
+
+	expectedJson := `
+		{
+			"grupo": "justica-estadual",
+			"inicio": "2022-12",
+			"fim": "2023-02",
+			"meses": ["2022-12", "2023-01", "2023-02"],
+			"orgaos": [
+				{
+					"id_orgao": "tjal",
+					"meses": [{"situacao": "nao_coletado"}, {"situacao": "coleta_manual"}, {"situacao": "nao_coletado"}],
+					"totais": {"ok": 0, "coleta_manual": 1, "erro_coleta": 0, "indisponivel": 0, "nao_coletado": 2}
+				},
+				{
+					"id_orgao": "tjpb",
+					"meses": [{"situacao": "ok"}, {"situacao": "indisponivel", "status_coleta": 4}, {"situacao": "erro_coleta", "status_coleta": 2}],
+					"totais": {"ok": 1, "coleta_manual": 0, "erro_coleta": 1, "indisponivel": 1, "nao_coletado": 0}
+				}
+			],
+			"totais_por_mes": [
+				{"ok": 1, "coleta_manual": 0, "erro_coleta": 0, "indisponivel": 0, "nao_coletado": 1},
+				{"ok": 0, "coleta_manual": 1, "erro_coleta": 0, "indisponivel": 1, "nao_coletado": 0},
+				{"ok": 0, "coleta_manual": 0, "erro_coleta": 1, "indisponivel": 0, "nao_coletado": 1}
+			],
+			"totais": {"ok": 1, "coleta_manual": 1, "erro_coleta": 1, "indisponivel": 1, "nao_coletado": 2}
+		}
+	`
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, expectedJson, recorder.Body.String())
+}
+
+func TestGetCrawlingErrors(t *testing.T) {
+	tests := getCrawlingErrors{}
+	t.Run("Test GetCrawlingErrors", tests.testGroupErrors)
+	t.Run("Test normalizeErrorMessage", tests.testNormalize)
+}
+
+type getCrawlingErrors struct{}
+
+func crawlErrorMI(agency string, year, month int, status int32, version, stderr string) models.AgencyMonthlyInfo {
+	return models.AgencyMonthlyInfo{
+		AgencyID:       agency,
+		Year:           year,
+		Month:          month,
+		CrawlerRepo:    "https://github.com/dadosjusbr/coletor-" + agency,
+		CrawlerVersion: version,
+		ProcInfo:       &coleta.ProcInfo{Status: status, Stderr: stderr, Cmd: "coletor"},
+	}
+}
+
+func (g getCrawlingErrors) testGroupErrors(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	agencies := []models.Agency{{ID: "tjpb"}, {ID: "tjal"}}
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetOPJ("Estadual").Return(agencies, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjpb").Return([]models.AgencyMonthlyInfo{
+		crawlErrorMI("tjpb", 2022, 12, 2, "abc123", "TimeoutError: https://tjpb.jus.br demorou 30s"),
+		crawlErrorMI("tjpb", 2023, 3, 2, "abc123", "Traceback:\n  File x.py, line 10\nTimeoutError: https://tjpb.jus.br/folha?mes=3 demorou 30s\n"),
+		crawlErrorMI("tjpb", 2023, 1, 2, "abc123", "Traceback:\n  File x.py, line 12\nTimeoutError: https://tjpb.jus.br/folha?mes=1 demorou 31s\n"),
+		crawlErrorMI("tjpb", 2023, 2, 4, "abc123", "planilha indisponível"),
+		growthMI("tjpb", 2023, 4, 10, 100000),
+		crawlErrorMI("tjpb", 2023, 5, 2, "def456", "TimeoutError: https://tjpb.jus.br demorou 30s"),
+	}, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjal").Return([]models.AgencyMonthlyInfo{
+		crawlErrorMI("tjal", 2023, 6, 5, "aaa111", "panic: index out of range [3] with length 3"),
+	}, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/erros?inicio=2023-01&fim=2023-12&grupo=justica-estadual", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetCrawlingErrors(ctx)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got crawlingErrors
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, "justica-estadual", got.Group)
+	assert.Equal(t, 5, got.Total)
+	assert.Len(t, got.Errors, 4)
+	assert.Equal(t, crawlingErrorGroup{
+		Agency:          "tjpb",
+		Status:          2,
+		CrawlerRepo:     "https://github.com/dadosjusbr/coletor-tjpb",
+		CrawlerVersion:  "abc123",
+		Message:         "TimeoutError: <url> demorou <n>s",
+		Example:         "Traceback:\n  File x.py, line 12\nTimeoutError: https://tjpb.jus.br/folha?mes=1 demorou 31s",
+		Cmd:             "coletor",
+		Count:           2,
+		FirstOccurrence: "2023-01",
+		LastOccurrence:  "2023-03",
+		Months:          []string{"2023-01", "2023-03"},
+	}, got.Errors[0])
+	// A mesma mensagem em outra versão do coletor é outro grupo.
+	assert.Equal(t, "def456", got.Errors[3].CrawlerVersion)
+	assert.Equal(t, []crawlingErrorCount{{Agency: "tjpb", Count: 4}, {Agency: "tjal", Count: 1}}, got.PerAgency)
+	assert.Len(t, got.PerStatus, 3)
+	assert.Equal(t, int32(2), *got.PerStatus[0].Status)
+	assert.Equal(t, 3, got.PerStatus[0].Count)
+}
+
+func (g getCrawlingErrors) testNormalize(t *testing.T) {
+	assert.Equal(t, "panic: index out of range [<n>] with length <n>", normalizeErrorMessage("panic: index out of range [3] with length 3\n\ngoroutine 1 [running]:\nmain.main()\n\t/src/main.go:42 +0x1d\n"))
+	assert.Equal(t, "erro em <hex>", normalizeErrorMessage("linha 1\n  erro   em 0xc000012345  \n\n"))
+	assert.Equal(t, "", normalizeErrorMessage(""))
+}
+
+func TestGetCrawlerHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	collection := func(year, month int, crawlerVersion string, duration float64, status int32) models.AgencyMonthlyInfo {
+		mi := growthMI("tjpb", year, month, 10, 100000)
+		mi.CrawlerRepo = "https://github.com/dadosjusbr/coletor-tjpb"
+		mi.CrawlerVersion = crawlerVersion
+		mi.ParserRepo = "https://github.com/dadosjusbr/parser-tjpb"
+		mi.ParserVersion = "p1"
+		mi.Duration = duration
+		mi.CrawlingTimestamp = timestamppb.New(time.Date(year, time.Month(month)+1, 5, 0, 0, 0, 0, time.UTC))
+		if status != 0 {
+			mi.Summary = nil
+			mi.ProcInfo = &coleta.ProcInfo{Status: status, Stderr: "erro"}
+		}
+		return mi
+	}
+	collections := []models.AgencyMonthlyInfo{
+		collection(2023, 4, "bbb", 30, 0),
+		collection(2023, 1, "aaa", 10, 0),
+		collection(2023, 2, "aaa", 20, 2),
+		collection(2023, 3, "bbb", 50, 4),
+		collection(2023, 6, "aaa", 60, 0),
+		{AgencyID: "tjpb", Year: 2022, Month: 12, ProcInfo: &coleta.ProcInfo{}},
+	}
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetAgency("tjpb").Return(&models.Agency{ID: "tjpb"}, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjpb").Return(collections, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/coletores/tjpb", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao")
+	ctx.SetParamValues("tjpb")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetCrawlerHistory(ctx)
+
+	expectedJson := `
+		{
+			"id_orgao": "tjpb",
+			"coletores": [
+				{
+					"repositorio": "https://github.com/dadosjusbr/coletor-tjpb",
+					"versao": "aaa",
+					"commit": "https://github.com/dadosjusbr/coletor-tjpb/commit/aaa",
+					"periodos": [{"inicio": "2023-01", "fim": "2023-02"}, {"inicio": "2023-06", "fim": "2023-06"}],
+					"coletas": 3,
+					"coletas_com_erro": 1,
+					"coletas_indisponiveis": 0,
+					"taxa_erro": 0.3333333333333333,
+					"duracao_segundos": {"min": 10, "mediana": 20, "media": 30, "max": 60},
+					"primeira_coleta": "2023-02-05T00:00:00Z",
+					"ultima_coleta": "2023-07-05T00:00:00Z"
+				},
+				{
+					"repositorio": "https://github.com/dadosjusbr/coletor-tjpb",
+					"versao": "bbb",
+					"commit": "https://github.com/dadosjusbr/coletor-tjpb/commit/bbb",
+					"periodos": [{"inicio": "2023-03", "fim": "2023-04"}],
+					"coletas": 2,
+					"coletas_com_erro": 0,
+					"coletas_indisponiveis": 1,
+					"taxa_erro": 0,
+					"duracao_segundos": {"min": 30, "mediana": 40, "media": 40, "max": 50},
+					"primeira_coleta": "2023-04-05T00:00:00Z",
+					"ultima_coleta": "2023-05-05T00:00:00Z"
+				}
+			],
+			"parsers": [
+				{
+					"repositorio": "https://github.com/dadosjusbr/parser-tjpb",
+					"versao": "p1",
+					"commit": "https://github.com/dadosjusbr/parser-tjpb/commit/p1",
+					"periodos": [{"inicio": "2023-01", "fim": "2023-04"}, {"inicio": "2023-06", "fim": "2023-06"}],
+					"coletas": 5,
+					"coletas_com_erro": 1,
+					"coletas_indisponiveis": 1,
+					"taxa_erro": 0.2,
+					"duracao_segundos": {"min": 10, "mediana": 30, "media": 34, "max": 60},
+					"primeira_coleta": "2023-02-05T00:00:00Z",
+					"ultima_coleta": "2023-07-05T00:00:00Z"
+				}
+			],
+			"coletas_sem_versao_coletor": 1,
+			"coletas_sem_versao_parser": 1
+		}
+	`
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, expectedJson, recorder.Body.String())
+}
+
+func TestGetFreshness(t *testing.T) {
+	tests := getFreshness{}
+	t.Run("Test late agencies", tests.testLateAgencies)
+	t.Run("Test GetFreshness with a crawling error", tests.testCrawlingError)
+	t.Run("Test GetFreshness with invalid delay", tests.testInvalidDelay)
+}
+
+type getFreshness struct{}
+
+func (g getFreshness) testLateAgencies(t *testing.T) {
+	checked := int64(1696118400)
+	agencies := []models.Agency{
+		{ID: "tjpb", Name: "TJPB"},
+		{ID: "tjal", Name: "TJAL", OmbudsmanURL: "https://www.tjal.jus.br/ouvidoria", Collecting: []models.Collecting{
+			{Timestamp: &checked, Description: []string{"Órgão não publica os dados desde junho"}},
+		}},
+		{ID: "tjba", Name: "TJBA"},
+		{ID: "tjse", Name: "TJSE"},
+	}
+	monthlyInfo := map[string][]models.AgencyMonthlyInfo{
+		"tjpb": {growthMI("tjpb", 2023, 8, 10, 100000), growthMI("tjpb", 2023, 9, 10, 100000)},
+		"tjal": {
+			growthMI("tjal", 2023, 5, 10, 100000),
+			{AgencyID: "tjal", Year: 2023, Month: 6, ProcInfo: &coleta.ProcInfo{Status: 4, Stderr: "indisponível"}},
+		},
+		"tjba": {
+			growthMI("tjba", 2023, 7, 10, 100000),
+			{AgencyID: "tjba", Year: 2023, Month: 8, ProcInfo: &coleta.ProcInfo{Status: 2, Stderr: "timeout"}},
+		},
+	}
+	got := newFreshness(agencies, monthlyInfo, period.YearMonth{Year: 2023, Month: 10}, 2)
+
+	assert.Equal(t, "2023-08", got.ExpectedMonth)
+	assert.Equal(t, []string{"tjpb"}, got.UpToDate)
+	assert.Len(t, got.Late, 3)
+	assert.Equal(t, lateAgency{ID: "tjse", Name: "TJSE", Reasons: []string{}}, got.Late[0])
+
+	lastMonth, monthsLate := "2023-05", 3
+	assert.Equal(t, lateAgency{
+		ID:               "tjal",
+		Name:             "TJAL",
+		LastMonth:        &lastMonth,
+		MonthsLate:       &monthsLate,
+		Reasons:          []string{"Órgão não publica os dados desde junho"},
+		ReasonsTimestamp: &checked,
+		OmbudsmanURL:     "https://www.tjal.jus.br/ouvidoria",
+	}, got.Late[1])
+
+	assert.Equal(t, "tjba", got.Late[2].ID)
+	assert.Equal(t, 1, *got.Late[2].MonthsLate)
+	assert.True(t, got.Late[2].CrawlingError)
+}
+
+func (g getFreshness) testCrawlingError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetOPJ("Estadual").Return([]models.Agency{{ID: "tjba", Name: "TJBA"}}, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjba").Return([]models.AgencyMonthlyInfo{
+		growthMI("tjba", 2020, 1, 10, 100000),
+		{AgencyID: "tjba", Year: 2020, Month: 2, ProcInfo: &coleta.ProcInfo{Status: 2, Stderr: "timeout"}},
+	}, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/atualizacao?grupo=justica-estadual", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetFreshness(ctx)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got freshness
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Len(t, got.Late, 1)
+	assert.Equal(t, "2020-01", *got.Late[0].LastMonth)
+	assert.True(t, got.Late[0].CrawlingError)
+}
+
+func (g getFreshness) testInvalidDelay(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/atualizacao?prazo=-1", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetFreshness(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestGetItemSeries(t *testing.T) {
+	tests := getItemSeries{}
+	t.Run("Test GetItemSeries as json", tests.testJSON)
+	t.Run("Test GetItemSeries as csv", tests.testCSV)
+	t.Run("Test GetItemSeries with invalid item", tests.testInvalidItem)
+	t.Run("Test GetItemSeries when period is too long", tests.testPeriodTooLong)
+}
+
+type getItemSeries struct{}
+
+func (g getItemSeries) request(t *testing.T, query string, mock bool) *httptest.ResponseRecorder {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	if mock {
+		monthlyInfos := map[string][]models.AgencyMonthlyInfo{
+			"tjpb": {
+				{
+					AgencyID: "tjpb",
+					Year:     2020,
+					Month:    1,
+					Summary: &models.Summary{
+						Count:       10,
+						ItemSummary: models.ItemSummary{HealthAllowance: 1000, BonusLicense: 500},
+					},
+				},
+				{
+					AgencyID: "tjpb",
+					Year:     2020,
+					Month:    3,
+					Summary: &models.Summary{
+						Count:       20,
+						ItemSummary: models.ItemSummary{HealthAllowance: 3000},
+					},
+				},
+			},
+		}
+		dbMock.EXPECT().GetAgency("tjpb").Return(&models.Agency{ID: "tjpb"}, nil).Times(1)
+		dbMock.EXPECT().GetMonthlyInfo([]models.Agency{{ID: "tjpb"}}, 2020).Return(monthlyInfos, nil).Times(1)
+	}
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/rubricas/:orgao?"+query, nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao")
+	ctx.SetParamValues("tjpb")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetItemSeries(ctx)
+	return recorder
+}
+
+func (g getItemSeries) testJSON(t *testing.T) {
+	recorder := g.request(t, "inicio=2020-01&fim=2020-03&rubricas=auxilio_saude,licenca_premio", true)
+
+	expectedJson := `
+		{
+			"orgao": "tjpb",
+			"inicio": "2020-01",
+			"fim": "2020-03",
+			"meses": ["2020-01", "2020-02", "2020-03"],
+			"num_membros": [10, null, 20],
+			"rubricas": [
+				{
+					"rubrica": "auxilio_saude",
+					"total": [1000, null, 3000],
+					"por_membro": [100, null, 150]
+				},
+				{
+					"rubrica": "licenca_premio",
+					"total": [500, null, 0],
+					"por_membro": [50, null, 0]
+				}
+			]
+		}
+	`
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, expectedJson, recorder.Body.String())
+}
+
+func (g getItemSeries) testCSV(t *testing.T) {
+	recorder := g.request(t, "inicio=2020-01&fim=2020-03&rubricas=auxilio_saude&formato=csv", true)
+
+	expectedCSV := "mes,num_membros,auxilio_saude,auxilio_saude_por_membro\n" +
+		"2020-01,10,1000.00,100.00\n" +
+		"2020-02,,,\n" +
+		"2020-03,20,3000.00,150.00\n"
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, expectedCSV, recorder.Body.String())
+}
+
+func (g getItemSeries) testInvalidItem(t *testing.T) {
+	recorder := g.request(t, "inicio=2020-01&fim=2020-03&rubricas=auxilio_moradia", false)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"rubrica inválida: 'auxilio_moradia'"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func (g getItemSeries) testPeriodTooLong(t *testing.T) {
+	recorder := g.request(t, "inicio=2000-01&fim=2020-03", false)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"o período informado é maior que o limite de 120 meses"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestGetItemDictionary(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/rubricas/dicionario", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler := NewHandler(client, "", "", "", "", nil, nil, nil)
+	handler.V2GetItemDictionary(ctx)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got classification.Dictionary
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, classification.Default().Version, got.Version)
+	assert.Equal(t, len(classification.Default().Rules), len(got.Rules))
+}
+
+func TestWebhooks(t *testing.T) {
+	tests := webhooks{}
+	t.Run("Test webhook subscription lifecycle", tests.testLifecycle)
+	t.Run("Test CreateWebhook with invalid params", tests.testInvalidParams)
+	t.Run("Test webhooks without dispatcher", tests.testUnavailable)
+}
+
+type webhooks struct{}
+
+func (w webhooks) request(t *testing.T, dispatcher *webhook.Dispatcher, method, path, body string, paramNames, paramValues []string, fn func(handler, echo.Context) error) *httptest.ResponseRecorder {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames(paramNames...)
+	ctx.SetParamValues(paramValues...)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	fn(*NewHandler(client, "", "", "", "", nil, nil, dispatcher), ctx)
+	return recorder
+}
+
+func (w webhooks) testLifecycle(t *testing.T) {
+	var received []string
+	stub := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var e webhook.Event
+		json.NewDecoder(r.Body).Decode(&e)
+		received = append(received, e.Type)
+		if e.Type == webhook.Verification {
+			rw.Write([]byte(e.Challenge))
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer stub.Close()
+	dispatcher := webhook.NewDispatcher(webhook.NewMemoryStore(), nil, nil)
+	dispatcher.AllowPrivateAddresses = true
+
+	recorder := w.request(t, dispatcher, http.MethodPost, "/v2/webhooks", fmt.Sprintf(`{"url":%q,"grupo":"justica-estadual"}`, stub.URL), nil, nil, handler.V2CreateWebhook)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var created webhook.Subscription
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.NotEmpty(t, created.Secret)
+	assert.Equal(t, "justica-estadual", created.Group)
+
+	recorder = w.request(t, dispatcher, http.MethodGet, "/v2/webhooks/"+created.ID, "", []string{"id"}, []string{created.ID}, handler.V2GetWebhook)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got webhook.Subscription
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, created.URL, got.URL)
+	assert.Empty(t, got.Secret)
+
+	recorder = w.request(t, dispatcher, http.MethodPost, "/v2/webhooks/"+created.ID+"/teste", "", []string{"id"}, []string{created.ID}, handler.V2PingWebhook)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var delivery webhook.Delivery
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &delivery))
+	assert.True(t, delivery.Delivered)
+	assert.Equal(t, http.StatusNoContent, delivery.StatusCode)
+	assert.Equal(t, []string{webhook.Verification, webhook.Ping}, received)
+
+	recorder = w.request(t, dispatcher, http.MethodGet, "/v2/webhooks/"+created.ID+"/entregas", "", []string{"id"}, []string{created.ID}, handler.V2GetWebhookDeliveries)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var deliveries []webhook.Delivery
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &deliveries))
+	assert.Len(t, deliveries, 1)
+
+	recorder = w.request(t, dispatcher, http.MethodDelete, "/v2/webhooks/"+created.ID, "", []string{"id"}, []string{created.ID}, handler.V2DeleteWebhook)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	recorder = w.request(t, dispatcher, http.MethodGet, "/v2/webhooks/"+created.ID, "", []string{"id"}, []string{created.ID}, handler.V2GetWebhook)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func (w webhooks) testInvalidParams(t *testing.T) {
+	dispatcher := webhook.NewDispatcher(webhook.NewMemoryStore(), nil, nil)
+	for body, status := range map[string]int{
+		`{"url":"ftp://example.com"}`: http.StatusBadRequest,
+		`{"url":"example.com/hook"}`:  http.StatusBadRequest,
+		`{"url":"https://example.com","orgao":"tjal","grupo":"justica-estadual"}`: http.StatusBadRequest,
+		`{"url":"https://example.com","grupo":"justica-inexistente"}`:             http.StatusNotFound,
+		`{"url":`:                                 http.StatusBadRequest,
+		`{"url":"http://127.0.0.1/hook"}`:         http.StatusBadRequest,
+		`{"url":"http://169.254.169.254/latest"}`: http.StatusBadRequest,
+	} {
+		recorder := w.request(t, dispatcher, http.MethodPost, "/v2/webhooks", body, nil, nil, handler.V2CreateWebhook)
+		assert.Equal(t, status, recorder.Code, body)
+	}
+	recorder := w.request(t, dispatcher, http.MethodGet, "/v2/webhooks/x/entregas?limite=0", "", []string{"id"}, []string{"x"}, handler.V2GetWebhookDeliveries)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func (w webhooks) testUnavailable(t *testing.T) {
+	recorder := w.request(t, nil, http.MethodPost, "/v2/webhooks", `{"url":"https://example.com"}`, nil, nil, handler.V2CreateWebhook)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestGetFeed(t *testing.T) {
+	tests := getFeed{}
+	t.Run("Test GetFeed", tests.testFeed)
+	t.Run("Test GetFeed without collection source", tests.testFeedUnavailable)
+	t.Run("Test GetAgencyFeed", tests.testAgencyFeed)
+	t.Run("Test GetAgencyFeed when agency does not exist", tests.testAgencyNotFound)
+	t.Run("Test formatNumber", tests.testFormatNumber)
+}
+
+type getFeed struct{}
+
+func feedMI(agency string, year, month, count int, total float64, crawled int64) models.AgencyMonthlyInfo {
+	mi := growthMI(agency, year, month, count, total)
+	mi.CrawlingTimestamp = timestamppb.New(time.Unix(crawled, 0))
+	mi.Score = &models.Score{Score: 0.75}
+	mi.Package = &models.Backup{URL: fmt.Sprintf("https://s3.example.com/%s/datapackage/%s-%d-%d.zip", agency, agency, year, month)}
+	return mi
+}
+
+func (g getFeed) request(t *testing.T, path string, collections CollectionSource, mockFn func(*database.MockInterface), fn func(handler, echo.Context) error, paramNames, paramValues []string) (*httptest.ResponseRecorder, atomFeed) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	mockFn(dbMock)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames(paramNames...)
+	ctx.SetParamValues(paramValues...)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	fn(*NewHandler(client, "https://dadosjusbr.org/download", "https://s3.example.com", "", "https://dadosjusbr.org/", nil, collections, nil), ctx)
+
+	var feed atomFeed
+	if recorder.Code == http.StatusOK {
+		assert.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &feed))
+	}
+	return recorder, feed
+}
+
+// fakeCollections retorna as coletas informadas, guardando o limite pedido.
+type fakeCollections struct {
+	collections []models.AgencyMonthlyInfo
+	limit       int
+}
+
+func (f *fakeCollections) RecentCollections(limit int) ([]models.AgencyMonthlyInfo, error) {
+	f.limit = limit
+	return f.collections, nil
+}
+
+func (g getFeed) testFeed(t *testing.T) {
+	agencies := []models.Agency{{ID: "tjal", Name: "Tribunal de Justiça de Alagoas"}, {ID: "mppb", Name: "Ministério Público da Paraíba"}}
+	// A recoleta de um mês antigo é a coleta mais recente.
+	collections := &fakeCollections{collections: []models.AgencyMonthlyInfo{
+		feedMI("tjal", 2018, 5, 8, 80000, 4000),
+		feedMI("mppb", 2023, 1, 5, 50000, 3000),
+		feedMI("tjal", 2023, 1, 10, 100000, 2000),
+	}}
+	recorder, feed := g.request(t, "/v2/feed.atom", collections, func(dbMock *database.MockInterface) {
+		dbMock.EXPECT().GetAllAgencies().Return(agencies, nil).Times(1)
+	}, handler.V2GetFeed, nil, nil)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, feedSize, collections.limit)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", recorder.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "tag:dadosjusbr.org,2020:coletas", feed.ID)
+	assert.Equal(t, "http://example.com/v2/feed.atom", feed.Links[0].Href)
+	assert.Equal(t, time.Unix(4000, 0).UTC().Format(time.RFC3339), feed.Updated)
+	assert.Len(t, feed.Entries, 3)
+	assert.Equal(t, "tag:dadosjusbr.org,2020:coleta/tjal/2018/05", feed.Entries[0].ID)
+	assert.Equal(t, "Ministério Público da Paraíba: dados de janeiro de 2023", feed.Entries[1].Title)
+	assert.Equal(t, "tag:dadosjusbr.org,2020:coleta/tjal/2023/01", feed.Entries[2].ID)
+}
+
+func (g getFeed) testFeedUnavailable(t *testing.T) {
+	recorder, _ := g.request(t, "/v2/feed.atom", nil, func(dbMock *database.MockInterface) {}, handler.V2GetFeed, nil, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func (g getFeed) testAgencyFeed(t *testing.T) {
+	agency := &models.Agency{ID: "tjal", Name: "Tribunal de Justiça de Alagoas"}
+	recorder, feed := g.request(t, "/v2/orgao/tjal/feed.atom", nil, func(dbMock *database.MockInterface) {
+		dbMock.EXPECT().GetAgency("tjal").Return(agency, nil).Times(1)
+		dbMock.EXPECT().GetAllAgencyCollection("tjal").Return([]models.AgencyMonthlyInfo{
+			feedMI("tjal", 2020, 1, 214, 1234567.891, 1000),
+			feedMI("tjal", 2020, 2, 210, 1000000, 500),
+		}, nil).Times(1)
+	}, handler.V2GetAgencyFeed, []string{"orgao"}, []string{"TJAL"})
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "tag:dadosjusbr.org,2020:coletas/tjal", feed.ID)
+	assert.Equal(t, "DadosJusBr: coletas recentes de Tribunal de Justiça de Alagoas", feed.Title)
+	assert.Len(t, feed.Entries, 2)
+
+	e := feed.Entries[0]
+	assert.Equal(t, "Tribunal de Justiça de Alagoas: dados de janeiro de 2020", e.Title)
+	assert.Equal(t, "1970-01-01T00:16:40Z", e.Updated)
+	assert.Equal(t, "Membros: 214. Remuneração total: R$ 1.234.567,89. Índice de transparência: 0,75.", e.Summary.Body)
+	assert.Equal(t, []atomLink{
+		{Href: "https://dadosjusbr.org/orgao/tjal/2020/1", Rel: "alternate", Type: "text/html", Title: "Página do órgão no DadosJusBr"},
+		{Href: "https://dadosjusbr.org/download/tjal/datapackage/tjal-2020-1.zip", Rel: "enclosure", Type: "application/zip", Title: "Pacote de dados"},
+	}, e.Links)
+	assert.Equal(t, "html", e.Content.Type)
+	assert.Contains(t, e.Content.Body, "<li>Membros: 214</li>")
+	assert.Contains(t, e.Content.Body, `<a href="https://dadosjusbr.org/download/tjal/datapackage/tjal-2020-1.zip">`)
+}
+
+func (g getFeed) testAgencyNotFound(t *testing.T) {
+	recorder, _ := g.request(t, "/v2/orgao/xpto/feed.atom", nil, func(dbMock *database.MockInterface) {
+		dbMock.EXPECT().GetAgency("xpto").Return(nil, fmt.Errorf("agency not found")).Times(1)
+	}, handler.V2GetAgencyFeed, []string{"orgao"}, []string{"xpto"})
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func (g getFeed) testFormatNumber(t *testing.T) {
+	assert.Equal(t, "0,00", formatNumber(0))
+	assert.Equal(t, "999,50", formatNumber(999.5))
+	assert.Equal(t, "1.000,00", formatNumber(1000))
+	assert.Equal(t, "1.234.567,89", formatNumber(1234567.891))
+	assert.Equal(t, "-12.345,00", formatNumber(-12345))
+}
@@ -0,0 +1,126 @@
+package papi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dadosjusbr/storage/models"
+)
+
+// rankingMetrics - métricas do resumo anual aceitas pelo parâmetro metrica. Os
+// valores por membro são médias mensais.
+var rankingMetrics = map[string]func(models.AnnualSummary) float64{
+	"remuneracao_base":               func(s models.AnnualSummary) float64 { return s.BaseRemuneration },
+	"outras_remuneracoes":            func(s models.AnnualSummary) float64 { return s.OtherRemunerations },
+	"descontos":                      func(s models.AnnualSummary) float64 { return s.Discounts },
+	"remuneracoes":                   func(s models.AnnualSummary) float64 { return s.Remunerations },
+	"remuneracao_base_por_membro":    func(s models.AnnualSummary) float64 { return s.BaseRemunerationPerCapita },
+	"outras_remuneracoes_por_membro": func(s models.AnnualSummary) float64 { return s.OtherRemunerationsPerCapita },
+	"descontos_por_membro":           func(s models.AnnualSummary) float64 { return s.DiscountsPerCapita },
+	"remuneracoes_por_membro":        func(s models.AnnualSummary) float64 { return s.RemunerationsPerCapita },
+	"num_membros":                    func(s models.AnnualSummary) float64 { return float64(s.AverageCount) },
+}
+
+func rankingMetricNames() string {
+	var names []string
+	for name := range rankingMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// newRanking ordena os órgãos pela métrica no ano. Órgãos com menos de 12
+// meses de dados são sinalizados e, se includeIncomplete for falso, ficam sem
+// posição, no fim da lista, já que seus totais não são comparáveis aos dos
+// demais. A variação de posição usa as mesmas regras no ano anterior.
+func newRanking(agencies []models.Agency, summaries map[string][]models.AnnualSummary, year int, metric string, includeIncomplete bool) ranking {
+	r := ranking{
+		Year:              year,
+		Metric:            metric,
+		IncludeIncomplete: includeIncomplete,
+		Agencies:          []rankingEntry{},
+		NoData:            []string{},
+	}
+	previous := rankPositions(agencies, summaries, year-1, metric, includeIncomplete)
+	entries := rankEntries(agencies, summaries, year, metric, includeIncomplete)
+	for _, a := range agencies {
+		if _, ok := annualSummaryOf(summaries[a.ID], year); !ok {
+			r.NoData = append(r.NoData, a.ID)
+		}
+	}
+	for i := range entries {
+		e := &entries[i]
+		if prev, ok := previous[e.AgencyID]; ok && e.Position != nil {
+			change := prev - *e.Position
+			e.PreviousPosition = &prev
+			e.PositionChange = &change
+		}
+	}
+	sort.Strings(r.NoData)
+	r.Agencies = entries
+	return r
+}
+
+// rankEntries retorna os órgãos com dados no ano, os classificados primeiro.
+func rankEntries(agencies []models.Agency, summaries map[string][]models.AnnualSummary, year int, metric string, includeIncomplete bool) []rankingEntry {
+	value := rankingMetrics[metric]
+	var ranked, unranked []rankingEntry
+	for _, a := range agencies {
+		s, ok := annualSummaryOf(summaries[a.ID], year)
+		if !ok {
+			continue
+		}
+		e := rankingEntry{
+			AgencyID:       a.ID,
+			Name:           a.Name,
+			Value:          value(s),
+			MonthsWithData: s.NumMonthsWithData,
+			Incomplete:     s.NumMonthsWithData < 12,
+		}
+		if e.Incomplete && !includeIncomplete {
+			unranked = append(unranked, e)
+		} else {
+			ranked = append(ranked, e)
+		}
+	}
+	byValue := func(entries []rankingEntry) {
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].Value != entries[j].Value {
+				return entries[i].Value > entries[j].Value
+			}
+			return entries[i].AgencyID < entries[j].AgencyID
+		})
+	}
+	byValue(ranked)
+	byValue(unranked)
+	for i := range ranked {
+		position := i + 1
+		percentile := 100.0
+		if len(ranked) > 1 {
+			percentile = 100 * float64(len(ranked)-position) / float64(len(ranked)-1)
+		}
+		ranked[i].Position = &position
+		ranked[i].Percentile = &percentile
+	}
+	return append(ranked, unranked...)
+}
+
+func rankPositions(agencies []models.Agency, summaries map[string][]models.AnnualSummary, year int, metric string, includeIncomplete bool) map[string]int {
+	positions := map[string]int{}
+	for _, e := range rankEntries(agencies, summaries, year, metric, includeIncomplete) {
+		if e.Position != nil {
+			positions[e.AgencyID] = *e.Position
+		}
+	}
+	return positions
+}
+
+func annualSummaryOf(summaries []models.AnnualSummary, year int) (models.AnnualSummary, bool) {
+	for _, s := range summaries {
+		if s.Year == year {
+			return s, true
+		}
+	}
+	return models.AnnualSummary{}, false
+}
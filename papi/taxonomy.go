@@ -0,0 +1,83 @@
+package papi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dadosjusbr/api/taxonomy"
+	"github.com/dadosjusbr/storage/models"
+)
+
+// newGroupInfos lista os grupos da taxonomia com o número de órgãos de cada
+// um, por entidade.
+func newGroupInfos(agencies []models.Agency) []groupInfo {
+	groups := []groupInfo{}
+	for _, g := range taxonomy.Groups {
+		var members []models.Agency
+		for _, a := range agencies {
+			if strings.EqualFold(a.Type, g.Jurisdiction) {
+				members = append(members, a)
+			}
+		}
+		groups = append(groups, groupInfo{
+			Slug:         g.Slug,
+			Label:        g.Label,
+			Jurisdiction: g.Jurisdiction,
+			AgencyCount:  len(members),
+			Entities:     newEntityCounts(members),
+		})
+	}
+	return groups
+}
+
+// newUFInfos lista as UFs da taxonomia com o número de órgãos de cada uma, por
+// entidade.
+func newUFInfos(agencies []models.Agency, flagsURL string) []ufInfo {
+	ufs := []ufInfo{}
+	for _, u := range taxonomy.UFs {
+		var members []models.Agency
+		for _, a := range agencies {
+			if strings.EqualFold(a.UF, u.Code) {
+				members = append(members, a)
+			}
+		}
+		ufs = append(ufs, ufInfo{
+			Code:        u.Code,
+			Slug:        u.Slug(),
+			Name:        u.Name,
+			Region:      u.Region,
+			FlagURL:     u.FlagURL(flagsURL),
+			AgencyCount: len(members),
+			Entities:    newEntityCounts(members),
+		})
+	}
+	return ufs
+}
+
+// newEntityCounts conta os órgãos por entidade, na ordem da taxonomia.
+// Entidades fora da taxonomia vêm depois, em ordem alfabética, com o próprio
+// código como nome.
+func newEntityCounts(agencies []models.Agency) []entityCount {
+	counts := map[string]int{}
+	for _, a := range agencies {
+		counts[a.Entity]++
+	}
+	entities := []entityCount{}
+	for _, e := range taxonomy.Entities {
+		for code, n := range counts {
+			if strings.EqualFold(code, e.Code) {
+				entities = append(entities, entityCount{Code: e.Code, Label: e.Label, AgencyCount: n})
+				delete(counts, code)
+			}
+		}
+	}
+	var unknown []string
+	for code := range counts {
+		unknown = append(unknown, code)
+	}
+	sort.Strings(unknown)
+	for _, code := range unknown {
+		entities = append(entities, entityCount{Code: code, Label: code, AgencyCount: counts[code]})
+	}
+	return entities
+}
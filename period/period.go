@@ -0,0 +1,77 @@
+// Package period trata os intervalos de meses informados pelos parâmetros
+// inicio e fim (AAAA-MM), usados pelos endpoints de séries e relatórios da papi
+// e da uiapi.
+package period
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period - intervalo de meses, do mês de Start ao mês de End, inclusive.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// YearMonth - um mês de um ano.
+type YearMonth struct {
+	Year  int
+	Month int
+}
+
+// New monta o período a partir dos parâmetros inicio e fim. Se maxMonths for
+// maior que zero, períodos com mais meses que o limite são recusados.
+func New(startQp, endQp string, maxMonths int) (*Period, error) {
+	start, err := time.Parse("2006-01", startQp)
+	if err != nil {
+		return nil, fmt.Errorf("parâmetro inicio '%s' é inválido! Use o formato AAAA-MM", startQp)
+	}
+	end, err := time.Parse("2006-01", endQp)
+	if err != nil {
+		return nil, fmt.Errorf("parâmetro fim '%s' é inválido! Use o formato AAAA-MM", endQp)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("o parâmetro fim '%s' é anterior ao parâmetro inicio '%s'", endQp, startQp)
+	}
+	p := &Period{Start: start, End: end}
+	if maxMonths > 0 && p.Len() > maxMonths {
+		return nil, fmt.Errorf("o período informado é maior que o limite de %d meses", maxMonths)
+	}
+	return p, nil
+}
+
+// Len retorna a quantidade de meses do período.
+func (p Period) Len() int {
+	return (p.End.Year()-p.Start.Year())*12 + int(p.End.Month()) - int(p.Start.Month()) + 1
+}
+
+// Contains diz se o mês está no período.
+func (p Period) Contains(year, month int) bool {
+	t := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return !t.Before(p.Start) && !t.After(p.End)
+}
+
+// Months retorna os meses do período, em ordem.
+func (p Period) Months() []YearMonth {
+	months := make([]YearMonth, 0, p.Len())
+	for t := p.Start; !t.After(p.End); t = t.AddDate(0, 1, 0) {
+		months = append(months, YearMonth{Year: t.Year(), Month: int(t.Month())})
+	}
+	return months
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%s a %s", p.Start.Format("2006-01"), p.End.Format("2006-01"))
+}
+
+// String retorna o mês no formato AAAA-MM.
+func (m YearMonth) String() string {
+	return fmt.Sprintf("%d-%02d", m.Year, m.Month)
+}
+
+// AddMonths retorna o mês n meses depois (ou antes, se n for negativo).
+func (m YearMonth) AddMonths(n int) YearMonth {
+	t := time.Date(m.Year, time.Month(m.Month)+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	return YearMonth{Year: t.Year(), Month: int(t.Month())}
+}
@@ -0,0 +1,45 @@
+package period
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	p, err := New("2020-11", "2021-02", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC), p.Start)
+	assert.Equal(t, 4, p.Len())
+	assert.Equal(t, []YearMonth{{2020, 11}, {2020, 12}, {2021, 1}, {2021, 2}}, p.Months())
+	assert.Equal(t, "2020-11 a 2021-02", p.String())
+
+	p, err = New("2020-01", "2020-12", 12)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, p.Len())
+
+	_, err = New("2020-01", "2021-01", 12)
+	assert.EqualError(t, err, "o período informado é maior que o limite de 12 meses")
+	_, err = New("2020", "2020-12", 0)
+	assert.EqualError(t, err, "parâmetro inicio '2020' é inválido! Use o formato AAAA-MM")
+	_, err = New("2020-01", "2020-13", 0)
+	assert.EqualError(t, err, "parâmetro fim '2020-13' é inválido! Use o formato AAAA-MM")
+	_, err = New("2020-05", "2020-01", 0)
+	assert.EqualError(t, err, "o parâmetro fim '2020-01' é anterior ao parâmetro inicio '2020-05'")
+}
+
+func TestContains(t *testing.T) {
+	p, _ := New("2020-11", "2021-02", 0)
+	assert.True(t, p.Contains(2020, 11))
+	assert.True(t, p.Contains(2021, 2))
+	assert.False(t, p.Contains(2020, 10))
+	assert.False(t, p.Contains(2021, 3))
+}
+
+func TestYearMonth(t *testing.T) {
+	m := YearMonth{Year: 2021, Month: 1}
+	assert.Equal(t, "2021-01", m.String())
+	assert.Equal(t, YearMonth{Year: 2020, Month: 12}, m.AddMonths(-1))
+	assert.Equal(t, YearMonth{Year: 2022, Month: 2}, m.AddMonths(13))
+}
@@ -0,0 +1,44 @@
+package tables
+
+import (
+	"net/url"
+
+	"github.com/dadosjusbr/storage/models"
+)
+
+// Correction descreve, nas respostas da API, a correção monetária aplicada aos
+// valores.
+type Correction struct {
+	Index        string `json:"indice"`
+	Base         string `json:"base"` // mês base (AAAA-MM) para o qual os valores foram corrigidos
+	TableVersion string `json:"versao_tabela"`
+}
+
+// DeflatorFromQuery lê os parâmetros corrigir e base da requisição. Retorna
+// nil se a correção monetária não foi pedida.
+func DeflatorFromQuery(q url.Values) (*Deflator, error) {
+	return NewDeflator(q.Get("corrigir"), q.Get("base"))
+}
+
+// Correction retorna a descrição da correção aplicada pelo deflator, ou nil se
+// a correção não foi pedida.
+func (d *Deflator) Correction() *Correction {
+	if d == nil {
+		return nil
+	}
+	return &Correction{Index: d.Index, Base: d.Base(), TableVersion: d.Version}
+}
+
+// ScaleItemSummary multiplica os valores do resumo de rubricas por f.
+func ScaleItemSummary(s models.ItemSummary, f float64) models.ItemSummary {
+	return models.ItemSummary{
+		FoodAllowance:        s.FoodAllowance * f,
+		BonusLicense:         s.BonusLicense * f,
+		VacationCompensation: s.VacationCompensation * f,
+		Vacation:             s.Vacation * f,
+		ChristmasBonus:       s.ChristmasBonus * f,
+		CompensatoryLicense:  s.CompensatoryLicense * f,
+		HealthAllowance:      s.HealthAllowance * f,
+		Others:               s.Others * f,
+	}
+}
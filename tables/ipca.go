@@ -0,0 +1,115 @@
+package tables
+
+import (
+	"fmt"
+	"time"
+)
+
+// CorrectionIPCA é o valor do parâmetro corrigir que ativa a correção pelo IPCA.
+const CorrectionIPCA = "ipca"
+
+// IPCA retorna a série de variações mensais do IPCA, em porcentagem.
+func IPCA() Series {
+	return mustLoad("ipca")
+}
+
+// Deflator corrige valores monetários pela inflação, trazendo-os para os
+// preços de um mês base. Um Deflator nil não altera os valores, o que permite
+// usá-lo sem verificar se a correção foi pedida.
+type Deflator struct {
+	Index     string
+	Version   string
+	BaseYear  int
+	BaseMonth int
+	// Número-índice acumulado ao final de cada mês (ano*12 + mês).
+	index map[int]float64
+	first int
+	last  int
+}
+
+// NewDeflator monta o deflator a partir dos parâmetros corrigir e base
+// (AAAA-MM). Retorna nil se nenhuma correção foi pedida. Se a base não for
+// informada, é usado o último mês da tabela.
+func NewDeflator(correction, base string) (*Deflator, error) {
+	switch correction {
+	case "":
+		return nil, nil
+	case CorrectionIPCA:
+	default:
+		return nil, fmt.Errorf("parâmetro corrigir '%s' é inválido! Valores aceitos: %s", correction, CorrectionIPCA)
+	}
+	s := IPCA()
+	d := &Deflator{Index: CorrectionIPCA, Version: s.Version, index: make(map[int]float64)}
+	acc := 1.0
+	for _, e := range s.Entries {
+		acc *= 1 + e.Value/100
+		d.index[e.Year*12+e.Month] = acc
+	}
+	d.first = s.Entries[0].Year*12 + s.Entries[0].Month
+	d.last = s.Last().Year*12 + s.Last().Month
+
+	if base == "" {
+		d.BaseYear, d.BaseMonth = s.Last().Year, s.Last().Month
+		return d, nil
+	}
+	t, err := time.Parse("2006-01", base)
+	if err != nil {
+		return nil, fmt.Errorf("parâmetro base '%s' é inválido! Use o formato AAAA-MM", base)
+	}
+	if _, ok := d.index[t.Year()*12+int(t.Month())]; !ok {
+		first, last := s.Entries[0], s.Last()
+		return nil, fmt.Errorf("parâmetro base '%s' fora da tabela do IPCA (%d-%02d a %d-%02d)", base, first.Year, first.Month, last.Year, last.Month)
+	}
+	d.BaseYear, d.BaseMonth = t.Year(), int(t.Month())
+	return d, nil
+}
+
+// Base retorna o mês base no formato AAAA-MM.
+func (d *Deflator) Base() string {
+	return fmt.Sprintf("%d-%02d", d.BaseYear, d.BaseMonth)
+}
+
+// Factor retorna o fator que leva um valor do mês informado para o mês base.
+// Meses posteriores ao fim da tabela usam o último índice publicado; meses
+// anteriores ao início da tabela usam o primeiro.
+func (d *Deflator) Factor(year, month int) float64 {
+	if d == nil {
+		return 1
+	}
+	key := year*12 + month
+	if key < d.first {
+		key = d.first
+	}
+	if key > d.last {
+		key = d.last
+	}
+	return d.index[d.BaseYear*12+d.BaseMonth] / d.index[key]
+}
+
+// YearFactor retorna a média dos fatores dos meses informados do ano (os meses
+// com dados), ou de todos os meses se nenhum for informado. É usado para
+// corrigir valores anuais, que não têm o detalhamento mensal.
+func (d *Deflator) YearFactor(year int, months ...int) float64 {
+	if d == nil {
+		return 1
+	}
+	return meanFactor(d.Factor, year, months)
+}
+
+// meanFactor retorna a média de factor nos meses do ano. Sem meses, usa todos
+// os meses do ano.
+func meanFactor(factor func(year, month int) float64, year int, months []int) float64 {
+	if len(months) == 0 {
+		months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	}
+	var sum float64
+	for _, m := range months {
+		sum += factor(year, m)
+	}
+	return sum / float64(len(months))
+}
+
+// Apply corrige um valor do mês informado para o mês base.
+func (d *Deflator) Apply(value float64, year, month int) float64 {
+	return value * d.Factor(year, month)
+}
@@ -0,0 +1,141 @@
+// Package tables contém séries mensais de referência distribuídas junto com a
+// API, como o teto remuneratório constitucional, o salário mínimo e a variação
+// mensal do IPCA.
+//
+// Cada tabela é um CSV (separado por ';') embutido no binário. As linhas que
+// começam com '#' são comentários, e a linha "# versao: <versão>" identifica a
+// versão da tabela. Para atualizar uma tabela, basta editar o CSV e a versão.
+package tables
+
+import (
+	"bytes"
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed *.csv
+var files embed.FS
+
+// Entry é um valor que passa a valer a partir de um mês.
+type Entry struct {
+	Year   int
+	Month  int
+	Value  float64
+	Source string
+}
+
+// Series é uma série mensal de valores. Um valor vale a partir do mês da sua
+// entrada até o mês anterior à entrada seguinte.
+type Series struct {
+	Name    string
+	Version string
+	Entries []Entry
+}
+
+// ValueAt retorna o valor em vigor no mês informado. Retorna false se o mês
+// for anterior ao início da série.
+func (s Series) ValueAt(year, month int) (float64, bool) {
+	key := year*12 + month
+	i := sort.Search(len(s.Entries), func(i int) bool {
+		return s.Entries[i].Year*12+s.Entries[i].Month > key
+	})
+	if i == 0 {
+		return 0, false
+	}
+	return s.Entries[i-1].Value, true
+}
+
+// Last retorna a última entrada da série.
+func (s Series) Last() Entry {
+	return s.Entries[len(s.Entries)-1]
+}
+
+// Ceiling retorna a tabela do teto remuneratório constitucional, o subsídio
+// mensal dos ministros do STF.
+func Ceiling() Series {
+	return mustLoad("teto")
+}
+
+func mustLoad(name string) Series {
+	s, err := load(name)
+	if err != nil {
+		// As tabelas são embutidas no binário, então um erro aqui é um erro de programação.
+		panic(err)
+	}
+	return s
+}
+
+func load(name string) (Series, error) {
+	b, err := files.ReadFile(name + ".csv")
+	if err != nil {
+		return Series{}, fmt.Errorf("error opening table %s: %w", name, err)
+	}
+	s, err := parse(name, bytes.NewReader(b))
+	if err != nil {
+		return Series{}, err
+	}
+	s.Version = version(b)
+	return s, nil
+}
+
+func parse(name string, r io.Reader) (Series, error) {
+	s := Series{Name: name}
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+
+	var header []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Series{}, fmt.Errorf("error reading table %s: %w", name, err)
+		}
+		if header == nil {
+			header = record
+			continue
+		}
+		if len(record) < 2 {
+			return Series{}, fmt.Errorf("invalid line in table %s: %v", name, record)
+		}
+		t, err := time.Parse("2006-01", record[0])
+		if err != nil {
+			return Series{}, fmt.Errorf("invalid month in table %s: %w", name, err)
+		}
+		v, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return Series{}, fmt.Errorf("invalid value in table %s: %w", name, err)
+		}
+		e := Entry{Year: t.Year(), Month: int(t.Month()), Value: v}
+		if len(record) > 2 {
+			e.Source = record[2]
+		}
+		s.Entries = append(s.Entries, e)
+	}
+	if len(s.Entries) == 0 {
+		return Series{}, fmt.Errorf("table %s is empty", name)
+	}
+	sort.SliceStable(s.Entries, func(i, j int) bool {
+		return s.Entries[i].Year*12+s.Entries[i].Month < s.Entries[j].Year*12+s.Entries[j].Month
+	})
+	return s, nil
+}
+
+// version lê a versão da tabela na linha de comentário "# versao: ...".
+func version(b []byte) string {
+	for _, line := range strings.Split(string(b), "\n") {
+		if v := strings.TrimPrefix(line, "# versao:"); v != line {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package tables
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeries(t *testing.T) {
+	tests := seriesTests{}
+	t.Run("Test value in force at a month", tests.testValueAt)
+	t.Run("Test parsing an invalid table", tests.testParseInvalidTable)
+	t.Run("Test bundled tables", tests.testBundledTables)
+	t.Run("Test IPCA deflator", tests.testDeflator)
+	t.Run("Test invalid deflator parameters", tests.testInvalidDeflator)
+	t.Run("Test minimum wage unit", tests.testMinimumWageUnit)
+}
+
+type seriesTests struct{}
+
+func (s seriesTests) testValueAt(t *testing.T) {
+	series, err := parse("teste", strings.NewReader("# versao: 1\ninicio;valor\n2019-01;10\n2015-01;5\n"))
+	assert.NoError(t, err)
+
+	_, ok := series.ValueAt(2014, 12)
+	assert.False(t, ok)
+	for _, c := range []struct {
+		year, month int
+		expected    float64
+	}{
+		{2015, 1, 5},
+		{2018, 12, 5},
+		{2019, 1, 10},
+		{2030, 6, 10},
+	} {
+		v, ok := series.ValueAt(c.year, c.month)
+		assert.True(t, ok)
+		assert.Equal(t, c.expected, v)
+	}
+	assert.Equal(t, Entry{Year: 2019, Month: 1, Value: 10}, series.Last())
+}
+
+func (s seriesTests) testParseInvalidTable(t *testing.T) {
+	_, err := parse("teste", strings.NewReader("inicio;valor\n2019-13;10\n"))
+	assert.Error(t, err)
+	_, err = parse("teste", strings.NewReader("inicio;valor\n2019-01;abc\n"))
+	assert.Error(t, err)
+	_, err = parse("teste", strings.NewReader("inicio;valor\n"))
+	assert.Error(t, err)
+}
+
+func (s seriesTests) testBundledTables(t *testing.T) {
+	ceiling := Ceiling()
+	assert.NotEmpty(t, ceiling.Version)
+	v, ok := ceiling.ValueAt(2020, 5)
+	assert.True(t, ok)
+	assert.Equal(t, 39293.32, v)
+}
+
+func (s seriesTests) testDeflator(t *testing.T) {
+	var none *Deflator
+	assert.Equal(t, 1.0, none.Factor(2020, 1))
+	assert.Equal(t, 100.0, none.Apply(100, 2020, 1))
+
+	d, err := NewDeflator("", "")
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+
+	d, err = NewDeflator(CorrectionIPCA, "2020-02")
+	assert.NoError(t, err)
+	assert.Equal(t, "2020-02", d.Base())
+	assert.Equal(t, IPCA().Version, d.Version)
+	assert.InDelta(t, 1.0, d.Factor(2020, 2), 1e-9)
+	// IPCA de fevereiro de 2020: 0,25%.
+	assert.InDelta(t, 1.0025, d.Factor(2020, 1), 1e-9)
+	assert.InDelta(t, 1/1.0007, d.Factor(2020, 3), 1e-9)
+	assert.InDelta(t, 1002.5, d.Apply(1000, 2020, 1), 1e-6)
+	// Meses fora da tabela usam o índice mais próximo.
+	last := IPCA().Last()
+	assert.Equal(t, d.Factor(last.Year, last.Month), d.Factor(last.Year+5, 1))
+	first := IPCA().Entries[0]
+	assert.Equal(t, d.Factor(first.Year, first.Month), d.Factor(first.Year-5, 1))
+
+	d, err = NewDeflator(CorrectionIPCA, "")
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%d-%02d", last.Year, last.Month), d.Base())
+
+	assert.Nil(t, none.Correction())
+	d, err = DeflatorFromQuery(url.Values{"corrigir": {"ipca"}, "base": {"2020-02"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &Correction{Index: CorrectionIPCA, Base: "2020-02", TableVersion: IPCA().Version}, d.Correction())
+	items := ScaleItemSummary(models.ItemSummary{FoodAllowance: 100, Others: 50}, 2)
+	assert.Equal(t, models.ItemSummary{FoodAllowance: 200, Others: 100}, items)
+}
+
+func (s seriesTests) testInvalidDeflator(t *testing.T) {
+	_, err := NewDeflator("igpm", "")
+	assert.EqualError(t, err, "parâmetro corrigir 'igpm' é inválido! Valores aceitos: ipca")
+	_, err = NewDeflator(CorrectionIPCA, "2020/02")
+	assert.EqualError(t, err, "parâmetro base '2020/02' é inválido! Use o formato AAAA-MM")
+	_, err = NewDeflator(CorrectionIPCA, "1990-01")
+	assert.Error(t, err)
+}
+
+func (s seriesTests) testMinimumWageUnit(t *testing.T) {
+	var none *Unit
+	assert.Equal(t, 1.0, none.Factor(2020, 1))
+
+	u, err := NewUnit("reais")
+	assert.NoError(t, err)
+	assert.Nil(t, u)
+
+	u, err = NewUnit(UnitMinimumWage)
+	assert.NoError(t, err)
+	assert.Equal(t, MinimumWage().Version, u.Version)
+	assert.InDelta(t, 1.0/1039, u.Factor(2020, 1), 1e-12)
+	assert.InDelta(t, 1.0/1045, u.Factor(2020, 2), 1e-12)
+	assert.InDelta(t, (1.0/1039+11.0/1045)/12, u.YearFactor(2020), 1e-12)
+	// Só os meses com dados entram na média.
+	assert.InDelta(t, 1.0/1045, u.YearFactor(2020, 2, 3), 1e-12)
+	assert.InDelta(t, (1.0/1039+1.0/1045)/2, u.YearFactor(2020, 1, 2), 1e-12)
+	assert.InDelta(t, 1.0/788, u.Factor(2010, 1), 1e-12)
+
+	_, err = NewUnit("dolar")
+	assert.EqualError(t, err, "parâmetro unidade 'dolar' é inválido! Valores aceitos: reais, salario_minimo")
+}
@@ -0,0 +1,57 @@
+package tables
+
+import "fmt"
+
+// UnitMinimumWage é o valor do parâmetro unidade que expressa os valores em
+// múltiplos do salário mínimo.
+const UnitMinimumWage = "salario_minimo"
+
+// MinimumWage retorna a tabela do salário mínimo nacional.
+func MinimumWage() Series {
+	return mustLoad("salario_minimo")
+}
+
+// Unit converte valores monetários em múltiplos de uma série de referência
+// em vigor em cada mês. Assim como o Deflator, uma Unit nil não altera os
+// valores.
+type Unit struct {
+	Name    string
+	Version string
+	series  Series
+}
+
+// NewUnit monta a unidade a partir do parâmetro unidade. Retorna nil se a
+// unidade não for informada ou for reais.
+func NewUnit(unit string) (*Unit, error) {
+	switch unit {
+	case "", "reais":
+		return nil, nil
+	case UnitMinimumWage:
+		s := MinimumWage()
+		return &Unit{Name: unit, Version: s.Version, series: s}, nil
+	default:
+		return nil, fmt.Errorf("parâmetro unidade '%s' é inválido! Valores aceitos: reais, %s", unit, UnitMinimumWage)
+	}
+}
+
+// Factor retorna o fator que converte um valor do mês informado para a
+// unidade. Meses anteriores ao início da tabela usam o primeiro valor.
+func (u *Unit) Factor(year, month int) float64 {
+	if u == nil {
+		return 1
+	}
+	v, ok := u.series.ValueAt(year, month)
+	if !ok {
+		v = u.series.Entries[0].Value
+	}
+	return 1 / v
+}
+
+// YearFactor retorna a média dos fatores dos meses informados do ano (os meses
+// com dados), ou de todos os meses se nenhum for informado.
+func (u *Unit) YearFactor(year int, months ...int) float64 {
+	if u == nil {
+		return 1
+	}
+	return meanFactor(u.Factor, year, months)
+}
@@ -0,0 +1,142 @@
+// Package taxonomy reúne as classificações usadas para agrupar os órgãos: os
+// grupos (jurisdições), as unidades federativas e as entidades. As duas APIs
+// resolvem os nomes usados nas URLs por aqui.
+package taxonomy
+
+import (
+	"strings"
+)
+
+// Group é um grupo de órgãos. Slug é o nome usado nas URLs e Jurisdiction é o
+// valor da coluna jurisdicao da tabela de órgãos.
+type Group struct {
+	Slug         string
+	Jurisdiction string
+	Label        string
+}
+
+// Groups - grupos de órgãos, na ordem em que são exibidos.
+var Groups = []Group{
+	{Slug: "justica-estadual", Jurisdiction: "Estadual", Label: "Justiça Estadual"},
+	{Slug: "ministerios-publicos", Jurisdiction: "Ministério", Label: "Ministérios Públicos"},
+	{Slug: "justica-do-trabalho", Jurisdiction: "Trabalho", Label: "Justiça do Trabalho"},
+	{Slug: "justica-federal", Jurisdiction: "Federal", Label: "Justiça Federal"},
+	{Slug: "justica-eleitoral", Jurisdiction: "Eleitoral", Label: "Justiça Eleitoral"},
+	{Slug: "justica-militar", Jurisdiction: "Militar", Label: "Justiça Militar"},
+	{Slug: "justica-superior", Jurisdiction: "Superior", Label: "Tribunais Superiores"},
+	{Slug: "conselhos-de-justica", Jurisdiction: "Conselho", Label: "Conselhos de Justiça"},
+}
+
+// GroupBySlug retorna o grupo com o slug informado, sem diferenciar
+// maiúsculas de minúsculas.
+func GroupBySlug(slug string) (Group, bool) {
+	for _, g := range Groups {
+		if strings.EqualFold(g.Slug, slug) {
+			return g, true
+		}
+	}
+	return Group{}, false
+}
+
+// GroupByJurisdiction retorna o grupo da jurisdição informada, sem diferenciar
+// maiúsculas de minúsculas. Até a consolidação ser finalizada, o site também
+// consulta os grupos pela jurisdição (/Eleitoral, /Trabalho etc).
+func GroupByJurisdiction(jurisdiction string) (Group, bool) {
+	for _, g := range Groups {
+		if strings.EqualFold(g.Jurisdiction, jurisdiction) {
+			return g, true
+		}
+	}
+	return Group{}, false
+}
+
+// UF é uma unidade federativa. Code é a sigla usada na coluna uf da tabela de
+// órgãos.
+type UF struct {
+	Code   string
+	Name   string
+	Region string
+}
+
+// Slug retorna o nome da UF usado nas URLs: a sigla em minúsculas.
+func (u UF) Slug() string {
+	return strings.ToLower(u.Code)
+}
+
+// FlagURL retorna o endereço da bandeira da UF, a partir do endereço base das
+// bandeiras (<base>/<sigla>.svg). Retorna vazio se base for vazio.
+func (u UF) FlagURL(base string) string {
+	if base == "" {
+		return ""
+	}
+	return strings.TrimSuffix(base, "/") + "/" + u.Slug() + ".svg"
+}
+
+// UFs - unidades federativas, em ordem alfabética da sigla.
+var UFs = []UF{
+	{Code: "AC", Name: "Acre", Region: "Norte"},
+	{Code: "AL", Name: "Alagoas", Region: "Nordeste"},
+	{Code: "AM", Name: "Amazonas", Region: "Norte"},
+	{Code: "AP", Name: "Amapá", Region: "Norte"},
+	{Code: "BA", Name: "Bahia", Region: "Nordeste"},
+	{Code: "CE", Name: "Ceará", Region: "Nordeste"},
+	{Code: "DF", Name: "Distrito Federal", Region: "Centro-Oeste"},
+	{Code: "ES", Name: "Espírito Santo", Region: "Sudeste"},
+	{Code: "GO", Name: "Goiás", Region: "Centro-Oeste"},
+	{Code: "MA", Name: "Maranhão", Region: "Nordeste"},
+	{Code: "MG", Name: "Minas Gerais", Region: "Sudeste"},
+	{Code: "MS", Name: "Mato Grosso do Sul", Region: "Centro-Oeste"},
+	{Code: "MT", Name: "Mato Grosso", Region: "Centro-Oeste"},
+	{Code: "PA", Name: "Pará", Region: "Norte"},
+	{Code: "PB", Name: "Paraíba", Region: "Nordeste"},
+	{Code: "PE", Name: "Pernambuco", Region: "Nordeste"},
+	{Code: "PI", Name: "Piauí", Region: "Nordeste"},
+	{Code: "PR", Name: "Paraná", Region: "Sul"},
+	{Code: "RJ", Name: "Rio de Janeiro", Region: "Sudeste"},
+	{Code: "RN", Name: "Rio Grande do Norte", Region: "Nordeste"},
+	{Code: "RO", Name: "Rondônia", Region: "Norte"},
+	{Code: "RR", Name: "Roraima", Region: "Norte"},
+	{Code: "RS", Name: "Rio Grande do Sul", Region: "Sul"},
+	{Code: "SC", Name: "Santa Catarina", Region: "Sul"},
+	{Code: "SE", Name: "Sergipe", Region: "Nordeste"},
+	{Code: "SP", Name: "São Paulo", Region: "Sudeste"},
+	{Code: "TO", Name: "Tocantins", Region: "Norte"},
+}
+
+// UFByCode retorna a UF com a sigla informada, sem diferenciar maiúsculas de
+// minúsculas.
+func UFByCode(code string) (UF, bool) {
+	for _, u := range UFs {
+		if strings.EqualFold(u.Code, code) {
+			return u, true
+		}
+	}
+	return UF{}, false
+}
+
+// Entity é o tipo de instituição do órgão. Code é o valor da coluna entidade
+// da tabela de órgãos.
+type Entity struct {
+	Code  string
+	Label string
+}
+
+// Entities - entidades dos órgãos.
+var Entities = []Entity{
+	{Code: "Tribunal", Label: "Tribunal"},
+	{Code: "Ministério", Label: "Ministério Público"},
+	{Code: "Conselho", Label: "Conselho"},
+	{Code: "Defensoria", Label: "Defensoria Pública"},
+	{Code: "Procuradoria", Label: "Procuradoria"},
+}
+
+// EntityByCode retorna a entidade com o código informado, sem diferenciar
+// maiúsculas de minúsculas.
+func EntityByCode(code string) (Entity, bool) {
+	for _, e := range Entities {
+		if strings.EqualFold(e.Code, code) {
+			return e, true
+		}
+	}
+	return Entity{}, false
+}
@@ -0,0 +1,39 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroups(t *testing.T) {
+	g, ok := GroupBySlug("Justica-Do-Trabalho")
+	assert.True(t, ok)
+	assert.Equal(t, "Trabalho", g.Jurisdiction)
+
+	g, ok = GroupByJurisdiction("eleitoral")
+	assert.True(t, ok)
+	assert.Equal(t, "justica-eleitoral", g.Slug)
+
+	_, ok = GroupBySlug("Trabalho")
+	assert.False(t, ok)
+}
+
+func TestUFs(t *testing.T) {
+	assert.Len(t, UFs, 27)
+	u, ok := UFByCode("pb")
+	assert.True(t, ok)
+	assert.Equal(t, "Paraíba", u.Name)
+	assert.Equal(t, "pb", u.Slug())
+	assert.Equal(t, "https://example.com/bandeiras/pb.svg", u.FlagURL("https://example.com/bandeiras/"))
+	assert.Equal(t, "", u.FlagURL(""))
+
+	_, ok = UFByCode("xx")
+	assert.False(t, ok)
+}
+
+func TestEntities(t *testing.T) {
+	e, ok := EntityByCode("ministério")
+	assert.True(t, ok)
+	assert.Equal(t, "Ministério Público", e.Label)
+}
@@ -4,7 +4,9 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -40,8 +42,7 @@ func (s awsSession) getRemunerationsFromS3(limit, downloadLimit int, category, b
 		// Forma de evitar o download de arquivos que estão fora do limite.
 		if mustUnzip {
 			// Pegando apenas a chave do arquivo zipado.
-			object := strings.Replace(r.ZipUrl, fmt.Sprintf("https://%s.s3.amazonaws.com/", bucket), "", 1)
-			paths = append(paths, object)
+			paths = append(paths, zipKey(bucket, r.ZipUrl))
 			buffer = append(buffer, aws.WriteAtBuffer{})
 		}
 		/* Aqui a gente faz um "early return" se o número de resultados for maior
@@ -84,10 +85,9 @@ func (s awsSession) getRemunerationsFromS3(limit, downloadLimit int, category, b
 	}
 
 	var searchResults []searchResult
-	reachedLimit := false
 	for _, downloadObject := range forDownload {
 		// Queremos processar apenas os dados dentro dos limites definidos.
-		if reachedLimit {
+		if len(searchResults) >= limit {
 			break
 		}
 		buf, ok := downloadObject.Writer.(*aws.WriteAtBuffer)
@@ -95,41 +95,113 @@ func (s awsSession) getRemunerationsFromS3(limit, downloadLimit int, category, b
 			return nil, 0, fmt.Errorf("error converting downloaded object (%s) to WriteAtBuffer", *downloadObject.Object.Key)
 		}
 
-		zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(len(buf.Bytes())))
-		if err != nil {
-			return nil, 0, fmt.Errorf("error creating zip reader: %w", err)
+		/* Queremos guardar na memória apenas os resultados da categoria que o
+		usuário pediu.*/
+		err := readRemunerationsZip(buf.Bytes(), *downloadObject.Object.Key, func(rem searchResult) error {
+			if len(searchResults) >= limit {
+				return errLimitReached
+			}
+			if matchesCategory(category, rem) {
+				searchResults = append(searchResults, rem)
+			}
+			return nil
+		})
+		if err != nil && err != errLimitReached {
+			return nil, 0, err
 		}
+	}
+	return searchResults, numRows, err
+}
 
-		fReader, err := zipReader.File[0].Open()
+// errLimitReached é usado pelos callbacks para interromper a leitura de um zip.
+var errLimitReached = errors.New("limit reached")
+
+// streamRemunerationsFromS3 baixa os zips de remunerações um a um, na ordem de
+// results, e chama fn para cada linha da categoria pedida assim que o zip é
+// lido. Dessa forma, quem consome os dados não precisa esperar o download de
+// todos os arquivos. A leitura é interrompida após limit linhas.
+func (s awsSession) streamRemunerationsFromS3(limit int, category, bucket string, results []searchDetails, fn func(searchResult) error) error {
+	txn := s.Newrelic.StartTransaction("aws.StreamRemunerations")
+	defer txn.End()
+	ctx := newrelic.NewContext(aws.BackgroundContext(), txn)
+	downloader := s3manager.NewDownloader(s.Sess)
+
+	numRows := 0
+	for _, r := range results {
+		if numRows >= limit {
+			break
+		}
+		key := zipKey(bucket, r.ZipUrl)
+		buf := aws.WriteAtBuffer{}
+		_, err := downloader.DownloadWithContext(ctx, &buf, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
 		if err != nil {
-			return nil, 0, fmt.Errorf("error opening zip file (%s): %w", *downloadObject.Object.Key, err)
+			return fmt.Errorf("error downloading file (%s) from S3: %q", key, err)
+		}
+		err = readRemunerationsZip(buf.Bytes(), key, func(rem searchResult) error {
+			if numRows >= limit {
+				return errLimitReached
+			}
+			if !matchesCategory(category, rem) {
+				return nil
+			}
+			numRows++
+			return fn(rem)
+		})
+		if err != nil && err != errLimitReached {
+			return err
 		}
+	}
+	return nil
+}
 
-		defer fReader.Close()
+// readRemunerationsZip lê o CSV de remunerações contido no zip, chamando fn
+// para cada linha. A leitura é interrompida no primeiro erro retornado por fn.
+func readRemunerationsZip(b []byte, key string, fn func(searchResult) error) error {
+	zipReader, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return fmt.Errorf("error creating zip reader: %w", err)
+	}
+	if len(zipReader.File) == 0 {
+		return fmt.Errorf("empty zip file (%s)", key)
+	}
 
-		var r []searchResult
+	fReader, err := zipReader.File[0].Open()
+	if err != nil {
+		return fmt.Errorf("error opening zip file (%s): %w", key, err)
+	}
+	defer fReader.Close()
 
-		// Definimos o separador de colunas personalizado
-		csvReader := csv.NewReader(fReader)
-		csvReader.Comma = ';'
+	// Definimos o separador de colunas personalizado
+	csvReader := csv.NewReader(fReader)
+	csvReader.Comma = ';'
 
-		// Fazemos a leitura do arquivo
-		if err := gocsv.UnmarshalCSV(csvReader, &r); err != nil {
-			return nil, 0, fmt.Errorf("error unmarshaling remuneracoes.csv: %w", err)
+	// Fazemos a leitura do arquivo linha a linha
+	um, err := gocsv.NewUnmarshaller(csvReader, searchResult{})
+	if err != nil {
+		return fmt.Errorf("error unmarshaling remuneracoes.csv: %w", err)
+	}
+	for {
+		row, err := um.Read()
+		if err == io.EOF {
+			return nil
 		}
-
-		/* Queremos guardar na memória apenas os resultados da categoria que o
-		usuário pediu.*/
-		for _, rem := range r {
-			if len(searchResults) < limit {
-				if category == "" || category == rem.CategoriaContracheque || category == "tudo" {
-					searchResults = append(searchResults, rem)
-				}
-			} else {
-				reachedLimit = true
-				break
-			}
+		if err != nil {
+			return fmt.Errorf("error unmarshaling remuneracoes.csv: %w", err)
+		}
+		if err := fn(row.(searchResult)); err != nil {
+			return err
 		}
 	}
-	return searchResults, numRows, err
+}
+
+func matchesCategory(category string, rem searchResult) bool {
+	return category == "" || category == rem.CategoriaContracheque || category == "tudo"
+}
+
+// zipKey retorna apenas a chave do arquivo zipado no bucket.
+func zipKey(bucket, zipURL string) string {
+	return strings.Replace(zipURL, fmt.Sprintf("https://%s.s3.amazonaws.com/", bucket), "", 1)
 }
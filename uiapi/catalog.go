@@ -0,0 +1,341 @@
+package uiapi
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dadosjusbr/api/classification"
+	"github.com/dadosjusbr/api/lease"
+	"github.com/dadosjusbr/api/period"
+)
+
+// Tempo de validade da concessão do indexador. A concessão é renovada a cada
+// zip indexado.
+const catalogLeaseTTL = 10 * time.Minute
+
+// itemCatalog é o catálogo das rubricas (detalhamento_contracheque) encontradas
+// nos zips de remunerações. As rubricas de cada órgão/mês ficam guardadas no
+// catalogStore, junto com a identificação do zip indexado. A cada execução do
+// indexador, só são baixados os zips de órgãos/meses novos ou alterados, e as
+// rubricas de um órgão/mês alterado substituem as anteriores. Só a instância
+// que detém a concessão executa o indexador.
+type itemCatalog struct {
+	store catalogStore
+	lease *lease.Lease
+}
+
+// itemCount - ocorrências e valor total de uma rubrica em um órgão/mês.
+type itemCount struct {
+	occurrences int
+	total       float64
+}
+
+// catalogAggregate - ocorrências, valor total, primeiro e último mês e órgãos
+// de uma rubrica em todos os órgãos/meses indexados.
+type catalogAggregate struct {
+	key         itemKey
+	occurrences int
+	total       float64
+	first       period.YearMonth
+	last        period.YearMonth
+	agencies    []string
+}
+
+// catalogStore guarda as rubricas indexadas de cada órgão/mês.
+type catalogStore interface {
+	// catalogFingerprints retorna a identificação do zip indexado de cada órgão/mês.
+	catalogFingerprints() (map[agencyMonthKey]string, error)
+	// replaceCatalogMonth grava as rubricas de um órgão/mês, substituindo as anteriores.
+	replaceCatalogMonth(month agencyMonthKey, fingerprint string, items map[itemKey]itemCount) error
+	// removeCatalogMonth apaga as rubricas de um órgão/mês que não tem mais zip.
+	removeCatalogMonth(month agencyMonthKey) error
+	// catalogItems soma as rubricas de todos os órgãos/meses. Se agency for
+	// informado, retorna só as rubricas usadas pelo órgão; se since for
+	// informado, só as vistas pela primeira vez a partir deste mês.
+	catalogItems(agency string, since *period.YearMonth) ([]catalogAggregate, error)
+	// catalogStatus retorna a quantidade de órgãos/meses indexados e a data da
+	// última indexação.
+	catalogStatus() (int, time.Time, error)
+}
+
+// readZipFunc lê as linhas do zip de um órgão/mês, chamando fn para cada uma.
+type readZipFunc func(zip searchDetails, fn func(searchResult) error) error
+
+func newItemCatalog(store catalogStore, l *lease.Lease) *itemCatalog {
+	return &itemCatalog{store: store, lease: l}
+}
+
+// zipFingerprint identifica o conteúdo de um zip sem precisar baixá-lo.
+func zipFingerprint(z searchDetails) string {
+	return fmt.Sprintf("%s|%d|%d|%d", z.ZipUrl, z.Base, z.Outras, z.Descontos)
+}
+
+// index indexa os zips novos ou alterados e apaga os órgãos/meses que não têm
+// mais zip. Um zip que não pôde ser lido é ignorado e será tentado novamente
+// na próxima execução.
+func (c *itemCatalog) index(zips []searchDetails, read readZipFunc) error {
+	indexed, err := c.store.catalogFingerprints()
+	if err != nil {
+		return fmt.Errorf("error getting indexed zips: %w", err)
+	}
+	listed := make(map[agencyMonthKey]struct{}, len(zips))
+	failed := 0
+	for _, z := range zips {
+		key := agencyMonthKey{z.Orgao, z.Ano, z.Mes}
+		listed[key] = struct{}{}
+		fp := zipFingerprint(z)
+		if indexed[key] == fp {
+			continue
+		}
+		if ok, err := c.lease.Acquire(catalogLeaseTTL); err != nil || !ok {
+			return fmt.Errorf("item catalog lease lost (err: %v)", err)
+		}
+		items, err := readZipItems(z, read)
+		if err != nil {
+			log.Printf("[item catalog] error indexing %s: %q", z.ZipUrl, err)
+			failed++
+			continue
+		}
+		if err := c.store.replaceCatalogMonth(key, fp, items); err != nil {
+			return fmt.Errorf("error saving items of %s: %w", z.ZipUrl, err)
+		}
+	}
+	for key := range indexed {
+		if _, ok := listed[key]; !ok {
+			if err := c.store.removeCatalogMonth(key); err != nil {
+				return fmt.Errorf("error removing items of %s %d/%d: %w", key.agency, key.month, key.year, err)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d zips could not be indexed", failed)
+	}
+	return nil
+}
+
+// readZipItems conta as rubricas de um zip. O zip só é gravado depois de lido
+// por completo.
+func readZipItems(z searchDetails, read readZipFunc) (map[itemKey]itemCount, error) {
+	items := make(map[itemKey]itemCount)
+	err := read(z, func(r searchResult) error {
+		value, err := parseValue(r.Valor)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s (%s %d/%d): %w", r.Nome, r.Orgao, r.Mes, r.Ano, err)
+		}
+		key := itemKey{category: r.CategoriaContracheque, item: strings.TrimSpace(r.DetalhamentoContracheque)}
+		c := items[key]
+		c.occurrences++
+		c.total += value
+		items[key] = c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// catalogFilter - filtros da consulta ao catálogo. Campos vazios não filtram.
+type catalogFilter struct {
+	category string
+	agency   string
+	since    *period.YearMonth // apenas rubricas vistas pela primeira vez a partir deste mês
+}
+
+// items retorna as rubricas do catálogo, classificadas pelo dicionário e
+// ordenadas pelo valor total, do maior para o menor.
+func (c *itemCatalog) items(dict *classification.Dictionary, f catalogFilter) ([]catalogItem, error) {
+	aggregates, err := c.store.catalogItems(f.agency, f.since)
+	if err != nil {
+		return nil, err
+	}
+	items := []catalogItem{}
+	for _, a := range aggregates {
+		category := dict.Classify(a.key.category, a.key.item)
+		if f.category != "" && f.category != category {
+			continue
+		}
+		agencies := append([]string{}, a.agencies...)
+		sort.Strings(agencies)
+		items = append(items, catalogItem{
+			Item:            a.key.item,
+			PayslipCategory: a.key.category,
+			Category:        category,
+			Occurrences:     a.occurrences,
+			Total:           a.total,
+			FirstSeen:       a.first.String(),
+			LastSeen:        a.last.String(),
+			Agencies:        agencies,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Total != items[j].Total {
+			return items[i].Total > items[j].Total
+		}
+		if items[i].Item != items[j].Item {
+			return items[i].Item < items[j].Item
+		}
+		return items[i].PayslipCategory < items[j].PayslipCategory
+	})
+	return items, nil
+}
+
+// status retorna a quantidade de órgãos/meses indexados, a data da última
+// indexação e se há uma indexação em andamento em alguma instância.
+func (c *itemCatalog) status() (int, time.Time, bool, error) {
+	indexed, updated, err := c.store.catalogStatus()
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	indexing, err := c.lease.Held()
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	return indexed, updated, indexing, nil
+}
+
+// indexItemCatalog lista os zips de remunerações e indexa os novos ou
+// alterados no catálogo, se esta instância obtiver a concessão do indexador.
+func (h handler) indexItemCatalog() error {
+	ok, err := h.catalog.lease.Acquire(catalogLeaseTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		log.Printf("[item catalog] indexer running in another instance")
+		return nil
+	}
+	defer func() {
+		if err := h.catalog.lease.Release(); err != nil {
+			log.Printf("[item catalog] %q", err)
+		}
+	}()
+	zips, err := h.db.filter(h.db.remunerationQuery(nil), nil)
+	if err != nil {
+		return fmt.Errorf("error listing remuneration zips: %w", err)
+	}
+	return h.catalog.index(zips, func(z searchDetails, fn func(searchResult) error) error {
+		return h.sess.streamRemunerationsFromS3(math.MaxInt, "", h.s3Bucket, []searchDetails{z}, fn)
+	})
+}
+
+// StartItemCatalogIndexer indexa o catálogo de rubricas em segundo plano,
+// repetindo a indexação a cada interval.
+func (h handler) StartItemCatalogIndexer(interval time.Duration) {
+	go func() {
+		for {
+			start := time.Now()
+			if err := h.indexItemCatalog(); err != nil {
+				log.Printf("[item catalog] error indexing catalog: %q", err)
+			}
+			if indexed, _, err := h.catalog.store.catalogStatus(); err == nil {
+				log.Printf("[item catalog] %d agency/months indexed in %s", indexed, time.Since(start))
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// memoryCatalogStore guarda o catálogo em memória. Os dados são perdidos ao
+// reiniciar a API: é usado nos testes e quando não há banco.
+type memoryCatalogStore struct {
+	mu      sync.RWMutex
+	months  map[agencyMonthKey]memoryCatalogMonth
+	updated time.Time
+}
+
+type memoryCatalogMonth struct {
+	fingerprint string
+	items       map[itemKey]itemCount
+}
+
+func newMemoryCatalogStore() *memoryCatalogStore {
+	return &memoryCatalogStore{months: make(map[agencyMonthKey]memoryCatalogMonth)}
+}
+
+func (m *memoryCatalogStore) catalogFingerprints() (map[agencyMonthKey]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	fps := make(map[agencyMonthKey]string, len(m.months))
+	for k, v := range m.months {
+		fps[k] = v.fingerprint
+	}
+	return fps, nil
+}
+
+func (m *memoryCatalogStore) replaceCatalogMonth(month agencyMonthKey, fingerprint string, items map[itemKey]itemCount) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.months[month] = memoryCatalogMonth{fingerprint: fingerprint, items: items}
+	m.updated = time.Now()
+	return nil
+}
+
+func (m *memoryCatalogStore) removeCatalogMonth(month agencyMonthKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.months, month)
+	return nil
+}
+
+func (m *memoryCatalogStore) catalogItems(agency string, since *period.YearMonth) ([]catalogAggregate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	aggregates := make(map[itemKey]*catalogAggregate)
+	used := make(map[itemKey]map[string]struct{})
+	for month, data := range m.months {
+		ym := period.YearMonth{Year: month.year, Month: month.month}
+		for key, c := range data.items {
+			a, ok := aggregates[key]
+			if !ok {
+				a = &catalogAggregate{key: key, first: ym, last: ym}
+				aggregates[key] = a
+				used[key] = make(map[string]struct{})
+			}
+			a.occurrences += c.occurrences
+			a.total += c.total
+			if monthIndex(ym) < monthIndex(a.first) {
+				a.first = ym
+			}
+			if monthIndex(ym) > monthIndex(a.last) {
+				a.last = ym
+			}
+			used[key][month.agency] = struct{}{}
+		}
+	}
+	result := []catalogAggregate{}
+	for key, a := range aggregates {
+		if _, ok := used[key][agency]; agency != "" && !ok {
+			continue
+		}
+		if since != nil && monthIndex(a.first) < monthIndex(*since) {
+			continue
+		}
+		for ag := range used[key] {
+			a.agencies = append(a.agencies, ag)
+		}
+		result = append(result, *a)
+	}
+	return result, nil
+}
+
+func (m *memoryCatalogStore) catalogStatus() (int, time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.months), m.updated, nil
+}
+
+// monthIndex numera os meses em sequência, para comparações e para guardar o
+// mês em uma coluna.
+func monthIndex(m period.YearMonth) int {
+	return m.Year*12 + m.Month - 1
+}
+
+func fromMonthIndex(i int) period.YearMonth {
+	return period.YearMonth{Year: i / 12, Month: i%12 + 1}
+}
@@ -0,0 +1,103 @@
+package uiapi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dadosjusbr/api/classification"
+)
+
+type categoryKey struct {
+	payslipCategory string
+	category        string
+}
+
+// categoryAggregator soma as linhas dos zips de remunerações pelas categorias
+// do dicionário de classificação das rubricas.
+type categoryAggregator struct {
+	dict       *classification.Dictionary
+	categories map[categoryKey]*itemCategory
+	members    map[categoryKey]map[memberKey]struct{}
+	items      map[categoryKey]map[string]struct{}
+}
+
+func newCategoryAggregator(dict *classification.Dictionary) *categoryAggregator {
+	return &categoryAggregator{
+		dict:       dict,
+		categories: make(map[categoryKey]*itemCategory),
+		members:    make(map[categoryKey]map[memberKey]struct{}),
+		items:      make(map[categoryKey]map[string]struct{}),
+	}
+}
+
+func (a *categoryAggregator) add(r searchResult) error {
+	value, err := parseValue(r.Valor)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s (%s %d/%d): %w", r.Nome, r.Orgao, r.Mes, r.Ano, err)
+	}
+	key := categoryKey{
+		payslipCategory: r.CategoriaContracheque,
+		category:        a.dict.Classify(r.CategoriaContracheque, r.DetalhamentoContracheque),
+	}
+	c, ok := a.categories[key]
+	if !ok {
+		c = &itemCategory{PayslipCategory: key.payslipCategory, Category: key.category}
+		a.categories[key] = c
+		a.members[key] = make(map[memberKey]struct{})
+		a.items[key] = make(map[string]struct{})
+	}
+	c.Total += value
+	member := memberKey{agency: r.Orgao, year: r.Ano, month: r.Mes, name: r.Nome}
+	if r.Matricula != nil {
+		member.enrollment = *r.Matricula
+	}
+	a.members[key][member] = struct{}{}
+	a.items[key][r.DetalhamentoContracheque] = struct{}{}
+	return nil
+}
+
+// result retorna as categorias ordenadas por categoria do contracheque e pelo
+// total, do maior para o menor. Os totais são multiplicados pelo fator de
+// correção monetária f. O agregador não é alterado, então result pode ser
+// chamado mais de uma vez.
+func (a *categoryAggregator) result(f float64) []itemCategory {
+	categories := []itemCategory{}
+	for key, c := range a.categories {
+		category := itemCategory{
+			PayslipCategory: c.PayslipCategory,
+			Category:        c.Category,
+			Total:           c.Total * f,
+			Members:         len(a.members[key]),
+			Items:           make([]string, 0, len(a.items[key])),
+		}
+		for i := range a.items[key] {
+			category.Items = append(category.Items, i)
+		}
+		sort.Strings(category.Items)
+		categories = append(categories, category)
+	}
+	order := map[string]int{"base": 0, "outras": 1, "descontos": 2}
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := categories[i], categories[j]
+		if a.PayslipCategory != b.PayslipCategory {
+			return order[a.PayslipCategory] < order[b.PayslipCategory]
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Category < b.Category
+	})
+	return categories
+}
+
+// classifyRemunerations baixa os zips de results e soma suas linhas pelas
+// categorias do dicionário.
+func (h handler) classifyRemunerations(dict *classification.Dictionary, results []searchDetails) (*categoryAggregator, error) {
+	agg := newCategoryAggregator(dict)
+	err := h.sess.streamRemunerationsFromS3(math.MaxInt, "", h.s3Bucket, results, agg.add)
+	if err != nil {
+		return nil, err
+	}
+	return agg, nil
+}
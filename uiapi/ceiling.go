@@ -0,0 +1,112 @@
+package uiapi
+
+import (
+	"sort"
+
+	"github.com/dadosjusbr/api/classification"
+	"github.com/dadosjusbr/api/tables"
+)
+
+type agencyMonthKey struct {
+	agency string
+	year   int
+	month  int
+}
+
+// newCeilingReport compara a remuneração mensal sujeita ao teto de cada membro
+// com o teto em vigor no mês. A remuneração sujeita ao teto exclui as rubricas
+// das categorias do dicionário marcadas como fora do teto (verbas
+// indenizatórias, gratificação natalina, adicional de férias e retroativos).
+// Meses anteriores ao início da tabela do teto são ignorados. A comparação usa
+// os valores nominais; o deflator corrige apenas os valores do relatório.
+func newCeilingReport(members []*memberRemuneration, ceiling tables.Series, dict *classification.Dictionary, deflator *tables.Deflator) ceilingReport {
+	report := ceilingReport{
+		CeilingTableVersion: ceiling.Version,
+		DictionaryVersion:   dict.Version,
+		ExcludedCategories:  []string{},
+		Correction:          deflator.Correction(),
+	}
+	for _, c := range dict.Categories {
+		if c.OutsideCeiling {
+			report.ExcludedCategories = append(report.ExcludedCategories, c.ID)
+		}
+	}
+	months := map[agencyMonthKey]*ceilingMonth{}
+	items := map[string]*ceilingItem{}
+	people := map[memberKey]struct{}{}
+	for _, m := range members {
+		value, ok := ceiling.ValueAt(m.Year, m.Month)
+		if !ok {
+			continue
+		}
+		f := deflator.Factor(m.Year, m.Month)
+		key := agencyMonthKey{m.Agency, m.Year, m.Month}
+		month, ok := months[key]
+		if !ok {
+			month = &ceilingMonth{Agency: m.Agency, Year: m.Year, Month: m.Month, Ceiling: value * f}
+			months[key] = month
+		}
+		month.MemberCount++
+		subject := 0.0
+		for _, i := range m.Items {
+			if dict.SubjectToCeiling(i.Category, i.Item) {
+				subject += i.Value
+			}
+		}
+		excess := subject - value
+		if excess <= 0 {
+			continue
+		}
+		excess *= f
+		month.MembersAboveCeiling++
+		month.AmountAboveCeiling += excess
+		report.OccurrencesAboveCeiling++
+		report.AmountAboveCeiling += excess
+
+		person := memberKey{agency: m.Agency, name: m.Name}
+		if m.Enrollment != nil {
+			person.enrollment = *m.Enrollment
+		}
+		people[person] = struct{}{}
+
+		for _, i := range m.Items {
+			if i.Category != "outras" || !dict.SubjectToCeiling(i.Category, i.Item) {
+				continue
+			}
+			item, ok := items[i.Item]
+			if !ok {
+				item = &ceilingItem{Item: i.Item, Category: dict.Classify(i.Category, i.Item)}
+				items[i.Item] = item
+			}
+			item.Members++
+			item.Value += i.Value * f
+		}
+	}
+	report.MembersAboveCeiling = len(people)
+
+	report.Months = []ceilingMonth{}
+	for _, m := range months {
+		report.Months = append(report.Months, *m)
+	}
+	sort.Slice(report.Months, func(i, j int) bool {
+		a, b := report.Months[i], report.Months[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Month != b.Month {
+			return a.Month < b.Month
+		}
+		return a.Agency < b.Agency
+	})
+	report.Items = []ceilingItem{}
+	for _, i := range items {
+		report.Items = append(report.Items, *i)
+	}
+	sort.Slice(report.Items, func(i, j int) bool {
+		if report.Items[i].Value != report.Items[j].Value {
+			return report.Items[i].Value > report.Items[j].Value
+		}
+		return report.Items[i].Item < report.Items[j].Item
+	})
+	return report
+}
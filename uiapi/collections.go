@@ -0,0 +1,12 @@
+package uiapi
+
+import (
+	"github.com/dadosjusbr/storage/models"
+)
+
+// RecentCollections retorna as coletas atuais com dados, da mais recente para a
+// mais antiga pela data da coleta, limitadas a limit. É usado pelo feed geral
+// da API pública.
+func (h handler) RecentCollections(limit int) ([]models.AgencyMonthlyInfo, error) {
+	return h.db.recentCollections(limit)
+}
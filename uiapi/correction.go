@@ -0,0 +1,54 @@
+package uiapi
+
+import (
+	"fmt"
+
+	"github.com/dadosjusbr/api/tables"
+	"github.com/labstack/echo/v4"
+)
+
+// conversion reúne a correção monetária e a unidade pedidas na requisição.
+type conversion struct {
+	deflator *tables.Deflator
+	unit     *tables.Unit
+}
+
+// conversionFromQuery lê os parâmetros corrigir, base e unidade. Como os
+// múltiplos do salário mínimo já descontam a variação de preços, a correção
+// monetária não pode ser combinada com uma unidade.
+func conversionFromQuery(c echo.Context) (conversion, error) {
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return conversion{}, err
+	}
+	u, err := tables.NewUnit(c.QueryParam("unidade"))
+	if err != nil {
+		return conversion{}, err
+	}
+	if deflator != nil && u != nil {
+		return conversion{}, fmt.Errorf("os parâmetros corrigir e unidade não podem ser usados juntos")
+	}
+	return conversion{deflator: deflator, unit: u}, nil
+}
+
+func (cv conversion) factor(year, month int) float64 {
+	return cv.deflator.Factor(year, month) * cv.unit.Factor(year, month)
+}
+
+// yearFactor converte valores anuais. A média é feita só sobre os meses com
+// dados, que são os que compõem os valores anuais.
+func (cv conversion) yearFactor(year int, months []int) float64 {
+	return cv.deflator.YearFactor(year, months...) * cv.unit.YearFactor(year, months...)
+}
+
+// isIdentity diz se a conversão não altera os valores.
+func (cv conversion) isIdentity() bool {
+	return cv.deflator == nil && cv.unit == nil
+}
+
+func newUnit(u *tables.Unit) *unit {
+	if u == nil {
+		return nil
+	}
+	return &unit{Name: u.Name, TableVersion: u.Version}
+}
@@ -0,0 +1,166 @@
+package uiapi
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+)
+
+const (
+	dataPackageCSV    = "dadosjusbr-remuneracoes.csv"
+	dataPackageJSON   = "datapackage.json"
+	dataPackageReadme = "README.md"
+)
+
+// Esquema das colunas de searchResult, na mesma ordem em que aparecem no CSV.
+var searchResultSchema = tableSchema{
+	Fields: []tableField{
+		{Name: "orgao", Type: "string", Description: "Sigla do órgão. Exemplos: tjal, mppb, trt13.", Constraints: &fieldConstraints{Required: true}},
+		{Name: "mes", Type: "integer", Description: "Mês de referência da remuneração.", Constraints: &fieldConstraints{Required: true}},
+		{Name: "ano", Type: "integer", Description: "Ano de referência da remuneração.", Constraints: &fieldConstraints{Required: true}},
+		{Name: "matricula", Type: "string", Description: "Matrícula do membro, quando disponibilizada pelo órgão."},
+		{Name: "nome", Type: "string", Description: "Nome do membro.", Constraints: &fieldConstraints{Required: true}},
+		{Name: "cargo", Type: "string", Description: "Cargo do membro, quando disponibilizado pelo órgão."},
+		{Name: "lotacao", Type: "string", Description: "Lotação do membro, quando disponibilizada pelo órgão."},
+		{Name: "categoria_contracheque", Type: "string", Description: "Categoria da rubrica no contracheque: remuneração base, outras remunerações ou descontos.", Constraints: &fieldConstraints{Required: true, Enum: []string{"base", "outras", "descontos"}}},
+		{Name: "detalhamento_contracheque", Type: "string", Description: "Nome da rubrica como publicado pelo órgão. Exemplos: subsídio, auxílio-alimentação, imposto de renda."},
+		{Name: "valor", Type: "number", Description: "Valor da rubrica, em reais, com ponto como separador decimal. Vazio quando não informado pelo órgão."},
+	},
+}
+
+// dataPackageSources lista as fontes dos dados baixados: o pacote de dados
+// anual de cada órgão e o arquivo de remunerações de cada órgão/mês. Os pacotes
+// anuais que não puderam ser obtidos continuam listados, marcados como
+// indisponíveis, para que o pacote não pareça completo sem ser.
+func (h handler) dataPackageSources(searchResults []searchResult, results []searchDetails) []dataPackageSource {
+	type agencyMonth struct {
+		agency string
+		year   int
+		month  int
+	}
+	months := make(map[agencyMonth]struct{})
+	years := make(map[agencyMonth]struct{})
+	for _, r := range searchResults {
+		months[agencyMonth{r.Orgao, r.Ano, r.Mes}] = struct{}{}
+		years[agencyMonth{agency: r.Orgao, year: r.Ano}] = struct{}{}
+	}
+
+	var sources []dataPackageSource
+	for y := range years {
+		source := dataPackageSource{Title: fmt.Sprintf("Pacote de dados de %s (%d)", y.agency, y.year)}
+		key := fmt.Sprintf("%s/datapackage/%s-%d.zip", y.agency, y.agency, y.year)
+		bkp, err := h.client.Cloud.GetFile(key)
+		switch {
+		case err != nil:
+			log.Printf("[datapackage] error getting %s: %q", key, err)
+			source.Unavailable = true
+		case bkp == nil || bkp.URL == "":
+			source.Unavailable = true
+		default:
+			source.Path = bkp.URL
+		}
+		sources = append(sources, source)
+	}
+	for _, r := range results {
+		if _, ok := months[agencyMonth{r.Orgao, r.Ano, r.Mes}]; !ok {
+			continue
+		}
+		sources = append(sources, dataPackageSource{
+			Title: fmt.Sprintf("Remunerações de %s (%02d/%d)", r.Orgao, r.Mes, r.Ano),
+			Path:  r.ZipUrl,
+		})
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Title < sources[j].Title
+	})
+	return sources
+}
+
+func newDataPackage(sources []dataPackageSource, created time.Time) dataPackage {
+	return dataPackage{
+		Profile:     "tabular-data-package",
+		Name:        "dadosjusbr-remuneracoes",
+		Title:       "Remunerações do sistema de justiça brasileiro",
+		Description: "Remunerações de membros do sistema de justiça coletadas, padronizadas e publicadas pelo DadosJusBr.",
+		Homepage:    "https://dadosjusbr.org",
+		Created:     created.Format(time.RFC3339),
+		Sources:     sources,
+		Resources: []dataResource{{
+			Profile:   "tabular-data-resource",
+			Name:      "remuneracoes",
+			Path:      dataPackageCSV,
+			Format:    "csv",
+			Mediatype: "text/csv",
+			Encoding:  "utf-8",
+			Schema:    searchResultSchema,
+		}},
+	}
+}
+
+// writeDataPackage escreve um zip contendo o descritor datapackage.json,
+// o CSV com as remunerações e um README listando as fontes dos dados. Os
+// valores são escritos com ponto decimal, como declarado no esquema.
+func writeDataPackage(w io.Writer, pkg dataPackage, searchResults []searchResult) error {
+	rows := make([]searchResult, len(searchResults))
+	for i, r := range searchResults {
+		r.Valor = normalizeValue(r.Valor)
+		rows[i] = r
+	}
+	zw := zip.NewWriter(w)
+
+	f, err := zw.Create(dataPackageJSON)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", dataPackageJSON, err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(pkg); err != nil {
+		return fmt.Errorf("error encoding %s: %w", dataPackageJSON, err)
+	}
+
+	f, err = zw.Create(dataPackageCSV)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", dataPackageCSV, err)
+	}
+	if err := gocsv.Marshal(rows, f); err != nil {
+		return fmt.Errorf("error marshaling %s: %w", dataPackageCSV, err)
+	}
+
+	f, err = zw.Create(dataPackageReadme)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", dataPackageReadme, err)
+	}
+	if _, err := io.WriteString(f, dataPackageReadmeText(pkg)); err != nil {
+		return fmt.Errorf("error writing %s: %w", dataPackageReadme, err)
+	}
+	return zw.Close()
+}
+
+func dataPackageReadmeText(pkg dataPackage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", pkg.Title)
+	fmt.Fprintf(&b, "%s\n\n", pkg.Description)
+	fmt.Fprintf(&b, "Gerado em %s a partir de %s.\n\n", pkg.Created, pkg.Homepage)
+	b.WriteString("## Arquivos\n\n")
+	fmt.Fprintf(&b, "- `%s`: descritor do pacote no formato Frictionless Data Package, com o esquema de cada coluna.\n", dataPackageJSON)
+	fmt.Fprintf(&b, "- `%s`: remunerações que atendem aos filtros da pesquisa.\n\n", dataPackageCSV)
+	b.WriteString("## Fontes\n\n")
+	if len(pkg.Sources) == 0 {
+		b.WriteString("Nenhuma fonte encontrada.\n")
+	}
+	for _, s := range pkg.Sources {
+		if s.Unavailable {
+			fmt.Fprintf(&b, "- %s: indisponível no momento da geração deste pacote\n", s.Title)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", s.Title, s.Path)
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package uiapi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dadosjusbr/api/distribution"
+	"github.com/dadosjusbr/api/tables"
+)
+
+// Quantidade máxima de distribuições em cache. Cada entrada é pequena, mas há
+// uma por órgão/mês, órgão/ano e correção consultados.
+const maxCachedDistributions = 2000
+
+// distributionCache guarda as distribuições já calculadas, junto com a
+// identificação dos zips usados no cálculo. Se algum zip mudar (uma nova
+// coleta, por exemplo), a distribuição é recalculada. Quando o cache enche, a
+// distribuição usada há mais tempo é descartada.
+type distributionCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]cachedDistribution
+	uses       uint64 // contador de acessos, para saber qual entrada foi usada há mais tempo
+}
+
+type cachedDistribution struct {
+	fingerprint string
+	dist        distribution.Distribution
+	lastUse     uint64
+}
+
+func newDistributionCache(maxEntries int) *distributionCache {
+	return &distributionCache{maxEntries: maxEntries, entries: make(map[string]cachedDistribution)}
+}
+
+// get retorna a distribuição em cache ou a calcula com compute. O cálculo é
+// feito fora da trava, então requisições simultâneas podem calcular a mesma
+// distribuição mais de uma vez.
+func (c *distributionCache) get(key, fingerprint string, compute func() (distribution.Distribution, error)) (distribution.Distribution, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && e.fingerprint == fingerprint {
+		c.uses++
+		e.lastUse = c.uses
+		c.entries[key] = e
+		c.mu.Unlock()
+		return e.dist, nil
+	}
+	c.mu.Unlock()
+	d, err := compute()
+	if err != nil {
+		return distribution.Distribution{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evictOldest()
+	}
+	c.uses++
+	c.entries[key] = cachedDistribution{fingerprint: fingerprint, dist: d, lastUse: c.uses}
+	return d, nil
+}
+
+// evictOldest descarta a entrada usada há mais tempo. Deve ser chamada com a
+// trava.
+func (c *distributionCache) evictOldest() {
+	oldest := ""
+	var oldestUse uint64
+	for k, e := range c.entries {
+		if oldest == "" || e.lastUse < oldestUse {
+			oldest, oldestUse = k, e.lastUse
+		}
+	}
+	delete(c.entries, oldest)
+}
+
+// membersDistribution calcula a distribuição das remunerações bruta e líquida,
+// considerando cada par membro/mês uma observação. Cada observação é corrigida
+// pelo fator do seu mês antes do cálculo, como no histograma.
+func membersDistribution(members []*memberRemuneration, deflator *tables.Deflator) distribution.Distribution {
+	gross := make([]float64, 0, len(members))
+	net := make([]float64, 0, len(members))
+	for _, m := range members {
+		f := deflator.Factor(m.Year, m.Month)
+		gross = append(gross, m.Remunerations()*f)
+		net = append(net, m.NetRemuneration()*f)
+	}
+	return distribution.New(gross, net)
+}
+
+// AgencyMonthDistribution retorna a distribuição das remunerações de um
+// órgão/mês, calculada a partir do zip de remunerações. Retorna nil se não
+// houver zip para o órgão/mês.
+func (h handler) AgencyMonthDistribution(agency string, year, month int) (*distribution.Distribution, error) {
+	sp := &searchParams{
+		Years:    []string{strconv.Itoa(year)},
+		Months:   []string{strconv.Itoa(month)},
+		Agencies: []string{agency},
+	}
+	return h.zipsDistribution(fmt.Sprintf("%s/%d/%d", agency, year, month), sp, nil)
+}
+
+// AgencyYearDistribution retorna a distribuição das remunerações de um órgão
+// em um ano, com os pares membro/mês de todos os meses com dados, corrigidos
+// pelo deflator. Retorna nil se não houver zips para o órgão no ano.
+func (h handler) AgencyYearDistribution(agency string, year int, deflator *tables.Deflator) (*distribution.Distribution, error) {
+	sp := &searchParams{
+		Years:    []string{strconv.Itoa(year)},
+		Agencies: []string{agency},
+	}
+	key := fmt.Sprintf("%s/%d", agency, year)
+	if c := deflator.Correction(); c != nil {
+		key = fmt.Sprintf("%s/%s/%s/%s", key, c.Index, c.Base, c.TableVersion)
+	}
+	return h.zipsDistribution(key, sp, deflator)
+}
+
+func (h handler) zipsDistribution(key string, sp *searchParams, deflator *tables.Deflator) (*distribution.Distribution, error) {
+	results, err := h.db.filter(h.db.remunerationQuery(sp), h.db.arguments(sp))
+	if err != nil {
+		return nil, fmt.Errorf("error querying remuneration zips (%s): %w", key, err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	var fingerprints []string
+	for _, r := range results {
+		fingerprints = append(fingerprints, zipFingerprint(r))
+	}
+	sort.Strings(fingerprints)
+	d, err := h.distributions.get(key, strings.Join(fingerprints, ";"), func() (distribution.Distribution, error) {
+		members, err := h.memberRemunerations(results)
+		if err != nil {
+			return distribution.Distribution{}, fmt.Errorf("error reading remunerations (%s): %w", key, err)
+		}
+		return membersDistribution(members, deflator), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
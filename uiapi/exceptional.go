@@ -0,0 +1,126 @@
+package uiapi
+
+import (
+	"sort"
+
+	"github.com/dadosjusbr/api/classification"
+	"github.com/dadosjusbr/api/distribution"
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/api/tables"
+)
+
+const (
+	defaultExceptionalRatio   = 1.0
+	defaultExceptionalHistory = 12
+	maxExceptionalHistory     = 12
+	maxExceptionalMonths      = 12
+	// Quantidade mínima de outros meses do membro para que o mês seja avaliado.
+	minExceptionalHistory = 3
+)
+
+// personKey identifica um membro em vários meses.
+type personKey struct {
+	agency     string
+	enrollment string
+	name       string
+}
+
+// findExceptionalPayments compara cada rubrica de outras remunerações recebida
+// por um membro em um mês do período com os demais meses do próprio membro. O
+// excesso da rubrica sobre a sua mediana (que é zero se a rubrica não é
+// recorrente) é dividido pela mediana da remuneração bruta do membro; a
+// rubrica é listada se essa razão for pelo menos minRatio. Membros com menos
+// de três outros meses não são avaliados.
+func findExceptionalPayments(members []*memberRemuneration, p *period.Period, minRatio float64, dict *classification.Dictionary, deflator *tables.Deflator) []exceptionalPayment {
+	history := map[personKey][]*memberRemuneration{}
+	for _, m := range members {
+		key := personKey{agency: m.Agency, name: m.Name}
+		if m.Enrollment != nil {
+			key.enrollment = *m.Enrollment
+		}
+		history[key] = append(history[key], m)
+	}
+
+	payments := []exceptionalPayment{}
+	for _, months := range history {
+		if len(months) <= minExceptionalHistory {
+			continue
+		}
+		for i, m := range months {
+			if !p.Contains(m.Year, m.Month) {
+				continue
+			}
+			others := make([]*memberRemuneration, 0, len(months)-1)
+			others = append(others, months[:i]...)
+			others = append(others, months[i+1:]...)
+			var gross []float64
+			for _, o := range others {
+				gross = append(gross, o.Remunerations()*deflator.Factor(o.Year, o.Month))
+			}
+			medianGross := median(gross)
+			if medianGross <= 0 {
+				continue
+			}
+			f := deflator.Factor(m.Year, m.Month)
+			for _, item := range m.Items {
+				if item.Category != "outras" {
+					continue
+				}
+				var values []float64
+				for _, o := range others {
+					values = append(values, o.itemValue(item.Category, item.Item)*deflator.Factor(o.Year, o.Month))
+				}
+				value := item.Value * f
+				itemMedian := median(values)
+				ratio := (value - itemMedian) / medianGross
+				if ratio < minRatio {
+					continue
+				}
+				payments = append(payments, exceptionalPayment{
+					Name:               m.Name,
+					Enrollment:         m.Enrollment,
+					Role:               m.Role,
+					Year:               m.Year,
+					Month:              m.Month,
+					Item:               item.Item,
+					Category:           dict.Classify(item.Category, item.Item),
+					Value:              value,
+					ItemMedian:         itemMedian,
+					MedianRemuneration: medianGross,
+					Ratio:              ratio,
+					ComparedMonths:     len(others),
+				})
+			}
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool {
+		if payments[i].Ratio != payments[j].Ratio {
+			return payments[i].Ratio > payments[j].Ratio
+		}
+		if payments[i].Name != payments[j].Name {
+			return payments[i].Name < payments[j].Name
+		}
+		if payments[i].Year != payments[j].Year {
+			return payments[i].Year < payments[j].Year
+		}
+		return payments[i].Month < payments[j].Month
+	})
+	return payments
+}
+
+// itemValue retorna o valor recebido na rubrica no mês, ou zero.
+func (m memberRemuneration) itemValue(category, item string) float64 {
+	for _, i := range m.Items {
+		if i.Category == category && i.Item == item {
+			return i.Value
+		}
+	}
+	return 0
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return distribution.Percentile(sorted, 50)
+}
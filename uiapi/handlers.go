@@ -1,6 +1,7 @@
 package uiapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,6 +10,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dadosjusbr/api/classification"
+	"github.com/dadosjusbr/api/lease"
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/api/tables"
+	"github.com/dadosjusbr/api/taxonomy"
 	"github.com/dadosjusbr/storage"
 	strModels "github.com/dadosjusbr/storage/models"
 	"github.com/gocarina/gocsv"
@@ -26,9 +32,12 @@ type handler struct {
 	envOmittedFields []string
 	searchLimit      int
 	downloadLimit    int
+	catalog          *itemCatalog
+	distributions    *distributionCache
+	flagsURL         string
 }
 
-func NewHandler(client *storage.Client, conn *gorm.DB, newrelic *newrelic.Application, awsRegion string, s3Bucket string, loc *time.Location, envOmittedFields []string, searchLimit, downloadLimit int) (*handler, error) {
+func NewHandler(client *storage.Client, conn *gorm.DB, newrelic *newrelic.Application, awsRegion string, s3Bucket string, loc *time.Location, envOmittedFields []string, searchLimit, downloadLimit int, flagsURL string) (*handler, error) {
 	db := &postgresDB{
 		conn:     conn,
 		newrelic: newrelic,
@@ -37,6 +46,13 @@ func NewHandler(client *storage.Client, conn *gorm.DB, newrelic *newrelic.Applic
 	if err != nil {
 		return nil, err
 	}
+	// Sem banco, o catálogo fica em memória e o indexador roda nesta instância.
+	var catalog *itemCatalog
+	if conn != nil {
+		catalog = newItemCatalog(db, lease.New(conn, "catalogo_rubricas"))
+	} else {
+		catalog = newItemCatalog(newMemoryCatalogStore(), nil)
+	}
 	return &handler{
 		db:               db,
 		sess:             sess,
@@ -46,6 +62,9 @@ func NewHandler(client *storage.Client, conn *gorm.DB, newrelic *newrelic.Applic
 		envOmittedFields: envOmittedFields,
 		searchLimit:      searchLimit,
 		downloadLimit:    downloadLimit,
+		catalog:          catalog,
+		distributions:    newDistributionCache(maxCachedDistributions),
+		flagsURL:         flagsURL,
 	}, nil
 }
 
@@ -306,14 +325,21 @@ func (h handler) GetTotalsOfAgencyYear(c echo.Context) error {
 //	@Produce		json
 //	@Param			orgao									path		string				true	"ID do órgão. Exemplos: tjal, tjba, mppb."
 //	@Param			ano										path		int					true	"Ano. Exemplo: 2018."
+//	@Param			corrigir								query		string				false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base									query		string				false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Param			unidade									query		string				false	"Unidade dos valores: reais (padrão) ou salario_minimo, em múltiplos do salário mínimo em vigor em cada mês. As médias por membro do ano são divididas pela média do salário mínimo nos meses com dados. Não pode ser combinada com corrigir, pois os múltiplos do salário mínimo já descontam a variação de preços. A unidade só está disponível aqui e no resumo anual do órgão."
 //	@Success		200										{object}	v2AgencyTotalsYear	"Requisição bem sucedida."
-//	@Failure		400										{string}	string				"Parâmetro ano ou orgao inválido."
+//	@Failure		400										{string}	string				"Parâmetro ano, orgao, corrigir, base ou unidade inválido."
 //	@Router			/uiapi/v2/orgao/totais/{orgao}/{ano} 	[get]
 func (h handler) V2GetTotalsOfAgencyYear(c echo.Context) error {
 	year, err := strconv.Atoi(c.Param("ano"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%s inválido", c.Param("ano")))
 	}
+	conv, err := conversionFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 	aID := c.Param("orgao")
 	agenciesMonthlyInfo, err := h.client.Db.GetMonthlyInfo([]strModels.Agency{{ID: aID}}, year)
 	if err != nil {
@@ -333,32 +359,26 @@ func (h handler) V2GetTotalsOfAgencyYear(c echo.Context) error {
 	}
 	host := c.Request().Host
 	strAgency.URL = fmt.Sprintf("%s/v2/orgao/%s", host, strAgency.ID)
+	var monthsWithData []int
 	for _, agencyMonthlyInfo := range agenciesMonthlyInfo[aID] {
 		if agencyMonthlyInfo.Summary != nil && agencyMonthlyInfo.Summary.BaseRemuneration.Total+agencyMonthlyInfo.Summary.OtherRemunerations.Total > 0 {
+			monthsWithData = append(monthsWithData, agencyMonthlyInfo.Month)
+			f := conv.factor(year, agencyMonthlyInfo.Month)
 			monthTotals := v2MonthTotals{Month: agencyMonthlyInfo.Month,
-				BaseRemuneration:            agencyMonthlyInfo.Summary.BaseRemuneration.Total,
-				OtherRemunerations:          agencyMonthlyInfo.Summary.OtherRemunerations.Total,
-				Remunerations:               agencyMonthlyInfo.Summary.Remunerations.Total,
-				Discounts:                   agencyMonthlyInfo.Summary.Discounts.Total,
-				BaseRemunerationPerCapita:   agencyMonthlyInfo.Summary.BaseRemuneration.Average,
-				OtherRemunerationsPerCapita: agencyMonthlyInfo.Summary.OtherRemunerations.Average,
-				RemunerationsPerCapita:      agencyMonthlyInfo.Summary.Remunerations.Average,
-				DiscountsPerCapita:          agencyMonthlyInfo.Summary.Discounts.Average,
+				BaseRemuneration:            agencyMonthlyInfo.Summary.BaseRemuneration.Total * f,
+				OtherRemunerations:          agencyMonthlyInfo.Summary.OtherRemunerations.Total * f,
+				Remunerations:               agencyMonthlyInfo.Summary.Remunerations.Total * f,
+				Discounts:                   agencyMonthlyInfo.Summary.Discounts.Total * f,
+				BaseRemunerationPerCapita:   agencyMonthlyInfo.Summary.BaseRemuneration.Average * f,
+				OtherRemunerationsPerCapita: agencyMonthlyInfo.Summary.OtherRemunerations.Average * f,
+				RemunerationsPerCapita:      agencyMonthlyInfo.Summary.Remunerations.Average * f,
+				DiscountsPerCapita:          agencyMonthlyInfo.Summary.Discounts.Average * f,
 				CrawlingTimestamp: timestamp{
 					Seconds: agencyMonthlyInfo.CrawlingTimestamp.GetSeconds(),
 					Nanos:   agencyMonthlyInfo.CrawlingTimestamp.GetNanos(),
 				},
 				MemberCount: agencyMonthlyInfo.Summary.Count,
-				ItemSummary: itemSummary{
-					FoodAllowance:        agencyMonthlyInfo.Summary.ItemSummary.FoodAllowance,
-					BonusLicense:         agencyMonthlyInfo.Summary.ItemSummary.BonusLicense,
-					VacationCompensation: agencyMonthlyInfo.Summary.ItemSummary.VacationCompensation,
-					Vacation:             agencyMonthlyInfo.Summary.ItemSummary.Vacation,
-					ChristmasBonus:       agencyMonthlyInfo.Summary.ItemSummary.ChristmasBonus,
-					CompensatoryLicense:  agencyMonthlyInfo.Summary.ItemSummary.CompensatoryLicense,
-					HealthAllowance:      agencyMonthlyInfo.Summary.ItemSummary.HealthAllowance,
-					Others:               agencyMonthlyInfo.Summary.ItemSummary.Others,
-				},
+				ItemSummary: newItemSummary(tables.ScaleItemSummary(agencyMonthlyInfo.Summary.ItemSummary, f)),
 			}
 			monthTotalsOfYear = append(monthTotalsOfYear, monthTotals)
 
@@ -397,6 +417,8 @@ func (h handler) V2GetTotalsOfAgencyYear(c echo.Context) error {
 		})
 		hasData = c.Collecting
 	}
+	// A média por membro do ano só considera os meses com dados.
+	yearFactor := conv.yearFactor(year, monthsWithData)
 	agencyTotalsYear := v2AgencyTotalsYear{
 		Year: year,
 		Agency: &agency{
@@ -414,11 +436,13 @@ func (h handler) V2GetTotalsOfAgencyYear(c echo.Context) error {
 		MonthTotals:    monthTotalsOfYear,
 		SummaryPackage: pkg,
 		AveragePerCapita: &perCapitaData{
-			BaseRemuneration:   strAveragePerCapita.BaseRemuneration,
-			OtherRemunerations: strAveragePerCapita.OtherRemunerations,
-			Discounts:          strAveragePerCapita.Discounts,
-			Remunerations:      strAveragePerCapita.Remunerations,
+			BaseRemuneration:   strAveragePerCapita.BaseRemuneration * yearFactor,
+			OtherRemunerations: strAveragePerCapita.OtherRemunerations * yearFactor,
+			Discounts:          strAveragePerCapita.Discounts * yearFactor,
+			Remunerations:      strAveragePerCapita.Remunerations * yearFactor,
 		},
+		Correction: conv.deflator.Correction(),
+		Unit:       newUnit(conv.unit),
 	}
 	return c.JSON(http.StatusOK, agencyTotalsYear)
 }
@@ -430,42 +454,21 @@ func (h handler) GetBasicInfoOfType(c echo.Context) error {
 	var agencies []strModels.Agency
 	var err error
 	var estadual bool
-	var exists bool
-	jurisdicao := map[string]string{
-		"justica-eleitoral":    "Eleitoral",
-		"ministerios-publicos": "Ministério",
-		"justica-estadual":     "Estadual",
-		"justica-do-trabalho":  "Trabalho",
-		"justica-federal":      "Federal",
-		"justica-militar":      "Militar",
-		"justica-superior":     "Superior",
-		"conselhos-de-justica": "Conselho",
-	}
-
-	// Adaptando as URLs do site com o banco de dados
-	// Primeiro consultamos entre as chaves do mapa.
-	if jurisdicao[groupName] != "" {
-		groupName = jurisdicao[groupName]
+	var shortName, flagURL string
+	// Adaptando as URLs do site com o banco de dados.
+	// Até a consolidação ser finalizada, o front também consulta a api com /Eleitoral, /Trabalho, etc.
+	if g, ok := taxonomy.GroupBySlug(groupName); ok {
+		groupName = g.Jurisdiction
+	} else if g, ok := taxonomy.GroupByJurisdiction(groupName); ok {
+		groupName = g.Jurisdiction
+	} else if uf, ok := taxonomy.UFByCode(groupName); ok {
+		groupName = uf.Code
+		estadual = true
+		shortName = uf.Code
+		flagURL = uf.FlagURL(h.flagsURL)
 	} else {
-		// Caso não encontremos entre as chaves, verificamos entre os valores do mapa.
-		// Isso pois, até a consolidação ser finalizada, o front consulta a api com /Eleitoral, /Trabalho, etc.
-		for _, value := range jurisdicao {
-			if groupName == value {
-				exists = true
-				break
-			}
-		}
-		// Se a jurisdição não existir no mapa, verificamos se trata-se de um estado
-		if !exists {
-			values := map[string]struct{}{"AC": {}, "AL": {}, "AP": {}, "AM": {}, "BA": {}, "CE": {}, "DF": {}, "ES": {}, "GO": {}, "MA": {}, "MT": {}, "MS": {}, "MG": {}, "PA": {}, "PB": {}, "PR": {}, "PE": {}, "PI": {}, "RJ": {}, "RN": {}, "RS": {}, "RO": {}, "RR": {}, "SC": {}, "SP": {}, "SE": {}, "TO": {}}
-			if _, estadual = values[groupName]; estadual {
-				exists = true
-			}
-		}
 		// Se o parâmetro dado não for encontrado de forma alguma, retornamos um NOT FOUND (404)
-		if !exists {
-			return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s.", groupName))
-		}
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: %s.", groupName))
 	}
 
 	if estadual {
@@ -492,7 +495,7 @@ func (h handler) GetBasicInfoOfType(c echo.Context) error {
 	for k := range agencies {
 		agenciesBasic = append(agenciesBasic, agencyBasic{Name: agencies[k].ID, FullName: agencies[k].Name, AgencyCategory: agencies[k].Entity})
 	}
-	state := state{Name: c.Param("grupo"), ShortName: "", FlagURL: "", Agency: agenciesBasic}
+	state := state{Name: c.Param("grupo"), ShortName: shortName, FlagURL: flagURL, Agency: agenciesBasic}
 	return c.JSON(http.StatusOK, state)
 }
 
@@ -510,42 +513,18 @@ func (h handler) V2GetBasicInfoOfType(c echo.Context) error {
 	var strAgencies []strModels.Agency
 	var err error
 	var estadual bool
-	var exists bool
-	jurisdicao := map[string]string{
-		"justica-eleitoral":    "Eleitoral",
-		"ministerios-publicos": "Ministério",
-		"justica-estadual":     "Estadual",
-		"justica-do-trabalho":  "Trabalho",
-		"justica-federal":      "Federal",
-		"justica-militar":      "Militar",
-		"justica-superior":     "Superior",
-		"conselhos-de-justica": "Conselho",
-	}
-
-	// Adaptando as URLs do site com o banco de dados
-	// Primeiro consultamos entre as chaves do mapa.
-	if jurisdicao[groupName] != "" {
-		groupName = jurisdicao[groupName]
+	// Adaptando as URLs do site com o banco de dados.
+	// Até a consolidação ser finalizada, o front também consulta a api com /Eleitoral, /Trabalho, etc.
+	if g, ok := taxonomy.GroupBySlug(groupName); ok {
+		groupName = g.Jurisdiction
+	} else if g, ok := taxonomy.GroupByJurisdiction(groupName); ok {
+		groupName = g.Jurisdiction
+	} else if uf, ok := taxonomy.UFByCode(groupName); ok {
+		groupName = uf.Code
+		estadual = true
 	} else {
-		// Caso não encontremos entre as chaves, verificamos entre os valores do mapa.
-		// Isso pois, até a consolidação ser finalizada, o front consulta a api com /Eleitoral, /Trabalho, etc.
-		for _, value := range jurisdicao {
-			if groupName == value {
-				exists = true
-				break
-			}
-		}
-		// Se a jurisdição não existir no mapa, verificamos se trata-se de um estado
-		if !exists {
-			values := map[string]struct{}{"AC": {}, "AL": {}, "AP": {}, "AM": {}, "BA": {}, "CE": {}, "DF": {}, "ES": {}, "GO": {}, "MA": {}, "MT": {}, "MS": {}, "MG": {}, "PA": {}, "PB": {}, "PR": {}, "PE": {}, "PI": {}, "RJ": {}, "RN": {}, "RS": {}, "RO": {}, "RR": {}, "SC": {}, "SP": {}, "SE": {}, "TO": {}}
-			if _, estadual = values[strings.ToUpper(groupName)]; estadual {
-				exists = true
-			}
-		}
 		// Se o parâmetro dado não for encontrado de forma alguma, retornamos um NOT FOUND (404)
-		if !exists {
-			return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: '%s'", c.Param("grupo")))
-		}
+		return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: '%s'", c.Param("grupo")))
 	}
 
 	if estadual {
@@ -594,8 +573,10 @@ func (h handler) GetGeneralRemunerationFromYear(c echo.Context) error {
 //	@Description	Busca os dados, das remunerações de um ano inteiro, agrupados por mês.
 //	@Produce		json
 //	@Param			ano									path		string					true	"Ano da remuneração. Exemplos: 2018, 2019, 2020..."
+//	@Param			corrigir							query		string					false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base								query		string					false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
 //	@Success		200									{object}	[]mensalRemuneration	"Requisição bem sucedida."
-//	@Failure		400									{string}	string					"Parâmetro ano inválido."
+//	@Failure		400									{string}	string					"Parâmetro ano, corrigir ou base inválido."
 //	@Failure		500									{string}	string					"Erro interno."
 //	@Router			/uiapi/v2/geral/remuneracao/{ano} 	[get]
 func (h handler) V2GetGeneralRemunerationFromYear(c echo.Context) error {
@@ -603,6 +584,10 @@ func (h handler) V2GetGeneralRemunerationFromYear(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%s inválido", c.Param("ano")))
 	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 	data, err := h.client.Db.GetGeneralMonthlyInfosFromYear(year)
 	if err != nil {
 		fmt.Println("Error searching for monthly info from year: %w", err)
@@ -610,23 +595,16 @@ func (h handler) V2GetGeneralRemunerationFromYear(c echo.Context) error {
 	}
 	annualRemu := []mensalRemuneration{}
 	for _, d := range data {
+		f := deflator.Factor(year, d.Month)
 		annualRemu = append(annualRemu, mensalRemuneration{
 			Month:              d.Month,
 			Members:            d.Count,
-			BaseRemuneration:   d.BaseRemuneration,
-			OtherRemunerations: d.OtherRemunerations,
-			Discounts:          d.Discounts,
-			Remunerations:      d.Remunerations,
-			ItemSummary: itemSummary{
-				FoodAllowance:        d.ItemSummary.FoodAllowance,
-				BonusLicense:         d.ItemSummary.BonusLicense,
-				VacationCompensation: d.ItemSummary.VacationCompensation,
-				Vacation:             d.ItemSummary.Vacation,
-				ChristmasBonus:       d.ItemSummary.ChristmasBonus,
-				CompensatoryLicense:  d.ItemSummary.CompensatoryLicense,
-				HealthAllowance:      d.ItemSummary.HealthAllowance,
-				Others:               d.ItemSummary.Others,
-			},
+			BaseRemuneration:   d.BaseRemuneration * f,
+			OtherRemunerations: d.OtherRemunerations * f,
+			Discounts:          d.Discounts * f,
+			Remunerations:      d.Remunerations * f,
+			ItemSummary:        newItemSummary(tables.ScaleItemSummary(d.ItemSummary, f)),
+			Correction:         deflator.Correction(),
 		})
 	}
 	return c.JSON(http.StatusOK, annualRemu)
@@ -719,6 +697,7 @@ func (h handler) GetGeneralSummary(c echo.Context) error {
 //	@Param			meses		query		string			false	"Meses a serem pesquisados, separados por virgula. Exemplo: 1,2,3"
 //	@Param			orgaos		query		string			false	"Orgãos a serem pesquisados, separados por virgula. Exemplo: tjal,mpal,mppb"
 //	@Param			categorias	query		string			false	"Categorias a serem pesquisadas"	Enums(base,outras,descontos)
+//	@Param			formato		query		string			false	"Formato da resposta. Com ndjson, cada remuneração é enviada em uma linha à medida que os dados são lidos; se a leitura falhar no meio, a última linha é um objeto {\"erro\": \"...\"}"	Enums(json,ndjson)
 //	@Success		200			{object}	searchResponse	"Requisição bem sucedida."
 //	@Failure		400			{string}	string			"Erro de validação dos parâmetros."
 //	@Failure		500			{string}	string			"Erro interno do servidor."
@@ -730,6 +709,10 @@ func (h handler) SearchByUrl(c echo.Context) error {
 	agencies := c.QueryParam("orgaos")
 	categories := c.QueryParam("categorias")
 	types := c.QueryParam("tipos")
+	format := c.QueryParam("formato")
+	if format != "" && format != "json" && format != "ndjson" {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro formato=%s inválido", format))
+	}
 	//Criando os filtros a partir dos query params e validando eles
 	searchParams, err := newSearchParams(years, months, agencies, categories, types)
 	if err != nil {
@@ -745,6 +728,9 @@ func (h handler) SearchByUrl(c echo.Context) error {
 		log.Printf("Error querying BD (searchParams or counter):%q", err)
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
+	if format == "ndjson" {
+		return h.streamNDJSON(c, "dadosjusbr-pesquisa.ndjson", h.searchLimit, category, results)
+	}
 	remunerations, numRows, err := h.getSearchResults(h.searchLimit, category, results)
 	if err != nil {
 		log.Printf("Error getting search results: %q", err)
@@ -769,8 +755,9 @@ func (h handler) SearchByUrl(c echo.Context) error {
 //	@Param			meses		query		string	false	"Meses a serem pesquisados, separados por virgula. Exemplo: 1,2,3"
 //	@Param			orgaos		query		string	false	"Orgãos a serem pesquisados, separados por virgula. Exemplo: tjal,mpal,mppb"
 //	@Param			categorias	query		string	false	"Categorias a serem pesquisadas"	Enums(base,outras,descontos)
-//	@Success		200			{file}		file	"Arquivo CSV com os dados."
-//	@Failure		400			{string}	string	"Erro de validação dos parâmetros."
+//	@Param			formato		query		string	false	"Formato do arquivo. O padrão é csv; datapackage retorna um zip no formato Frictionless Data Package e ndjson retorna uma remuneração por linha (se a leitura falhar no meio, a última linha é um objeto {\"erro\": \"...\"})"	Enums(csv,datapackage,ndjson)
+//	@Success		200			{file}		file	"Arquivo CSV, zip ou NDJSON com os dados."
+//	@Failure		400			{string}	string	"Erro de validação dos parâmetros ou nenhum filtro informado."
 //	@Failure		500			{string}	string	"Erro interno do servidor."
 //	@Router			/uiapi/v2/download [get]
 func (h handler) DownloadByUrl(c echo.Context) error {
@@ -780,22 +767,43 @@ func (h handler) DownloadByUrl(c echo.Context) error {
 	agencies := c.QueryParam("orgaos")
 	categories := c.QueryParam("categorias")
 	types := c.QueryParam("tipos")
+	format := c.QueryParam("formato")
+	if format != "" && format != "csv" && format != "datapackage" && format != "ndjson" {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro formato=%s inválido", format))
+	}
 
 	//Criando os filtros a partir dos query params e validando eles
 	searchParams, err := newSearchParams(years, months, agencies, categories, types)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
+	// Sem filtros, o download leria os zips de todos os órgãos e meses.
+	if searchParams == nil {
+		return c.JSON(http.StatusBadRequest, "Informe ao menos um filtro: anos, meses, orgaos, categorias ou tipos")
+	}
 
 	results, err := h.db.filter(h.db.remunerationQuery(searchParams), h.db.arguments(searchParams))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
+	if format == "ndjson" {
+		return h.streamNDJSON(c, "dadosjusbr-remuneracoes.ndjson", h.downloadLimit, searchParams.Category, results)
+	}
 	searchResults, _, err := h.getSearchResults(h.downloadLimit, searchParams.Category, results)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 
+	if format == "datapackage" {
+		pkg := newDataPackage(h.dataPackageSources(searchResults, results), time.Now().In(h.loc))
+		c.Response().Header().Set("Content-Disposition", "attachment; filename=dadosjusbr-remuneracoes.zip")
+		c.Response().Header().Set("Content-Type", "application/zip")
+		if err := writeDataPackage(c.Response().Writer, pkg, searchResults); err != nil {
+			return c.JSON(http.StatusInternalServerError, fmt.Errorf("erro tentando fazer download do datapackage: %q", err))
+		}
+		return nil
+	}
+
 	c.Response().Header().Set("Content-Disposition", "attachment; filename=dadosjusbr-remuneracoes.csv")
 	c.Response().Header().Set("Content-Type", c.Response().Header().Get("Content-Type"))
 	err = gocsv.Marshal(searchResults, c.Response().Writer)
@@ -809,13 +817,20 @@ func (h handler) DownloadByUrl(c echo.Context) error {
 //	@Tags			ui_api
 //	@Description	Retorna os dados anuais de um orgão
 //	@Produce		json
-//	@Param			orgao	path		string			true	"Nome do orgão"
-//	@Success		200		{object}	[]annualSummary	"Requisição bem sucedida."
-//	@Failure		400		{string}	string			"Parâmetro orgao inválido"
-//	@Failure		500		{string}	string			"Algo deu errado ao tentar coletar os dados anuais do orgao"
+//	@Param			orgao		path		string			true	"Nome do orgão"
+//	@Param			corrigir	query		string			false	"Índice para correção monetária dos valores. Valor aceito: ipca. Os valores de cada ano são corrigidos pela média dos fatores dos meses com dados."
+//	@Param			base		query		string			false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Param			unidade		query		string			false	"Unidade dos valores: reais (padrão) ou salario_minimo. Os valores de cada ano são divididos pela média do salário mínimo em vigor nos meses com dados. Não pode ser combinada com corrigir, pois os múltiplos do salário mínimo já descontam a variação de preços. A unidade só está disponível aqui e nos totais do órgão no ano."
+//	@Success		200			{object}	[]annualSummary	"Requisição bem sucedida."
+//	@Failure		400			{string}	string			"Parâmetro orgao, corrigir, base ou unidade inválido"
+//	@Failure		500			{string}	string			"Algo deu errado ao tentar coletar os dados anuais do orgao"
 //	@Router			/uiapi/v1/orgao/resumo/{orgao} [get]
 func (h handler) GetAnnualSummary(c echo.Context) error {
 	agencyName := c.Param("orgao")
+	conv, err := conversionFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 	strAgency, err := h.client.Db.GetAgency(agencyName)
 	if err != nil {
 		log.Printf("error getting agency '%s' :%q", agencyName, err)
@@ -828,39 +843,46 @@ func (h handler) GetAnnualSummary(c echo.Context) error {
 		log.Printf("error getting annual data of '%s' :%q", agencyName, err)
 		return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Algo deu errado ao tentar coletar os dados anuais do orgao=%s", agencyName))
 	}
+	// Os valores anuais são convertidos pela média dos fatores dos meses com
+	// dados, que são os meses que compõem os totais do ano.
+	monthsWithData := map[int][]int{}
+	if !conv.isIdentity() {
+		collections, err := h.client.Db.GetAllAgencyCollection(agencyName)
+		if err != nil {
+			log.Printf("error getting collections of '%s' :%q", agencyName, err)
+			return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Algo deu errado ao tentar coletar os dados anuais do orgao=%s", agencyName))
+		}
+		for _, mi := range collections {
+			if mi.Summary != nil && (mi.ProcInfo == nil || mi.ProcInfo.String() == "") {
+				monthsWithData[mi.Year] = append(monthsWithData[mi.Year], mi.Month)
+			}
+		}
+	}
 	var annualData []annualSummaryData
 	for _, s := range summaries {
-		baseRemPerMonth := s.BaseRemuneration / float64(s.NumMonthsWithData)
-		baseRemPerCapita := s.BaseRemunerationPerCapita
-		otherRemPerMonth := s.OtherRemunerations / float64(s.NumMonthsWithData)
-		otherRemPerCapita := s.OtherRemunerationsPerCapita
-		remPerMonth := s.Remunerations / float64(s.NumMonthsWithData)
-		remPerCapita := s.RemunerationsPerCapita
-		discountsRemPerMonth := s.Discounts / float64(s.NumMonthsWithData)
-		discountsRemPerCapita := s.DiscountsPerCapita
-		itemSummary := itemSummary{
-			FoodAllowance:        s.ItemSummary.FoodAllowance,
-			BonusLicense:         s.ItemSummary.BonusLicense,
-			VacationCompensation: s.ItemSummary.VacationCompensation,
-			Vacation:             s.ItemSummary.Vacation,
-			ChristmasBonus:       s.ItemSummary.ChristmasBonus,
-			CompensatoryLicense:  s.ItemSummary.CompensatoryLicense,
-			HealthAllowance:      s.ItemSummary.HealthAllowance,
-			Others:               s.ItemSummary.Others,
-		}
+		f := conv.yearFactor(s.Year, monthsWithData[s.Year])
+		baseRemPerMonth := s.BaseRemuneration * f / float64(s.NumMonthsWithData)
+		baseRemPerCapita := s.BaseRemunerationPerCapita * f
+		otherRemPerMonth := s.OtherRemunerations * f / float64(s.NumMonthsWithData)
+		otherRemPerCapita := s.OtherRemunerationsPerCapita * f
+		remPerMonth := s.Remunerations * f / float64(s.NumMonthsWithData)
+		remPerCapita := s.RemunerationsPerCapita * f
+		discountsRemPerMonth := s.Discounts * f / float64(s.NumMonthsWithData)
+		discountsRemPerCapita := s.DiscountsPerCapita * f
+		itemSummary := newItemSummary(tables.ScaleItemSummary(s.ItemSummary, f))
 		annualData = append(annualData, annualSummaryData{
 			Year:                        s.Year,
 			AverageMemberCount:          s.AverageCount,
-			BaseRemuneration:            s.BaseRemuneration,
+			BaseRemuneration:            s.BaseRemuneration * f,
 			BaseRemunerationPerMonth:    baseRemPerMonth,
 			BaseRemunerationPerCapita:   baseRemPerCapita,
-			OtherRemunerations:          s.OtherRemunerations,
+			OtherRemunerations:          s.OtherRemunerations * f,
 			OtherRemunerationsPerMonth:  otherRemPerMonth,
 			OtherRemunerationsPerCapita: otherRemPerCapita,
-			Discounts:                   s.Discounts,
+			Discounts:                   s.Discounts * f,
 			DiscountsPerMonth:           discountsRemPerMonth,
 			DiscountsPerCapita:          discountsRemPerCapita,
-			Remunerations:               s.Remunerations,
+			Remunerations:               s.Remunerations * f,
 			RemunerationsPerMonth:       remPerMonth,
 			RemunerationsPerCapita:      remPerCapita,
 			NumMonthsWithData:           s.NumMonthsWithData,
@@ -894,23 +916,498 @@ func (h handler) GetAnnualSummary(c echo.Context) error {
 			OmbudsmanURL:  strAgency.OmbudsmanURL,
 			HasData:       hasData,
 		},
-		Data: annualData,
+		Data:       annualData,
+		Correction: conv.deflator.Correction(),
+		Unit:       newUnit(conv.unit),
 	}
 	return c.JSON(http.StatusOK, annualSum)
 }
 
+//	@ID				GetTopEarners
+//	@Tags			ui_api
+//	@Description	Busca os membros com as maiores remunerações brutas (base + outras) de um órgão em um mês.
+//	@Produce		json
+//	@Param			orgao										path		string		true	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Param			ano											path		int			true	"Ano da remuneração. Exemplo: 2018."
+//	@Param			mes											path		int			true	"Mês da remuneração. Exemplo: 1."
+//	@Param			n											query		int			false	"Número de membros. O padrão é 50 e o máximo é 500."
+//	@Param			corrigir									query		string		false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base										query		string		false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200											{object}	topEarners	"Requisição bem sucedida."
+//	@Failure		400											{string}	string		"Parâmetros inválidos."
+//	@Failure		404											{string}	string		"Não existem dados para os parâmetros informados."
+//	@Failure		500											{string}	string		"Erro interno do servidor."
+//	@Router			/uiapi/v2/orgao/maiores/{orgao}/{ano}/{mes} [get]
+func (h handler) V2GetTopEarners(c echo.Context) error {
+	year, err := strconv.Atoi(c.Param("ano"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%s inválido", c.Param("ano")))
+	}
+	month, err := strconv.Atoi(c.Param("mes"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro mês=%s inválido", c.Param("mes")))
+	}
+	n := defaultTopEarners
+	if c.QueryParam("n") != "" {
+		n, err = strconv.Atoi(c.QueryParam("n"))
+		if err != nil || n <= 0 || n > maxTopEarners {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro n=%s inválido", c.QueryParam("n")))
+		}
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	agencyName := strings.ToLower(c.Param("orgao"))
+	searchParams := &searchParams{
+		Years:    []string{strconv.Itoa(year)},
+		Months:   []string{strconv.Itoa(month)},
+		Agencies: []string{agencyName},
+	}
+	results, err := h.db.filter(h.db.remunerationQuery(searchParams), h.db.arguments(searchParams))
+	if err != nil {
+		log.Printf("[top earners] error querying remuneration zips (orgao:%s ano:%d mes:%d): %q", agencyName, year, month, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando as remunerações do órgão")
+	}
+	if len(results) == 0 {
+		return c.JSON(http.StatusNotFound, "Não existem dados para os parâmetros informados")
+	}
+	members, err := h.memberRemunerations(results)
+	if err != nil {
+		log.Printf("[top earners] error reading remunerations (orgao:%s ano:%d mes:%d): %q", agencyName, year, month, err)
+		return c.JSON(http.StatusInternalServerError, "Erro lendo as remunerações do órgão")
+	}
+	earners := []earner{}
+	for _, m := range topMembers(members, n) {
+		earners = append(earners, newEarner(m, deflator.Factor(year, month)))
+	}
+	return c.JSON(http.StatusOK, topEarners{
+		Agency:     agencyName,
+		Year:       year,
+		Month:      month,
+		Members:    earners,
+		Correction: deflator.Correction(),
+	})
+}
+
+const (
+	defaultTopEarners = 50
+	maxTopEarners     = 500
+)
+
+// newEarner monta a resposta de um membro, multiplicando os valores pelo
+// fator de correção monetária f.
+func newEarner(m *memberRemuneration, f float64) earner {
+	items := []earnerItem{}
+	for _, i := range m.Items {
+		items = append(items, earnerItem{Category: i.Category, Item: i.Item, Value: i.Value * f})
+	}
+	return earner{
+		Name:               m.Name,
+		Enrollment:         m.Enrollment,
+		Role:               m.Role,
+		Workplace:          m.Workplace,
+		BaseRemuneration:   m.BaseRemuneration * f,
+		OtherRemunerations: m.OtherRemunerations * f,
+		Remunerations:      m.Remunerations() * f,
+		Discounts:          m.Discounts * f,
+		NetRemuneration:    m.NetRemuneration() * f,
+		Items:              items,
+	}
+}
+
+//	@ID				GetItemCategories
+//	@Tags			ui_api
+//	@Description	Soma as rubricas (detalhamento_contracheque) de um órgão em um mês pelas categorias do dicionário de classificação, disponível em /v2/rubricas/dicionario.
+//	@Produce		json
+//	@Param			orgao											path		string			true	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Param			ano												path		int				true	"Ano da remuneração. Exemplo: 2018."
+//	@Param			mes												path		int				true	"Mês da remuneração. Exemplo: 1."
+//	@Param			corrigir										query		string			false	"Índice para correção monetária dos valores. Valor aceito: ipca."
+//	@Param			base											query		string			false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200												{object}	itemCategories	"Requisição bem sucedida."
+//	@Failure		400												{string}	string			"Parâmetros inválidos."
+//	@Failure		404												{string}	string			"Não existem dados para os parâmetros informados."
+//	@Failure		500												{string}	string			"Erro interno do servidor."
+//	@Router			/uiapi/v2/rubricas/categorias/{orgao}/{ano}/{mes} [get]
+func (h handler) V2GetItemCategories(c echo.Context) error {
+	year, err := strconv.Atoi(c.Param("ano"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro ano=%s inválido", c.Param("ano")))
+	}
+	month, err := strconv.Atoi(c.Param("mes"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro mês=%s inválido", c.Param("mes")))
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	agencyName := strings.ToLower(c.Param("orgao"))
+	searchParams := &searchParams{
+		Years:    []string{strconv.Itoa(year)},
+		Months:   []string{strconv.Itoa(month)},
+		Agencies: []string{agencyName},
+	}
+	results, err := h.db.filter(h.db.remunerationQuery(searchParams), h.db.arguments(searchParams))
+	if err != nil {
+		log.Printf("[item categories] error querying remuneration zips (orgao:%s ano:%d mes:%d): %q", agencyName, year, month, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando as remunerações do órgão")
+	}
+	if len(results) == 0 {
+		return c.JSON(http.StatusNotFound, "Não existem dados para os parâmetros informados")
+	}
+	dict := classification.Default()
+	agg, err := h.classifyRemunerations(dict, results)
+	if err != nil {
+		log.Printf("[item categories] error reading remunerations (orgao:%s ano:%d mes:%d): %q", agencyName, year, month, err)
+		return c.JSON(http.StatusInternalServerError, "Erro lendo as remunerações do órgão")
+	}
+	return c.JSON(http.StatusOK, itemCategories{
+		Agency:            agencyName,
+		Year:              year,
+		Month:             month,
+		DictionaryVersion: dict.Version,
+		Categories:        agg.result(deflator.Factor(year, month)),
+		Correction:        deflator.Correction(),
+	})
+}
+
+//	@ID				GetItemCatalog
+//	@Tags			public_api
+//	@Description	Lista as rubricas (detalhamento_contracheque) encontradas nos zips de remunerações, com a categoria do dicionário de classificação, a quantidade de ocorrências, o valor total, o primeiro e o último mês em que aparecem e os órgãos que as usam. O catálogo é guardado no banco e atualizado em segundo plano por uma única instância da API, que só lê os zips de órgãos/meses novos ou alterados. Pode estar incompleto enquanto indexando for verdadeiro.
+//	@Produce		json
+//	@Param			categoria				query		string				false	"Categoria do dicionário de classificação. Exemplo: auxilio_saude."
+//	@Param			orgao					query		string				false	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Param			desde					query		string				false	"Apenas rubricas vistas pela primeira vez a partir deste mês, no formato AAAA-MM. Útil para encontrar rubricas novas."
+//	@Success		200						{object}	itemCatalogResult	"Requisição bem sucedida."
+//	@Failure		400						{string}	string				"Parâmetros inválidos."
+//	@Failure		500						{string}	string				"Erro interno do servidor."
+//	@Router			/v2/rubricas/catalogo	[get]
+func (h handler) V2GetItemCatalog(c echo.Context) error {
+	filter := catalogFilter{
+		category: c.QueryParam("categoria"),
+		agency:   strings.ToLower(c.QueryParam("orgao")),
+	}
+	if since := c.QueryParam("desde"); since != "" {
+		t, err := time.Parse("2006-01", since)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("parâmetro desde '%s' é inválido! Use o formato AAAA-MM", since))
+		}
+		filter.since = &period.YearMonth{Year: t.Year(), Month: int(t.Month())}
+	}
+	dict := classification.Default()
+	indexed, updated, indexing, err := h.catalog.status()
+	if err != nil {
+		log.Printf("[item catalog] error getting catalog status: %q", err)
+		return c.JSON(http.StatusInternalServerError, "Erro consultando o catálogo de rubricas")
+	}
+	items, err := h.catalog.items(dict, filter)
+	if err != nil {
+		log.Printf("[item catalog] error getting catalog items: %q", err)
+		return c.JSON(http.StatusInternalServerError, "Erro consultando o catálogo de rubricas")
+	}
+	result := itemCatalogResult{
+		DictionaryVersion: dict.Version,
+		IndexedMonths:     indexed,
+		Indexing:          indexing,
+		Items:             items,
+	}
+	if !updated.IsZero() {
+		updated = updated.In(h.loc)
+		result.UpdatedAt = &updated
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+//	@ID				GetIncomeHistogram
+//	@Tags			ui_api
+//	@Description	Histograma das remunerações dos membros de um órgão em um período, calculado a partir dos zips de remunerações. Cada par membro/mês é uma observação. Informando os limites das faixas, é possível comparar órgãos e períodos diferentes na mesma escala.
+//	@Produce		json
+//	@Param			orgao							path		string			true	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Param			inicio							query		string			true	"Mês inicial, no formato AAAA-MM. Exemplo: 2023-01."
+//	@Param			fim								query		string			true	"Mês final, no formato AAAA-MM. Exemplo: 2023-12."
+//	@Param			bins							query		string			false	"Quantidade de faixas de mesma largura entre o menor e o maior valor (padrão: 10, máximo: 100) ou os limites das faixas, crescentes e separados por vírgula. Exemplo: 0,10000,20000,40000,80000."
+//	@Param			metrica							query		string			false	"Valor usado no histograma: bruto (padrão), liquido ou base."
+//	@Param			corrigir						query		string			false	"Índice para correção monetária dos valores antes da distribuição nas faixas. Valor aceito: ipca."
+//	@Param			base							query		string			false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200								{object}	incomeHistogram	"Requisição bem sucedida."
+//	@Failure		400								{string}	string			"Parâmetros inválidos."
+//	@Failure		404								{string}	string			"Não existem dados para os parâmetros informados."
+//	@Failure		500								{string}	string			"Erro interno do servidor."
+//	@Router			/uiapi/v2/orgao/histograma/{orgao} [get]
+func (h handler) V2GetIncomeHistogram(c echo.Context) error {
+	agencyName := strings.ToLower(c.Param("orgao"))
+	p, err := period.New(c.QueryParam("inicio"), c.QueryParam("fim"), maxReportMonths)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	spec, err := parseBins(c.QueryParam("bins"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	metric := c.QueryParam("metrica")
+	if metric == "" {
+		metric = "bruto"
+	}
+	value, ok := histogramMetrics[metric]
+	if !ok {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("parâmetro metrica '%s' é inválido! Valores aceitos: bruto, liquido, base", metric))
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	results, err := h.periodRemunerationZips(p, []string{agencyName})
+	if err != nil {
+		log.Printf("[income histogram] error querying remuneration zips (orgao:%s, %s): %q", agencyName, p, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando as remunerações do órgão")
+	}
+	if len(results) == 0 {
+		return c.JSON(http.StatusNotFound, "Não existem dados para os parâmetros informados")
+	}
+	members, err := h.memberRemunerations(results)
+	if err != nil {
+		log.Printf("[income histogram] error reading remunerations (orgao:%s, %s): %q", agencyName, p, err)
+		return c.JSON(http.StatusInternalServerError, "Erro lendo as remunerações do órgão")
+	}
+	values := make([]float64, 0, len(members))
+	for _, m := range members {
+		values = append(values, value(m)*deflator.Factor(m.Year, m.Month))
+	}
+	bins, below, above := newHistogram(values, spec)
+	return c.JSON(http.StatusOK, incomeHistogram{
+		Agency:       agencyName,
+		Start:        p.Start.Format("2006-01"),
+		End:          p.End.Format("2006-01"),
+		Metric:       metric,
+		Observations: len(values),
+		Bins:         bins,
+		Below:        below,
+		Above:        above,
+		Correction:   deflator.Correction(),
+	})
+}
+
+//	@ID				GetExceptionalPayments
+//	@Tags			ui_api
+//	@Description	Lista rubricas de outras remunerações muito acima do histórico do próprio membro, como pagamentos retroativos, indenizações de férias acumuladas e licenças-prêmio. Cada rubrica recebida em um mês do período é comparada com os demais meses do membro nos zips de remunerações: a razão é o valor da rubrica acima da sua mediana dividido pela mediana da remuneração bruta do membro. Membros com menos de três outros meses não são avaliados.
+//	@Produce		json
+//	@Param			orgao										path		string				true	"ID do órgão. Exemplos: tjal, tjba, mppb."
+//	@Param			inicio										query		string				true	"Mês inicial, no formato AAAA-MM. Exemplo: 2023-01. O período tem no máximo 12 meses."
+//	@Param			fim											query		string				true	"Mês final, no formato AAAA-MM. Exemplo: 2023-12."
+//	@Param			historico									query		int					false	"Meses anteriores ao início incluídos no histórico dos membros, entre 0 e 12. Padrão: 12."
+//	@Param			razao										query		number				false	"Razão mínima para a rubrica ser listada. Padrão: 1 (uma remuneração bruta mediana do membro)."
+//	@Param			corrigir									query		string				false	"Índice para correção monetária dos valores antes da comparação. Valor aceito: ipca."
+//	@Param			base										query		string				false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200											{object}	exceptionalPayments	"Requisição bem sucedida."
+//	@Failure		400											{string}	string				"Parâmetros inválidos."
+//	@Failure		404											{string}	string				"Não existem dados para os parâmetros informados."
+//	@Failure		500											{string}	string				"Erro interno do servidor."
+//	@Router			/uiapi/v2/orgao/pagamentos-excepcionais/{orgao} [get]
+func (h handler) V2GetExceptionalPayments(c echo.Context) error {
+	agencyName := strings.ToLower(c.Param("orgao"))
+	p, err := period.New(c.QueryParam("inicio"), c.QueryParam("fim"), maxExceptionalMonths)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	history := defaultExceptionalHistory
+	if qp := c.QueryParam("historico"); qp != "" {
+		history, err = strconv.Atoi(qp)
+		if err != nil || history < 0 || history > maxExceptionalHistory {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro historico=%s inválido", qp))
+		}
+	}
+	minRatio := defaultExceptionalRatio
+	if qp := c.QueryParam("razao"); qp != "" {
+		minRatio, err = strconv.ParseFloat(qp, 64)
+		if err != nil || minRatio <= 0 {
+			return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro razao=%s inválido", qp))
+		}
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	withHistory := &period.Period{Start: p.Start.AddDate(0, -history, 0), End: p.End}
+	results, err := h.periodRemunerationZips(withHistory, []string{agencyName})
+	if err != nil {
+		log.Printf("[exceptional payments] error querying remuneration zips (orgao:%s, %s): %q", agencyName, withHistory, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando as remunerações do órgão")
+	}
+	if len(results) == 0 {
+		return c.JSON(http.StatusNotFound, "Não existem dados para os parâmetros informados")
+	}
+	members, err := h.memberRemunerations(results)
+	if err != nil {
+		log.Printf("[exceptional payments] error reading remunerations (orgao:%s, %s): %q", agencyName, withHistory, err)
+		return c.JSON(http.StatusInternalServerError, "Erro lendo as remunerações do órgão")
+	}
+	dict := classification.Default()
+	return c.JSON(http.StatusOK, exceptionalPayments{
+		Agency:            agencyName,
+		Start:             p.Start.Format("2006-01"),
+		End:               p.End.Format("2006-01"),
+		HistoryMonths:     history,
+		MinRatio:          minRatio,
+		DictionaryVersion: dict.Version,
+		Payments:          findExceptionalPayments(members, p, minRatio, dict, deflator),
+		Correction:        deflator.Correction(),
+	})
+}
+
+//	@ID				GetCeilingReport
+//	@Tags			ui_api
+//	@Description	Relatório de membros que receberam remuneração mensal acima do teto constitucional, com o valor pago acima do teto e as rubricas recebidas por esses membros. A remuneração comparada com o teto exclui as rubricas que o teto não alcança, segundo o dicionário de classificação (/v2/rubricas/dicionario): verbas indenizatórias, gratificação natalina, adicional de férias e retroativos (categorias_fora_do_teto). Rubricas que o dicionário não reconhece são consideradas sujeitas ao teto.
+//	@Produce		json
+//	@Param			param						path		string			true	"'grupo' ou 'orgao'"
+//	@Param			valor						path		string			true	"Jurisdição ou ID do órgão. Exemplos: justica-estadual, tjal."
+//	@Param			inicio						query		string			true	"Mês inicial, no formato AAAA-MM. Exemplo: 2023-01."
+//	@Param			fim							query		string			true	"Mês final, no formato AAAA-MM. Exemplo: 2023-12."
+//	@Param			corrigir					query		string			false	"Índice para correção monetária dos valores. Valor aceito: ipca. A comparação com o teto é sempre feita com os valores nominais."
+//	@Param			base						query		string			false	"Mês base da correção, no formato AAAA-MM. O padrão é o último mês da tabela do índice."
+//	@Success		200							{object}	ceilingReport	"Requisição bem sucedida."
+//	@Failure		400							{string}	string			"Parâmetros inválidos."
+//	@Failure		404							{string}	string			"Grupo não encontrado, grupo sem órgãos ou não existem dados para os parâmetros informados."
+//	@Failure		500							{string}	string			"Erro interno do servidor."
+//	@Router			/uiapi/v2/teto/{param}/{valor} [get]
+func (h handler) V2GetCeilingReport(c echo.Context) error {
+	param := c.Param("param")
+	value := strings.ToLower(c.Param("valor"))
+	p, err := period.New(c.QueryParam("inicio"), c.QueryParam("fim"), maxReportMonths)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	deflator, err := tables.DeflatorFromQuery(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	var agencies []string
+	switch param {
+	case "orgao":
+		agencies = []string{value}
+	case "grupo":
+		g, ok := taxonomy.GroupBySlug(value)
+		if !ok {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Grupo não encontrado: '%s'", c.Param("valor")))
+		}
+		strAgencies, err := h.client.Db.GetOPJ(g.Jurisdiction)
+		if err != nil {
+			log.Printf("[ceiling report] error getting agencies of group '%s': %q", g.Jurisdiction, err)
+			return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Erro buscando os órgãos do grupo %s", value))
+		}
+		for _, a := range strAgencies {
+			agencies = append(agencies, a.ID)
+		}
+		if len(agencies) == 0 {
+			return c.JSON(http.StatusNotFound, fmt.Sprintf("Nenhum órgão encontrado no grupo '%s'", c.Param("valor")))
+		}
+	default:
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Parâmetro inválido: %s.", param))
+	}
+
+	results, err := h.periodRemunerationZips(p, agencies)
+	if err != nil {
+		log.Printf("[ceiling report] error querying remuneration zips (%s=%s, %s): %q", param, value, p, err)
+		return c.JSON(http.StatusInternalServerError, "Erro buscando as remunerações")
+	}
+	if len(results) == 0 {
+		return c.JSON(http.StatusNotFound, "Não existem dados para os parâmetros informados")
+	}
+	members, err := h.memberRemunerations(results)
+	if err != nil {
+		log.Printf("[ceiling report] error reading remunerations (%s=%s, %s): %q", param, value, p, err)
+		return c.JSON(http.StatusInternalServerError, "Erro lendo as remunerações")
+	}
+	report := newCeilingReport(members, tables.Ceiling(), classification.Default(), deflator)
+	report.Agencies = agencies
+	report.Start = p.Start.Format("2006-01")
+	report.End = p.End.Format("2006-01")
+
+	// O resumo das rubricas de cada mês complementa o relatório, então um erro aqui não impede a resposta.
+	summaries, err := h.itemSummaries(p, agencies)
+	if err != nil {
+		log.Printf("[ceiling report] error getting item summaries (%s=%s, %s): %q", param, value, p, err)
+	}
+	for i, m := range report.Months {
+		if s, ok := summaries[agencyMonthKey{m.Agency, m.Year, m.Month}]; ok {
+			s := newItemSummary(tables.ScaleItemSummary(s, deflator.Factor(m.Year, m.Month)))
+			report.Months[i].ItemSummary = &s
+		}
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// Número máximo de meses dos relatórios calculados a partir dos zips de remunerações.
+const maxReportMonths = 24
+
+// periodRemunerationZips busca os zips de remunerações dos órgãos no período.
+// A lista de órgãos não pode ser vazia: sem o filtro de órgãos, a consulta
+// retornaria os zips de todos os órgãos.
+func (h handler) periodRemunerationZips(p *period.Period, agencies []string) ([]searchDetails, error) {
+	if len(agencies) == 0 {
+		return nil, fmt.Errorf("no agencies to search remuneration zips")
+	}
+	sp := periodSearchParams(p, agencies)
+	results, err := h.db.filter(h.db.remunerationQuery(sp), h.db.arguments(sp))
+	if err != nil {
+		return nil, err
+	}
+	var inPeriod []searchDetails
+	for _, r := range results {
+		if p.Contains(r.Ano, r.Mes) {
+			inPeriod = append(inPeriod, r)
+		}
+	}
+	return inPeriod, nil
+}
+
+// itemSummaries retorna o resumo das rubricas de cada órgão/mês do período.
+func (h handler) itemSummaries(p *period.Period, agencies []string) (map[agencyMonthKey]strModels.ItemSummary, error) {
+	var strAgencies []strModels.Agency
+	for _, a := range agencies {
+		strAgencies = append(strAgencies, strModels.Agency{ID: a})
+	}
+	summaries := map[agencyMonthKey]strModels.ItemSummary{}
+	for year := p.Start.Year(); year <= p.End.Year(); year++ {
+		monthlyInfo, err := h.client.Db.GetMonthlyInfo(strAgencies, year)
+		if err != nil {
+			return summaries, err
+		}
+		for _, mis := range monthlyInfo {
+			for _, mi := range mis {
+				if mi.Summary == nil || !p.Contains(mi.Year, mi.Month) {
+					continue
+				}
+				summaries[agencyMonthKey{mi.AgencyID, mi.Year, mi.Month}] = mi.Summary.ItemSummary
+			}
+		}
+	}
+	return summaries, nil
+}
+
+func newItemSummary(s strModels.ItemSummary) itemSummary {
+	return itemSummary{
+		FoodAllowance:        s.FoodAllowance,
+		BonusLicense:         s.BonusLicense,
+		VacationCompensation: s.VacationCompensation,
+		Vacation:             s.Vacation,
+		ChristmasBonus:       s.ChristmasBonus,
+		CompensatoryLicense:  s.CompensatoryLicense,
+		HealthAllowance:      s.HealthAllowance,
+		Others:               s.Others,
+	}
+}
+
 func (h handler) getSearchResults(limit int, category string, results []searchDetails) ([]searchResult, int, error) {
 	searchResults := []searchResult{}
 	numRows := 0
 	if len(results) == 0 {
 		return searchResults, numRows, nil
 	} else {
-		// A razão para essa ordenação é que quando o usuário escolhe diversos órgãos
-		// provavelmente ele prefere ver dados de todos eles. Dessa forma, aumentamos
-		// as chances do preview limitado retornar dados de diversos órgãos.
-		sort.SliceStable(results, func(i, j int) bool {
-			return results[i].Ano < results[j].Ano || results[i].Mes < results[j].Mes
-		})
+		sortSearchDetails(results)
 		searchResults, numRows, err := h.sess.getRemunerationsFromS3(limit, h.downloadLimit, category, h.s3Bucket, results)
 		if err != nil {
 			return nil, numRows, fmt.Errorf("failed to get remunerations from s3 %q", err)
@@ -918,3 +1415,49 @@ func (h handler) getSearchResults(limit int, category string, results []searchDe
 		return searchResults, numRows, nil
 	}
 }
+
+// A razão para essa ordenação é que quando o usuário escolhe diversos órgãos
+// provavelmente ele prefere ver dados de todos eles. Dessa forma, aumentamos
+// as chances do preview limitado retornar dados de diversos órgãos.
+func sortSearchDetails(results []searchDetails) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Ano < results[j].Ano || results[i].Mes < results[j].Mes
+	})
+}
+
+// Número de linhas enviadas entre cada flush da resposta em NDJSON.
+const ndjsonFlushInterval = 100
+
+// streamNDJSON envia as remunerações no formato NDJSON (um objeto JSON por
+// linha) à medida que os zips são lidos, permitindo que o cliente comece a
+// processar os dados antes do fim da resposta. Se a leitura falhar no meio, a
+// última linha é um objeto {"erro": "..."}.
+func (h handler) streamNDJSON(c echo.Context, filename string, limit int, category string, results []searchDetails) error {
+	sortSearchDetails(results)
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	enc := json.NewEncoder(res)
+	numRows := 0
+	err := h.sess.streamRemunerationsFromS3(limit, category, h.s3Bucket, results, func(rem searchResult) error {
+		if err := enc.Encode(rem); err != nil {
+			return err
+		}
+		numRows++
+		if numRows%ndjsonFlushInterval == 0 {
+			res.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// O status já foi enviado, então o erro vai como a última linha, para
+		// que o cliente saiba que o arquivo está incompleto.
+		log.Printf("Error streaming remunerations as ndjson: %q", err)
+		enc.Encode(ndjsonError{Error: "Erro lendo as remunerações: o arquivo está incompleto"})
+	}
+	res.Flush()
+	return nil
+}
@@ -0,0 +1,112 @@
+package uiapi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultHistogramBins = 10
+	maxHistogramBins     = 100
+)
+
+// histogramMetrics - valores aceitos pelo parâmetro metrica.
+var histogramMetrics = map[string]func(*memberRemuneration) float64{
+	"bruto":   (*memberRemuneration).Remunerations,
+	"liquido": (*memberRemuneration).NetRemuneration,
+	"base":    func(m *memberRemuneration) float64 { return m.BaseRemuneration },
+}
+
+// binSpec - faixas pedidas pelo parâmetro bins: limites explícitos ou a
+// quantidade de faixas de mesma largura entre o menor e o maior valor.
+type binSpec struct {
+	edges []float64
+	count int
+}
+
+// parseBins lê o parâmetro bins, que pode ser uma quantidade (ex.: 20) ou uma
+// lista crescente de limites separados por vírgula (ex.: 0,10000,20000,50000).
+func parseBins(qp string) (binSpec, error) {
+	if qp == "" {
+		return binSpec{count: defaultHistogramBins}, nil
+	}
+	if !strings.Contains(qp, ",") {
+		n, err := strconv.Atoi(qp)
+		if err != nil || n <= 0 || n > maxHistogramBins {
+			return binSpec{}, fmt.Errorf("parâmetro bins '%s' é inválido! Informe uma quantidade entre 1 e %d ou os limites das faixas separados por vírgula", qp, maxHistogramBins)
+		}
+		return binSpec{count: n}, nil
+	}
+	var edges []float64
+	for _, s := range strings.Split(qp, ",") {
+		e, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || math.IsNaN(e) || math.IsInf(e, 0) {
+			return binSpec{}, fmt.Errorf("limite '%s' do parâmetro bins é inválido", s)
+		}
+		if len(edges) > 0 && e <= edges[len(edges)-1] {
+			return binSpec{}, fmt.Errorf("os limites do parâmetro bins devem ser crescentes")
+		}
+		edges = append(edges, e)
+	}
+	if len(edges)-1 > maxHistogramBins {
+		return binSpec{}, fmt.Errorf("o parâmetro bins tem mais que %d faixas", maxHistogramBins)
+	}
+	return binSpec{edges: edges}, nil
+}
+
+// newHistogram distribui os valores nas faixas. Com uma quantidade de faixas,
+// os limites vão do menor ao maior valor, então abaixo e acima são sempre zero.
+func newHistogram(values []float64, spec binSpec) (bins []histogramBin, below, above int) {
+	edges := spec.edges
+	if edges == nil {
+		edges = equalWidthEdges(values, spec.count)
+	}
+	bins = make([]histogramBin, len(edges)-1)
+	for i := range bins {
+		bins[i] = histogramBin{Min: edges[i], Max: edges[i+1]}
+	}
+	last := edges[len(edges)-1]
+	for _, v := range values {
+		switch {
+		case v < edges[0]:
+			below++
+		case v > last:
+			above++
+		case v == last:
+			bins[len(bins)-1].Count++
+		default:
+			// Primeira faixa cujo limite superior é maior que v.
+			i := sort.SearchFloat64s(edges[1:], v)
+			if i < len(edges)-1 && edges[i+1] == v {
+				i++
+			}
+			bins[i].Count++
+		}
+	}
+	return bins, below, above
+}
+
+func equalWidthEdges(values []float64, count int) []float64 {
+	if len(values) == 0 {
+		return []float64{0, 0}
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if lo == hi {
+		// Todos os valores são iguais: uma única faixa.
+		return []float64{lo, hi}
+	}
+	width := (hi - lo) / float64(count)
+	edges := make([]float64, count+1)
+	for i := range edges {
+		edges[i] = lo + float64(i)*width
+	}
+	edges[count] = hi
+	return edges
+}
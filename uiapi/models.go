@@ -3,6 +3,7 @@ package uiapi
 import (
 	"time"
 
+	"github.com/dadosjusbr/api/tables"
 	"github.com/dadosjusbr/proto/coleta"
 	"github.com/dadosjusbr/storage/models"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -124,11 +125,13 @@ type agencyTotalsYear struct {
 }
 
 type v2AgencyTotalsYear struct {
-	Year             int             `json:"ano,omitempty"`
-	Agency           *agency         `json:"orgao,omitempty"`
-	AveragePerCapita *perCapitaData  `json:"media_por_membro,omitempty"`
-	MonthTotals      []v2MonthTotals `json:"meses,omitempty"`
-	SummaryPackage   *backup         `json:"package,omitempty"`
+	Year             int                `json:"ano,omitempty"`
+	Agency           *agency            `json:"orgao,omitempty"`
+	AveragePerCapita *perCapitaData     `json:"media_por_membro,omitempty"`
+	MonthTotals      []v2MonthTotals    `json:"meses,omitempty"`
+	SummaryPackage   *backup            `json:"package,omitempty"`
+	Correction       *tables.Correction `json:"correcao_monetaria,omitempty"`
+	Unit             *unit              `json:"unidade,omitempty"`
 }
 
 type backup struct {
@@ -249,8 +252,10 @@ type collecting struct {
 }
 
 type annualSummary struct {
-	Agency *agency             `json:"orgao,omitempty"`
-	Data   []annualSummaryData `json:"dados_anuais,omitempty"`
+	Agency     *agency             `json:"orgao,omitempty"`
+	Data       []annualSummaryData `json:"dados_anuais,omitempty"`
+	Correction *tables.Correction  `json:"correcao_monetaria,omitempty"`
+	Unit       *unit               `json:"unidade,omitempty"`
 }
 
 type annualSummaryData struct {
@@ -285,13 +290,14 @@ type itemSummary struct {
 }
 
 type mensalRemuneration struct {
-	Month              int         `json:"mes,omitempty"`
-	Members            int         `json:"num_membros,omitempty"`
-	BaseRemuneration   float64     `json:"remuneracao_base"`
-	OtherRemunerations float64     `json:"outras_remuneracoes"`
-	Discounts          float64     `json:"descontos"`
-	Remunerations      float64     `json:"remuneracoes"`
-	ItemSummary        itemSummary `json:"resumo_rubricas"`
+	Month              int                `json:"mes,omitempty"`
+	Members            int                `json:"num_membros,omitempty"`
+	BaseRemuneration   float64            `json:"remuneracao_base"`
+	OtherRemunerations float64            `json:"outras_remuneracoes"`
+	Discounts          float64            `json:"descontos"`
+	Remunerations      float64            `json:"remuneracoes"`
+	ItemSummary        itemSummary        `json:"resumo_rubricas"`
+	Correction         *tables.Correction `json:"correcao_monetaria,omitempty"`
 }
 
 type perCapitaData struct {
@@ -300,3 +306,209 @@ type perCapitaData struct {
 	Discounts          float64 `json:"descontos"`
 	Remunerations      float64 `json:"remuneracoes"`
 }
+
+// dataPackage - descritor (datapackage.json) no formato Frictionless Data Package
+type dataPackage struct {
+	Profile     string              `json:"profile"`
+	Name        string              `json:"name"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Homepage    string              `json:"homepage"`
+	Created     string              `json:"created"`
+	Sources     []dataPackageSource `json:"sources,omitempty"`
+	Resources   []dataResource      `json:"resources"`
+}
+
+type dataPackageSource struct {
+	Title       string `json:"title"`
+	Path        string `json:"path,omitempty"`
+	Unavailable bool   `json:"indisponivel,omitempty"` // o arquivo não pôde ser obtido ao gerar o pacote
+}
+
+type dataResource struct {
+	Profile   string      `json:"profile"`
+	Name      string      `json:"name"`
+	Path      string      `json:"path"`
+	Format    string      `json:"format"`
+	Mediatype string      `json:"mediatype"`
+	Encoding  string      `json:"encoding"`
+	Schema    tableSchema `json:"schema"`
+}
+
+// tableSchema - esquema de uma tabela no formato Frictionless Table Schema
+type tableSchema struct {
+	Fields []tableField `json:"fields"`
+}
+
+type tableField struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Description string            `json:"description"`
+	Constraints *fieldConstraints `json:"constraints,omitempty"`
+}
+
+type fieldConstraints struct {
+	Required bool     `json:"required,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+}
+
+// topEarners - membros com as maiores remunerações de um órgão em um mês
+type topEarners struct {
+	Agency     string             `json:"orgao"`
+	Year       int                `json:"ano"`
+	Month      int                `json:"mes"`
+	Members    []earner           `json:"membros"`
+	Correction *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+type earner struct {
+	Name               string       `json:"nome"`
+	Enrollment         *string      `json:"matricula,omitempty"`
+	Role               *string      `json:"cargo,omitempty"`
+	Workplace          *string      `json:"lotacao,omitempty"`
+	BaseRemuneration   float64      `json:"remuneracao_base"`
+	OtherRemunerations float64      `json:"outras_remuneracoes"`
+	Remunerations      float64      `json:"remuneracao_bruta"`
+	Discounts          float64      `json:"descontos"`
+	NetRemuneration    float64      `json:"remuneracao_liquida"`
+	Items              []earnerItem `json:"rubricas"`
+}
+
+type earnerItem struct {
+	Category string  `json:"categoria"`
+	Item     string  `json:"detalhamento"`
+	Value    float64 `json:"valor"`
+}
+
+// ceilingReport - relatório de remunerações acima do teto constitucional
+type ceilingReport struct {
+	Agencies                []string           `json:"orgaos"`
+	Start                   string             `json:"inicio"`
+	End                     string             `json:"fim"`
+	CeilingTableVersion     string             `json:"versao_tabela_teto"`
+	DictionaryVersion       string             `json:"versao_dicionario"`       // versão do dicionário que classifica as rubricas fora do teto
+	ExcludedCategories      []string           `json:"categorias_fora_do_teto"` // categorias do dicionário que não entram na comparação
+	MembersAboveCeiling     int                `json:"membros_acima_teto"`      // pessoas distintas que receberam acima do teto em algum mês
+	OccurrencesAboveCeiling int                `json:"ocorrencias_acima_teto"`  // quantidade de pares membro/mês acima do teto
+	AmountAboveCeiling      float64            `json:"valor_acima_teto"`
+	Months                  []ceilingMonth     `json:"meses"`
+	Items                   []ceilingItem      `json:"rubricas"` // rubricas de outras remunerações sujeitas ao teto recebidas pelos membros acima do teto
+	Correction              *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+type ceilingMonth struct {
+	Agency              string       `json:"orgao"`
+	Year                int          `json:"ano"`
+	Month               int          `json:"mes"`
+	Ceiling             float64      `json:"teto"`
+	MemberCount         int          `json:"total_membros"`
+	MembersAboveCeiling int          `json:"membros_acima_teto"`
+	AmountAboveCeiling  float64      `json:"valor_acima_teto"`
+	ItemSummary         *itemSummary `json:"resumo_rubricas,omitempty"`
+}
+
+type ceilingItem struct {
+	Item     string  `json:"detalhamento"`
+	Category string  `json:"categoria"` // categoria da rubrica no dicionário de classificação
+	Members  int     `json:"ocorrencias"`
+	Value    float64 `json:"valor"`
+}
+
+// unit - unidade em que os valores da resposta estão expressos, quando diferente de reais
+type unit struct {
+	Name         string `json:"nome"`
+	TableVersion string `json:"versao_tabela"`
+}
+
+// itemCategories - totais das rubricas de um órgão/mês, por categoria do dicionário de classificação
+type itemCategories struct {
+	Agency            string             `json:"orgao"`
+	Year              int                `json:"ano"`
+	Month             int                `json:"mes"`
+	DictionaryVersion string             `json:"versao_dicionario"`
+	Categories        []itemCategory     `json:"categorias"`
+	Correction        *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+type itemCategory struct {
+	PayslipCategory string   `json:"categoria_contracheque"`
+	Category        string   `json:"categoria"`
+	Total           float64  `json:"total"`
+	Members         int      `json:"membros"`  // membros que receberam alguma rubrica da categoria
+	Items           []string `json:"rubricas"` // rubricas classificadas na categoria
+}
+
+// itemCatalogResult - catálogo das rubricas encontradas nos zips de remunerações
+type itemCatalogResult struct {
+	DictionaryVersion string        `json:"versao_dicionario"`
+	IndexedMonths     int           `json:"meses_indexados"` // quantidade de pares órgão/mês indexados
+	UpdatedAt         *time.Time    `json:"atualizado_em,omitempty"`
+	Indexing          bool          `json:"indexando"`
+	Items             []catalogItem `json:"rubricas"`
+}
+
+type catalogItem struct {
+	Item            string   `json:"detalhamento_contracheque"`
+	PayslipCategory string   `json:"categoria_contracheque"`
+	Category        string   `json:"categoria"` // categoria do dicionário de classificação
+	Occurrences     int      `json:"ocorrencias"`
+	Total           float64  `json:"total"`
+	FirstSeen       string   `json:"primeiro_mes"` // AAAA-MM
+	LastSeen        string   `json:"ultimo_mes"`   // AAAA-MM
+	Agencies        []string `json:"orgaos"`
+}
+
+// incomeHistogram - distribuição das remunerações de um órgão em um período.
+// Cada par membro/mês é uma observação.
+type incomeHistogram struct {
+	Agency       string             `json:"orgao"`
+	Start        string             `json:"inicio"`
+	End          string             `json:"fim"`
+	Metric       string             `json:"metrica"`
+	Observations int                `json:"observacoes"`
+	Bins         []histogramBin     `json:"faixas"`
+	Below        int                `json:"abaixo"` // observações menores que o limite inferior da primeira faixa
+	Above        int                `json:"acima"`  // observações maiores que o limite superior da última faixa
+	Correction   *tables.Correction `json:"correcao_monetaria,omitempty"`
+}
+
+// histogramBin - faixa do histograma. O limite inferior é incluído e o
+// superior não, exceto na última faixa, que inclui os dois.
+type histogramBin struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"quantidade"`
+}
+
+// exceptionalPayments - rubricas de outras remunerações muito acima do
+// histórico do próprio membro, como pagamentos retroativos e indenizações
+type exceptionalPayments struct {
+	Agency            string               `json:"orgao"`
+	Start             string               `json:"inicio"`
+	End               string               `json:"fim"`
+	HistoryMonths     int                  `json:"meses_historico"` // meses anteriores ao início incluídos no histórico dos membros
+	MinRatio          float64              `json:"razao_minima"`
+	DictionaryVersion string               `json:"versao_dicionario"`
+	Payments          []exceptionalPayment `json:"pagamentos"`
+	Correction        *tables.Correction   `json:"correcao_monetaria,omitempty"`
+}
+
+type exceptionalPayment struct {
+	Name               string  `json:"nome"`
+	Enrollment         *string `json:"matricula,omitempty"`
+	Role               *string `json:"cargo,omitempty"`
+	Year               int     `json:"ano"`
+	Month              int     `json:"mes"`
+	Item               string  `json:"rubrica"`
+	Category           string  `json:"categoria"` // categoria do dicionário de classificação
+	Value              float64 `json:"valor"`
+	ItemMedian         float64 `json:"mediana_rubrica"`           // mediana da rubrica nos demais meses do membro (zero nos meses sem a rubrica)
+	MedianRemuneration float64 `json:"mediana_remuneracao_bruta"` // mediana da remuneração bruta nos demais meses do membro
+	Ratio              float64 `json:"razao"`                     // (valor - mediana_rubrica) / mediana_remuneracao_bruta
+	ComparedMonths     int     `json:"meses_comparados"`
+}
+
+// ndjsonError é a última linha de um download NDJSON interrompido por erro.
+type ndjsonError struct {
+	Error string `json:"erro"`
+}
@@ -7,6 +7,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
 	_ "github.com/newrelic/go-agent/v3/integrations/nrpq"
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"gorm.io/driver/postgres"
@@ -218,3 +221,129 @@ func (p postgresDB) arguments(searchParams *searchParams) []interface{} {
 
 	return arguments
 }
+
+// Funções do catalogStore, que guardam o catálogo de rubricas nas tabelas
+// catalogo_rubricas_zips e catalogo_rubricas.
+
+func (p postgresDB) catalogFingerprints() (map[agencyMonthKey]string, error) {
+	var rows []struct {
+		IdOrgao       string
+		Mes           int
+		Ano           int
+		Identificacao string
+	}
+	if err := p.conn.Raw(`SELECT id_orgao, mes, ano, identificacao FROM catalogo_rubricas_zips`).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting indexed zips: %w", err)
+	}
+	fps := make(map[agencyMonthKey]string, len(rows))
+	for _, r := range rows {
+		fps[agencyMonthKey{r.IdOrgao, r.Ano, r.Mes}] = r.Identificacao
+	}
+	return fps, nil
+}
+
+func (p postgresDB) replaceCatalogMonth(month agencyMonthKey, fingerprint string, items map[itemKey]itemCount) error {
+	return p.conn.Transaction(func(tx *gorm.DB) error {
+		// Apagar o zip apaga as rubricas (ON DELETE CASCADE).
+		if err := tx.Exec(`DELETE FROM catalogo_rubricas_zips WHERE id_orgao = ? AND mes = ? AND ano = ?`, month.agency, month.month, month.year).Error; err != nil {
+			return fmt.Errorf("error removing indexed zip: %w", err)
+		}
+		if err := tx.Exec(`INSERT INTO catalogo_rubricas_zips (id_orgao, mes, ano, identificacao, indexado_em) VALUES (?, ?, ?, ?, NOW())`, month.agency, month.month, month.year, fingerprint).Error; err != nil {
+			return fmt.Errorf("error inserting indexed zip: %w", err)
+		}
+		for key, c := range items {
+			query := `INSERT INTO catalogo_rubricas (id_orgao, mes, ano, categoria_contracheque, detalhamento_contracheque, ocorrencias, total) VALUES (?, ?, ?, ?, ?, ?, ?)`
+			if err := tx.Exec(query, month.agency, month.month, month.year, key.category, key.item, c.occurrences, c.total).Error; err != nil {
+				return fmt.Errorf("error inserting item %s: %w", key.item, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (p postgresDB) removeCatalogMonth(month agencyMonthKey) error {
+	if err := p.conn.Exec(`DELETE FROM catalogo_rubricas_zips WHERE id_orgao = ? AND mes = ? AND ano = ?`, month.agency, month.month, month.year).Error; err != nil {
+		return fmt.Errorf("error removing indexed zip: %w", err)
+	}
+	return nil
+}
+
+func (p postgresDB) catalogItems(agency string, since *period.YearMonth) ([]catalogAggregate, error) {
+	query := `SELECT
+		categoria_contracheque,
+		detalhamento_contracheque,
+		SUM(ocorrencias) AS ocorrencias,
+		SUM(total) AS total,
+		MIN(ano * 12 + mes - 1) AS primeiro,
+		MAX(ano * 12 + mes - 1) AS ultimo,
+		STRING_AGG(DISTINCT id_orgao, ',') AS orgaos
+	FROM catalogo_rubricas
+	GROUP BY categoria_contracheque, detalhamento_contracheque
+	HAVING TRUE`
+	var arguments []interface{}
+	if agency != "" {
+		query += " AND BOOL_OR(id_orgao = ?)"
+		arguments = append(arguments, agency)
+	}
+	if since != nil {
+		query += " AND MIN(ano * 12 + mes - 1) >= ?"
+		arguments = append(arguments, monthIndex(*since))
+	}
+	var rows []struct {
+		CategoriaContracheque    string
+		DetalhamentoContracheque string
+		Ocorrencias              int
+		Total                    float64
+		Primeiro                 int
+		Ultimo                   int
+		Orgaos                   string
+	}
+	if err := p.conn.Raw(query, arguments...).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting catalog items: %w", err)
+	}
+	aggregates := make([]catalogAggregate, 0, len(rows))
+	for _, r := range rows {
+		aggregates = append(aggregates, catalogAggregate{
+			key:         itemKey{category: r.CategoriaContracheque, item: r.DetalhamentoContracheque},
+			occurrences: r.Ocorrencias,
+			total:       r.Total,
+			first:       fromMonthIndex(r.Primeiro),
+			last:        fromMonthIndex(r.Ultimo),
+			agencies:    strings.Split(r.Orgaos, ","),
+		})
+	}
+	return aggregates, nil
+}
+
+func (p postgresDB) catalogStatus() (int, time.Time, error) {
+	var status struct {
+		Indexados int
+		Ultima    sql.NullTime
+	}
+	if err := p.conn.Raw(`SELECT COUNT(*) AS indexados, MAX(indexado_em) AS ultima FROM catalogo_rubricas_zips`).Scan(&status).Error; err != nil {
+		return 0, time.Time{}, fmt.Errorf("error getting catalog status: %w", err)
+	}
+	return status.Indexados, status.Ultima.Time, nil
+}
+
+// recentCollections lista as coletas atuais com dados, da mais recente para a
+// mais antiga pela data da coleta.
+func (p postgresDB) recentCollections(limit int) ([]models.AgencyMonthlyInfo, error) {
+	var dtos []dto.AgencyMonthlyInfoDTO
+	query := p.conn.Model(&dto.AgencyMonthlyInfoDTO{}).
+		Where("atual = TRUE AND sumario IS NOT NULL AND (procinfo IS NULL OR procinfo::text = 'null')").
+		Order("timestamp DESC").
+		Limit(limit)
+	if err := query.Find(&dtos).Error; err != nil {
+		return nil, fmt.Errorf("error getting recent collections: %w", err)
+	}
+	collections := make([]models.AgencyMonthlyInfo, 0, len(dtos))
+	for _, d := range dtos {
+		mi, err := d.ConvertToModel()
+		if err != nil {
+			return nil, fmt.Errorf("error converting collection %s: %w", d.ID, err)
+		}
+		collections = append(collections, *mi)
+	}
+	return collections, nil
+}
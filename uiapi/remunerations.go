@@ -0,0 +1,157 @@
+package uiapi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// memberRemuneration - remuneração de um membro em um órgão/mês, obtida
+// agrupando as linhas do zip de remunerações.
+type memberRemuneration struct {
+	Agency             string
+	Year               int
+	Month              int
+	Enrollment         *string
+	Name               string
+	Role               *string
+	Workplace          *string
+	BaseRemuneration   float64
+	OtherRemunerations float64
+	Discounts          float64
+	Items              []memberItem
+}
+
+type memberItem struct {
+	Category string
+	Item     string
+	Value    float64
+}
+
+// Remunerations retorna a remuneração bruta (base + outras).
+func (m memberRemuneration) Remunerations() float64 {
+	return m.BaseRemuneration + m.OtherRemunerations
+}
+
+// NetRemuneration retorna a remuneração líquida (bruta - descontos).
+func (m memberRemuneration) NetRemuneration() float64 {
+	return m.Remunerations() - m.Discounts
+}
+
+type memberKey struct {
+	agency     string
+	year       int
+	month      int
+	enrollment string
+	name       string
+}
+
+type itemKey struct {
+	category string
+	item     string
+}
+
+// memberAggregator agrupa as linhas dos zips de remunerações por membro,
+// mantendo a ordem em que cada membro aparece pela primeira vez.
+type memberAggregator struct {
+	members []*memberRemuneration
+	index   map[memberKey]int
+	items   []map[itemKey]int
+}
+
+func newMemberAggregator() *memberAggregator {
+	return &memberAggregator{index: make(map[memberKey]int)}
+}
+
+func (a *memberAggregator) add(r searchResult) error {
+	value, err := parseValue(r.Valor)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s (%s %d/%d): %w", r.Nome, r.Orgao, r.Mes, r.Ano, err)
+	}
+	key := memberKey{agency: r.Orgao, year: r.Ano, month: r.Mes, name: r.Nome}
+	if r.Matricula != nil {
+		key.enrollment = *r.Matricula
+	}
+	i, ok := a.index[key]
+	if !ok {
+		i = len(a.members)
+		a.index[key] = i
+		a.members = append(a.members, &memberRemuneration{
+			Agency:     r.Orgao,
+			Year:       r.Ano,
+			Month:      r.Mes,
+			Enrollment: r.Matricula,
+			Name:       r.Nome,
+			Role:       r.Cargo,
+			Workplace:  r.Lotacao,
+		})
+		a.items = append(a.items, make(map[itemKey]int))
+	}
+	m := a.members[i]
+	switch r.CategoriaContracheque {
+	case "base":
+		m.BaseRemuneration += value
+	case "outras":
+		m.OtherRemunerations += value
+	case "descontos":
+		m.Discounts += value
+	}
+	ik := itemKey{category: r.CategoriaContracheque, item: r.DetalhamentoContracheque}
+	j, ok := a.items[i][ik]
+	if !ok {
+		j = len(m.Items)
+		a.items[i][ik] = j
+		m.Items = append(m.Items, memberItem{Category: ik.category, Item: ik.item})
+	}
+	m.Items[j].Value += value
+	return nil
+}
+
+func (a *memberAggregator) result() []*memberRemuneration {
+	return a.members
+}
+
+// parseValue converte o valor de uma rubrica, aceitando tanto ponto quanto
+// vírgula como separador decimal.
+func parseValue(v string) (float64, error) {
+	v = normalizeValue(v)
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+// normalizeValue troca a vírgula decimal do valor de uma rubrica por ponto.
+func normalizeValue(v string) string {
+	v = strings.TrimSpace(v)
+	if strings.Contains(v, ",") && !strings.Contains(v, ".") {
+		v = strings.Replace(v, ",", ".", 1)
+	}
+	return v
+}
+
+// topMembers ordena os membros pela remuneração bruta, da maior para a menor,
+// e retorna os n primeiros.
+func topMembers(members []*memberRemuneration, n int) []*memberRemuneration {
+	sorted := make([]*memberRemuneration, len(members))
+	copy(sorted, members)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Remunerations() > sorted[j].Remunerations()
+	})
+	if n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// memberRemunerations baixa os zips de results e agrupa suas linhas por membro.
+func (h handler) memberRemunerations(results []searchDetails) ([]*memberRemuneration, error) {
+	agg := newMemberAggregator()
+	err := h.sess.streamRemunerationsFromS3(math.MaxInt, "", h.s3Bucket, results, agg.add)
+	if err != nil {
+		return nil, err
+	}
+	return agg.result(), nil
+}
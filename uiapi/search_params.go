@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/dadosjusbr/api/period"
 )
 
 type searchParams struct {
@@ -51,3 +53,23 @@ func newSearchParams(yearsQp, monthsQp, agenciesQp, categoriesQp, typesQp string
 		Types:    types,
 	}, nil
 }
+
+// periodSearchParams retorna os filtros de busca dos zips de remunerações do período.
+// Como os filtros de ano e mês são independentes, os resultados devem ser
+// filtrados novamente com Contains.
+func periodSearchParams(p *period.Period, agencies []string) *searchParams {
+	years := map[int]struct{}{}
+	months := map[int]struct{}{}
+	sp := &searchParams{Agencies: agencies}
+	for _, m := range p.Months() {
+		if _, ok := years[m.Year]; !ok {
+			years[m.Year] = struct{}{}
+			sp.Years = append(sp.Years, strconv.Itoa(m.Year))
+		}
+		if _, ok := months[m.Month]; !ok {
+			months[m.Month] = struct{}{}
+			sp.Months = append(sp.Months, strconv.Itoa(m.Month))
+		}
+	}
+	return sp
+}
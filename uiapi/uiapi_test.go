@@ -1,15 +1,27 @@
 package uiapi
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/dadosjusbr/api/classification"
+	"github.com/dadosjusbr/api/distribution"
+	"github.com/dadosjusbr/api/period"
+	"github.com/dadosjusbr/api/tables"
 	"github.com/dadosjusbr/proto/coleta"
 	"github.com/dadosjusbr/storage"
 	"github.com/dadosjusbr/storage/models"
@@ -74,7 +86,7 @@ func (g getSummaryOfAgency) testWhenDataExists(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020", "1")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -136,7 +148,7 @@ func (g getSummaryOfAgency) testWhenDataDoesNotExist(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020", "1")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -168,7 +180,7 @@ func (g getSummaryOfAgency) testWhenYearIsInvalid(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020a", "1")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -200,7 +212,7 @@ func (g getSummaryOfAgency) testWhenMonthIsInvalid(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020", "1a")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -245,7 +257,7 @@ func (g getSalaryOfAgencyMonthYear) testWhenDataExists(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020", "1")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -295,7 +307,7 @@ func (g getSalaryOfAgencyMonthYear) testWhenDataDoesNotExist(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020", "1")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -327,7 +339,7 @@ func (g getSalaryOfAgencyMonthYear) testWhenYearIsInvalid(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020a", "1")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -359,7 +371,7 @@ func (g getSalaryOfAgencyMonthYear) testWhenMonthIsInvalid(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020", "1a")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -400,7 +412,7 @@ func (g getSalaryOfAgencyMonthYear) testWhenProcInfoIsNotNull(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020", "1")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -473,7 +485,7 @@ func (g getBasicInfoOfType) testWhenGroupIsAJurisdiction(t *testing.T) {
 	ctx.SetParamValues("justica-estadual")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -534,7 +546,7 @@ func (g getBasicInfoOfType) testWhenGroupIsAnUF(t *testing.T) {
 	ctx.SetParamValues("PB")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -582,7 +594,7 @@ func (g getBasicInfoOfType) testWhenGroupDoesNotExist(t *testing.T) {
 	ctx.SetParamValues("grupo-que-nao-existe")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -627,7 +639,7 @@ func (g getBasicInfoOfType) testWhenJurisdictionIsInIrregularCase(t *testing.T)
 	ctx.SetParamValues("JuStiCa-esTaDuaL")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -688,7 +700,7 @@ func (g getBasicInfoOfType) testWhenUFIsInIrregularCase(t *testing.T) {
 	ctx.SetParamValues("pB")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -751,7 +763,7 @@ func (g getGeneralSummary) testWhenAllDataAreReturned(t *testing.T) {
 	ctx := e.NewContext(request, recorder)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -790,7 +802,7 @@ func (g getGeneralSummary) testWhenGetAgenciesCountReturnsAnError(t *testing.T)
 	ctx := e.NewContext(request, recorder)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -822,7 +834,7 @@ func (g getGeneralSummary) testWhenGetNumberOfMonthsCollectedReturnsAnError(t *t
 	ctx := e.NewContext(request, recorder)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -855,7 +867,7 @@ func (g getGeneralSummary) testWhenGetFirstDateWithMonthlyInfoReturnsAnError(t *
 	ctx := e.NewContext(request, recorder)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -889,7 +901,7 @@ func (g getGeneralSummary) testWhenGetLastDateWithMonthlyInfoReturnsAnError(t *t
 	ctx := e.NewContext(request, recorder)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -924,7 +936,7 @@ func (g getGeneralSummary) testWhenGetGeneralMonthlyInfoReturnsAnError(t *testin
 	ctx := e.NewContext(request, recorder)
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -942,6 +954,8 @@ func TestGetGeneralRemunerationFromYear(t *testing.T) {
 	t.Run("Test GetGeneralRemunerationFromYear when data exists", tests.testWhenDataExists)
 	t.Run("Test GetGeneralRemunerationFromYear when data does not exist", tests.testWhenDataDoesNotExist)
 	t.Run("Test GetGeneralRemunerationFromYear when year is invalid", tests.testWhenYearIsInvalid)
+	t.Run("Test GetGeneralRemunerationFromYear with IPCA correction", tests.testWithIPCACorrection)
+	t.Run("Test GetGeneralRemunerationFromYear with invalid correction", tests.testWithInvalidCorrection)
 }
 
 type getGenerealRemunerationFromYear struct{}
@@ -998,7 +1012,7 @@ func (g getGenerealRemunerationFromYear) testWhenDataExists(t *testing.T) {
 	ctx.SetParamValues("2020")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1070,7 +1084,7 @@ func (g getGenerealRemunerationFromYear) testWhenDataDoesNotExist(t *testing.T)
 	ctx.SetParamValues("2020")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1102,7 +1116,7 @@ func (g getGenerealRemunerationFromYear) testWhenYearIsInvalid(t *testing.T) {
 	ctx.SetParamValues("2020a")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1115,11 +1129,103 @@ func (g getGenerealRemunerationFromYear) testWhenYearIsInvalid(t *testing.T) {
 	assert.Equal(t, expectedJson, strings.Trim(recorder.Body.String(), "\n"))
 }
 
+func (g getGenerealRemunerationFromYear) testWithIPCACorrection(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	mi := []models.GeneralMonthlyInfo{
+		{
+			Month:              1,
+			Count:              100,
+			BaseRemuneration:   10000,
+			OtherRemunerations: 1000,
+			Discounts:          1000,
+			Remunerations:      11000,
+			ItemSummary:        models.ItemSummary{FoodAllowance: 100},
+		},
+		{
+			Month:              2,
+			Count:              100,
+			BaseRemuneration:   10000,
+			OtherRemunerations: 1000,
+			Discounts:          1000,
+			Remunerations:      11000,
+			ItemSummary:        models.ItemSummary{FoodAllowance: 100},
+		},
+	}
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetGeneralMonthlyInfosFromYear(2020).Return(mi, nil)
+
+	e := echo.New()
+	request := httptest.NewRequest(
+		http.MethodGet,
+		"/v2/geral/remuneracao/:ano?corrigir=ipca&base=2020-02",
+		nil,
+	)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("ano")
+	ctx.SetParamValues("2020")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetGeneralRemunerationFromYear(ctx)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got []mensalRemuneration
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Len(t, got, 2)
+	// IPCA de fevereiro de 2020: 0,25%.
+	assert.InDelta(t, 10025, got[0].BaseRemuneration, 1e-6)
+	assert.InDelta(t, 11027.5, got[0].Remunerations, 1e-6)
+	assert.InDelta(t, 100.25, got[0].ItemSummary.FoodAllowance, 1e-6)
+	assert.InDelta(t, 10000, got[1].BaseRemuneration, 1e-6)
+	assert.Equal(t, &tables.Correction{Index: "ipca", Base: "2020-02", TableVersion: tables.IPCA().Version}, got[1].Correction)
+}
+
+func (g getGenerealRemunerationFromYear) testWithInvalidCorrection(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(
+		http.MethodGet,
+		"/v2/geral/remuneracao/:ano?corrigir=ipca&base=2020",
+		nil,
+	)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("ano")
+	ctx.SetParamValues("2020")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetGeneralRemunerationFromYear(ctx)
+
+	expectedCode := http.StatusBadRequest
+	expectedJson := `"parâmetro base '2020' é inválido! Use o formato AAAA-MM"`
+
+	assert.Equal(t, expectedCode, recorder.Code)
+	assert.Equal(t, expectedJson, strings.Trim(recorder.Body.String(), "\n"))
+}
+
 func TestGetTotalsOfAgencyYear(t *testing.T) {
 	tests := getTotalsOfAgencyYear{}
 	t.Run("test when data exists", tests.testWhenDataExists)
 	t.Run("test when monthly info does not exist", getTotalsOfAgencyYear{}.testWhenMonthlyInfoDoesNotExist)
 	t.Run("test when year is invalid", tests.testWhenYearIsInvalid)
+	t.Run("test with minimum wage unit", tests.testWithMinimumWageUnit)
+	t.Run("test with correction and unit", tests.testWithCorrectionAndUnit)
 }
 
 type getTotalsOfAgencyYear struct{}
@@ -1168,7 +1274,7 @@ func (g getTotalsOfAgencyYear) testWhenDataExists(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1234,6 +1340,92 @@ func (g getTotalsOfAgencyYear) testWhenDataExists(t *testing.T) {
 	assert.JSONEq(t, expectedJson, recorder.Body.String())
 }
 
+func (g getTotalsOfAgencyYear) testWithMinimumWageUnit(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	agmi := agencyMonthlyInfos()
+	agency := models.Agency{ID: "tjal", Name: "Tribunal de Justiça do Estado de Alagoas"}
+	avg := models.PerCapitaData{
+		AgencyID:           "tjal",
+		Year:               2020,
+		BaseRemuneration:   33173.01121495333,
+		OtherRemunerations: 9119.563364485992,
+		Discounts:          10382.615233644861,
+		Remunerations:      33173.01121495333,
+	}
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetAgency("tjal").Return(&agency, nil).Times(1)
+	dbMock.EXPECT().GetMonthlyInfo([]models.Agency{{ID: "tjal"}}, 2020).Return(map[string][]models.AgencyMonthlyInfo{"tjal": agmi}, nil).Times(1)
+	fsMock.EXPECT().GetFile("tjal/datapackage/tjal-2020.zip").Return(agmi[0].Package, nil)
+	dbMock.EXPECT().GetAveragePerCapita("tjal", 2020).Return(&avg, nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(
+		http.MethodGet,
+		"/uiapi/v2/orgao/totais/:orgao/:ano?unidade=salario_minimo",
+		nil,
+	)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao", "ano")
+	ctx.SetParamValues("tjal", "2020")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetTotalsOfAgencyYear(ctx)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got v2AgencyTotalsYear
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	// Salário mínimo: R$ 1.039,00 em janeiro de 2020 e R$ 1.045,00 a partir de fevereiro.
+	assert.InDelta(t, 33173.01121495333/1039, got.MonthTotals[0].RemunerationsPerCapita, 1e-9)
+	assert.InDelta(t, 7.099024400000013e+06/1039, got.MonthTotals[0].Remunerations, 1e-6)
+	assert.InDelta(t, 100.0/1039, got.MonthTotals[0].ItemSummary.FoodAllowance, 1e-9)
+	// A média anual só considera os meses com dados (janeiro).
+	assert.InDelta(t, 33173.01121495333/1039, got.AveragePerCapita.Remunerations, 1e-9)
+	assert.Equal(t, 214, got.MonthTotals[0].MemberCount)
+	assert.Nil(t, got.Correction)
+	assert.Equal(t, &unit{Name: "salario_minimo", TableVersion: tables.MinimumWage().Version}, got.Unit)
+}
+
+func (g getTotalsOfAgencyYear) testWithCorrectionAndUnit(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(
+		http.MethodGet,
+		"/uiapi/v2/orgao/totais/:orgao/:ano?unidade=salario_minimo&corrigir=ipca",
+		nil,
+	)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao", "ano")
+	ctx.SetParamValues("tjal", "2020")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetTotalsOfAgencyYear(ctx)
+
+	expectedCode := http.StatusBadRequest
+	expectedJson := `"os parâmetros corrigir e unidade não podem ser usados juntos"`
+
+	assert.Equal(t, expectedCode, recorder.Code)
+	assert.Equal(t, expectedJson, strings.Trim(recorder.Body.String(), "\n"))
+}
+
 func (g getTotalsOfAgencyYear) testWhenMonthlyInfoDoesNotExist(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	dbMock := database.NewMockInterface(mockCtrl)
@@ -1275,7 +1467,7 @@ func (g getTotalsOfAgencyYear) testWhenMonthlyInfoDoesNotExist(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1327,7 +1519,7 @@ func (g getTotalsOfAgencyYear) testWhenYearIsInvalid(t *testing.T) {
 	ctx.SetParamValues("tjal", "2020a")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1346,6 +1538,7 @@ func TestGetAnnualSummary(t *testing.T) {
 	t.Run("Test GetAnnualSummary when agency does not exist", tests.testWhenAgencyDoesNotExist)
 	t.Run("Test GetAnnualSummary when GetAnnualSummary() returns error", tests.testWhenGetAnnualSummaryReturnsError)
 	t.Run("Test GetAnnualSummary when agency does not have data", tests.testWhenAgencyDoesNotHaveData)
+	t.Run("Test GetAnnualSummary with minimum wage unit", tests.testWithMinimumWageUnit)
 }
 
 type getAnnualSummary struct{}
@@ -1412,7 +1605,7 @@ func (g getAnnualSummary) testWhenDataExists(t *testing.T) {
 	ctx.SetParamValues("tjal")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1472,6 +1665,60 @@ func (g getAnnualSummary) testWhenDataExists(t *testing.T) {
 	assert.JSONEq(t, expectedJson, recorder.Body.String())
 }
 
+func (g getAnnualSummary) testWithMinimumWageUnit(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	agency := models.Agency{ID: "tjal", Name: "Tribunal de Justiça do Estado de Alagoas"}
+	agmi := []models.AnnualSummary{
+		{
+			Year:                   2020,
+			AverageCount:           214,
+			Remunerations:          20000,
+			RemunerationsPerCapita: 100,
+			NumMonthsWithData:      2,
+			Package:                &models.Backup{},
+			ItemSummary:            models.ItemSummary{FoodAllowance: 100},
+		},
+	}
+	// Só janeiro e fevereiro têm dados: março teve erro na coleta.
+	collections := []models.AgencyMonthlyInfo{
+		{AgencyID: "tjal", Year: 2020, Month: 1, Summary: &models.Summary{Count: 214}},
+		{AgencyID: "tjal", Year: 2020, Month: 2, Summary: &models.Summary{Count: 214}},
+		{AgencyID: "tjal", Year: 2020, Month: 3, ProcInfo: &coleta.ProcInfo{Status: 4, Stderr: "erro"}},
+	}
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	dbMock.EXPECT().GetAgency("tjal").Return(&agency, nil).Times(1)
+	dbMock.EXPECT().GetAnnualSummary("tjal").Return(agmi, nil).Times(1)
+	dbMock.EXPECT().GetAllAgencyCollection("tjal").Return(collections, nil).Times(1)
+	fsMock.EXPECT().GetFile("tjal/datapackage/tjal-2020.zip").Return(agmi[0].Package, nil)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/uiapi/v2/orgao/resumo/:orgao?unidade=salario_minimo", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao")
+	ctx.SetParamValues("tjal")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.GetAnnualSummary(ctx)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got annualSummary
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	// Salário mínimo: R$ 1.039,00 em janeiro de 2020 e R$ 1.045,00 em fevereiro.
+	f := (1.0/1039 + 1.0/1045) / 2
+	assert.InDelta(t, 20000*f, got.Data[0].Remunerations, 1e-9)
+	assert.InDelta(t, 100*f, got.Data[0].RemunerationsPerCapita, 1e-9)
+	assert.InDelta(t, 100*f, got.Data[0].ItemSummary.FoodAllowance, 1e-9)
+	assert.Equal(t, &unit{Name: "salario_minimo", TableVersion: tables.MinimumWage().Version}, got.Unit)
+}
+
 func (g getAnnualSummary) testWhenAgencyDoesNotExist(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	dbMock := database.NewMockInterface(mockCtrl)
@@ -1492,7 +1739,7 @@ func (g getAnnualSummary) testWhenAgencyDoesNotExist(t *testing.T) {
 	ctx.SetParamValues("tjal")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1536,7 +1783,7 @@ func (g getAnnualSummary) testWhenGetAnnualSummaryReturnsError(t *testing.T) {
 	ctx.SetParamValues("tjal")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1580,7 +1827,7 @@ func (g getAnnualSummary) testWhenAgencyDoesNotHaveData(t *testing.T) {
 	ctx.SetParamValues("tjal")
 
 	client, _ := storage.NewClient(dbMock, fsMock)
-	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1606,6 +1853,807 @@ func (g getAnnualSummary) testWhenAgencyDoesNotHaveData(t *testing.T) {
 	assert.JSONEq(t, expectedJson, recorder.Body.String())
 }
 
+func TestDataPackage(t *testing.T) {
+	tests := dataPackageTests{}
+	t.Run("Test data package schema matches the CSV columns", tests.testSchemaMatchesCSVColumns)
+	t.Run("Test data package zip contents", tests.testZipContents)
+}
+
+type dataPackageTests struct{}
+
+func (g dataPackageTests) testSchemaMatchesCSVColumns(t *testing.T) {
+	rt := reflect.TypeOf(searchResult{})
+	assert.Equal(t, rt.NumField(), len(searchResultSchema.Fields))
+	for i, f := range searchResultSchema.Fields {
+		assert.Equal(t, rt.Field(i).Tag.Get("csv"), f.Name)
+		assert.NotEmpty(t, f.Description)
+	}
+}
+
+func (g dataPackageTests) testZipContents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	fsMock.EXPECT().GetFile("tjal/datapackage/tjal-2020.zip").Return(&models.Backup{URL: "https://dadosjusbr.org/download/tjal/datapackage/tjal-2020.zip"}, nil).Times(1)
+	fsMock.EXPECT().GetFile("tjba/datapackage/tjba-2020.zip").Return(nil, fmt.Errorf("not found")).Times(1)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	searchResults := []searchResult{
+		{Orgao: "tjal", Mes: 1, Ano: 2020, Nome: "nome", CategoriaContracheque: "base", DetalhamentoContracheque: "subsidio", Valor: "1000.5"},
+		{Orgao: "tjba", Mes: 1, Ano: 2020, Nome: "nome", CategoriaContracheque: "base", DetalhamentoContracheque: "subsidio", Valor: "2000"},
+		{Orgao: "tjba", Mes: 1, Ano: 2020, Nome: "nome", CategoriaContracheque: "outras", DetalhamentoContracheque: "auxilio", Valor: "150,25"},
+		{Orgao: "tjba", Mes: 1, Ano: 2020, Nome: "nome", CategoriaContracheque: "descontos", DetalhamentoContracheque: "imposto", Valor: ""},
+	}
+	results := []searchDetails{
+		{Orgao: "tjal", Mes: 1, Ano: 2020, ZipUrl: "https://dadosjusbr_public.s3.amazonaws.com/tjal/remuneracoes/tjal-1-2020.zip"},
+		{Orgao: "tjal", Mes: 2, Ano: 2020, ZipUrl: "https://dadosjusbr_public.s3.amazonaws.com/tjal/remuneracoes/tjal-2-2020.zip"},
+	}
+	pkg := newDataPackage(handler.dataPackageSources(searchResults, results), time.Unix(1, 0).In(loc))
+
+	var buf bytes.Buffer
+	if err := writeDataPackage(&buf, pkg, searchResults); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(b)
+	}
+
+	expectedSources := `[
+		{"title": "Pacote de dados de tjal (2020)", "path": "https://dadosjusbr.org/download/tjal/datapackage/tjal-2020.zip"},
+		{"title": "Pacote de dados de tjba (2020)", "indisponivel": true},
+		{"title": "Remunerações de tjal (01/2020)", "path": "https://dadosjusbr_public.s3.amazonaws.com/tjal/remuneracoes/tjal-1-2020.zip"}
+	]`
+	var descriptor map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal([]byte(files["datapackage.json"]), &descriptor))
+	assert.JSONEq(t, expectedSources, string(descriptor["sources"]))
+	assert.Contains(t, string(descriptor["resources"]), `"enum": [`)
+	assert.Equal(t, "orgao,mes,ano,matricula,nome,cargo,lotacao,categoria_contracheque,detalhamento_contracheque,valor\ntjal,1,2020,,nome,,,base,subsidio,1000.5\ntjba,1,2020,,nome,,,base,subsidio,2000\ntjba,1,2020,,nome,,,outras,auxilio,150.25\ntjba,1,2020,,nome,,,descontos,imposto,\n", files["dadosjusbr-remuneracoes.csv"])
+	assert.Contains(t, files["README.md"], "Pacote de dados de tjba (2020): indisponível")
+	assert.Contains(t, files["README.md"], "https://dadosjusbr_public.s3.amazonaws.com/tjal/remuneracoes/tjal-1-2020.zip")
+	assert.NotContains(t, files["README.md"], "tjal-2-2020.zip")
+}
+
+func TestReadRemunerationsZip(t *testing.T) {
+	tests := readRemunerationsZipTests{}
+	t.Run("Test reading every row of the zip", tests.testReadEveryRow)
+	t.Run("Test stopping when the callback returns an error", tests.testStopWhenCallbackReturnsError)
+	t.Run("Test invalid zip", tests.testInvalidZip)
+}
+
+type readRemunerationsZipTests struct{}
+
+func (g readRemunerationsZipTests) testReadEveryRow(t *testing.T) {
+	var rows []searchResult
+	err := readRemunerationsZip(remunerationsZip(t, remunerationsCSV), "tjal-1-2020.zip", func(r searchResult) error {
+		rows = append(rows, r)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, rows, 6)
+	assert.Equal(t, "fulano", rows[0].Nome)
+	assert.Equal(t, "descontos", rows[2].CategoriaContracheque)
+	assert.Equal(t, "2500.5", rows[1].Valor)
+}
+
+func (g readRemunerationsZipTests) testStopWhenCallbackReturnsError(t *testing.T) {
+	count := 0
+	err := readRemunerationsZip(remunerationsZip(t, remunerationsCSV), "tjal-1-2020.zip", func(r searchResult) error {
+		count++
+		if count == 2 {
+			return errLimitReached
+		}
+		return nil
+	})
+	assert.Equal(t, errLimitReached, err)
+	assert.Equal(t, 2, count)
+}
+
+func (g readRemunerationsZipTests) testInvalidZip(t *testing.T) {
+	err := readRemunerationsZip([]byte("not a zip"), "tjal-1-2020.zip", func(r searchResult) error { return nil })
+	assert.Error(t, err)
+}
+
+const remunerationsCSV = `orgao;mes;ano;matricula;nome;cargo;lotacao;categoria_contracheque;detalhamento_contracheque;valor
+tjal;1;2020;1;fulano;juiz;maceio;base;subsidio;30000
+tjal;1;2020;1;fulano;juiz;maceio;outras;auxilio-alimentacao;2500.5
+tjal;1;2020;1;fulano;juiz;maceio;descontos;imposto de renda;7000
+tjal;1;2020;2;beltrano;desembargador;maceio;base;subsidio;35000
+tjal;1;2020;2;beltrano;desembargador;maceio;outras;licenca-premio;40000
+tjal;1;2020;2;beltrano;desembargador;maceio;descontos;imposto de renda;9000
+`
+
+// remunerationsZip cria um zip com o CSV de remunerações, no mesmo formato dos
+// arquivos armazenados no S3.
+func remunerationsZip(t *testing.T, csv string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("remuneracoes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(f, csv); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGetTopEarners(t *testing.T) {
+	tests := getTopEarners{}
+	t.Run("Test members are grouped and sorted by remuneration", tests.testGroupAndSort)
+	t.Run("Test GetTopEarners when n is invalid", tests.testWhenNIsInvalid)
+	t.Run("Test GetTopEarners when month is invalid", tests.testWhenMonthIsInvalid)
+}
+
+type getTopEarners struct{}
+
+func (g getTopEarners) testGroupAndSort(t *testing.T) {
+	agg := newMemberAggregator()
+	err := readRemunerationsZip(remunerationsZip(t, remunerationsCSV), "tjal-1-2020.zip", agg.add)
+	assert.NoError(t, err)
+
+	top := topMembers(agg.result(), 1)
+	assert.Len(t, agg.result(), 2)
+	assert.Len(t, top, 1)
+
+	expectedJson := `
+		{
+			"nome": "beltrano",
+			"matricula": "2",
+			"cargo": "desembargador",
+			"lotacao": "maceio",
+			"remuneracao_base": 35000,
+			"outras_remuneracoes": 40000,
+			"remuneracao_bruta": 75000,
+			"descontos": 9000,
+			"remuneracao_liquida": 66000,
+			"rubricas": [
+				{"categoria": "base", "detalhamento": "subsidio", "valor": 35000},
+				{"categoria": "outras", "detalhamento": "licenca-premio", "valor": 40000},
+				{"categoria": "descontos", "detalhamento": "imposto de renda", "valor": 9000}
+			]
+		}
+	`
+	b, _ := json.Marshal(newEarner(top[0], 1))
+	assert.JSONEq(t, expectedJson, string(b))
+}
+
+func (g getTopEarners) testWhenNIsInvalid(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/uiapi/v2/orgao/maiores/:orgao/:ano/:mes?n=0", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao", "ano", "mes")
+	ctx.SetParamValues("tjal", "2020", "1")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetTopEarners(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"Parâmetro n=0 inválido"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func (g getTopEarners) testWhenMonthIsInvalid(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/uiapi/v2/orgao/maiores/:orgao/:ano/:mes", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao", "ano", "mes")
+	ctx.SetParamValues("tjal", "2020", "1a")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetTopEarners(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"Parâmetro mês=1a inválido"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestGetIncomeHistogram(t *testing.T) {
+	tests := getIncomeHistogram{}
+	t.Run("Test histogram with a number of bins", tests.testBinCount)
+	t.Run("Test histogram with explicit edges", tests.testExplicitEdges)
+	t.Run("Test invalid bins", tests.testInvalidBins)
+	t.Run("Test GetIncomeHistogram when metric is invalid", tests.testWhenMetricIsInvalid)
+}
+
+type getIncomeHistogram struct{}
+
+func (g getIncomeHistogram) testBinCount(t *testing.T) {
+	agg := newMemberAggregator()
+	err := readRemunerationsZip(remunerationsZip(t, remunerationsCSV), "tjal-1-2020.zip", agg.add)
+	assert.NoError(t, err)
+	var values []float64
+	for _, m := range agg.result() {
+		values = append(values, m.Remunerations())
+	}
+
+	bins, below, above := newHistogram(values, binSpec{count: 2})
+	assert.Equal(t, []histogramBin{
+		{Min: 32500.5, Max: 53750.25, Count: 1},
+		{Min: 53750.25, Max: 75000, Count: 1},
+	}, bins)
+	assert.Zero(t, below)
+	assert.Zero(t, above)
+}
+
+func (g getIncomeHistogram) testExplicitEdges(t *testing.T) {
+	spec, err := parseBins("0, 10000,20000,30000")
+	assert.NoError(t, err)
+	bins, below, above := newHistogram([]float64{-1, 0, 9999.99, 10000, 20000, 30000, 30000.01}, spec)
+	assert.Equal(t, []histogramBin{
+		{Min: 0, Max: 10000, Count: 2},
+		{Min: 10000, Max: 20000, Count: 1},
+		{Min: 20000, Max: 30000, Count: 2},
+	}, bins)
+	assert.Equal(t, 1, below)
+	assert.Equal(t, 1, above)
+}
+
+func (g getIncomeHistogram) testInvalidBins(t *testing.T) {
+	for _, qp := range []string{"0", "101", "dez", "0,10000,5000", "0,10000,a", "10000"} {
+		_, err := parseBins(qp)
+		assert.Error(t, err, qp)
+	}
+	spec, err := parseBins("")
+	assert.NoError(t, err)
+	assert.Equal(t, binSpec{count: defaultHistogramBins}, spec)
+}
+
+func (g getIncomeHistogram) testWhenMetricIsInvalid(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/uiapi/v2/orgao/histograma/:orgao?inicio=2020-01&fim=2020-12&metrica=media", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao")
+	ctx.SetParamValues("tjal")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetIncomeHistogram(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"parâmetro metrica 'media' é inválido! Valores aceitos: bruto, liquido, base"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestDistribution(t *testing.T) {
+	tests := distributionTests{}
+	t.Run("Test distribution of the members of a zip", tests.testMembersDistribution)
+	t.Run("Test each member/month is corrected before the statistics", tests.testCorrectedDistribution)
+	t.Run("Test cache is invalidated when the zips change", tests.testCache)
+	t.Run("Test cache drops the least recently used distribution", tests.testCacheLimit)
+}
+
+type distributionTests struct{}
+
+func (g distributionTests) testMembersDistribution(t *testing.T) {
+	agg := newMemberAggregator()
+	err := readRemunerationsZip(remunerationsZip(t, remunerationsCSV), "tjal-1-2020.zip", agg.add)
+	assert.NoError(t, err)
+
+	d := membersDistribution(agg.result(), nil)
+	assert.Equal(t, 2, d.Members)
+	assert.InDelta(t, 53750.25, d.Gross.Median, 1e-9)
+	assert.InDelta(t, 45750.25, d.Net.Median, 1e-9)
+	assert.InDelta(t, 42499.5/(2*107500.5), d.Gross.Gini, 1e-9)
+}
+
+func (g distributionTests) testCorrectedDistribution(t *testing.T) {
+	deflator, err := tables.NewDeflator("ipca", "2020-02")
+	assert.NoError(t, err)
+	// Mesma remuneração nominal em meses diferentes: corrigidas, as
+	// observações diferem e o Gini deixa de ser zero.
+	members := []*memberRemuneration{
+		{Year: 2020, Month: 1, BaseRemuneration: 10000},
+		{Year: 2020, Month: 2, BaseRemuneration: 10000},
+	}
+	d := membersDistribution(members, deflator)
+	assert.InDelta(t, (10000*deflator.Factor(2020, 1)+10000)/2, d.Gross.Median, 1e-9)
+	assert.Greater(t, d.Gross.Gini, 0.0)
+	assert.Equal(t, 0.0, membersDistribution(members, nil).Gross.Gini)
+}
+
+func (g distributionTests) testCache(t *testing.T) {
+	c := newDistributionCache(10)
+	computed := 0
+	compute := func() (distribution.Distribution, error) {
+		computed++
+		return distribution.New([]float64{float64(computed)}, []float64{0}), nil
+	}
+	d, err := c.get("tjal/2020/1", "a", compute)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, d.Gross.Median)
+
+	d, _ = c.get("tjal/2020/1", "a", compute)
+	assert.Equal(t, 1.0, d.Gross.Median)
+	assert.Equal(t, 1, computed)
+
+	d, _ = c.get("tjal/2020/1", "b", compute)
+	assert.Equal(t, 2.0, d.Gross.Median)
+
+	_, err = c.get("tjal/2020/2", "a", func() (distribution.Distribution, error) {
+		return distribution.Distribution{}, fmt.Errorf("s3 unavailable")
+	})
+	assert.Error(t, err)
+	_, ok := c.entries["tjal/2020/2"]
+	assert.False(t, ok)
+}
+
+func (g distributionTests) testCacheLimit(t *testing.T) {
+	c := newDistributionCache(2)
+	compute := func() (distribution.Distribution, error) {
+		return distribution.New([]float64{1}, []float64{1}), nil
+	}
+	c.get("tjal/2020/1", "a", compute)
+	c.get("tjal/2020/2", "a", compute)
+	c.get("tjal/2020/1", "a", compute)
+	c.get("tjal/2020/3", "a", compute)
+
+	assert.Len(t, c.entries, 2)
+	_, ok := c.entries["tjal/2020/2"]
+	assert.False(t, ok)
+	_, ok = c.entries["tjal/2020/1"]
+	assert.True(t, ok)
+}
+
+func TestExceptionalPayments(t *testing.T) {
+	tests := exceptionalPaymentsTests{}
+	t.Run("Test payments are compared with the member's history", tests.testFindExceptionalPayments)
+	t.Run("Test GetExceptionalPayments when history is invalid", tests.testWhenHistoryIsInvalid)
+}
+
+type exceptionalPaymentsTests struct{}
+
+// fulano recebe uma diferença de subsídio em maio; beltrano tem poucos meses
+// para ser avaliado.
+const exceptionalPaymentsCSV = `orgao;mes;ano;matricula;nome;cargo;lotacao;categoria_contracheque;detalhamento_contracheque;valor
+tjal;1;2020;1;fulano;juiz;maceio;base;subsidio;30000
+tjal;1;2020;1;fulano;juiz;maceio;outras;auxilio-alimentacao;1000
+tjal;2;2020;1;fulano;juiz;maceio;base;subsidio;30000
+tjal;2;2020;1;fulano;juiz;maceio;outras;auxilio-alimentacao;1000
+tjal;3;2020;1;fulano;juiz;maceio;base;subsidio;30000
+tjal;3;2020;1;fulano;juiz;maceio;outras;auxilio-alimentacao;1000
+tjal;4;2020;1;fulano;juiz;maceio;base;subsidio;30000
+tjal;4;2020;1;fulano;juiz;maceio;outras;auxilio-alimentacao;1000
+tjal;5;2020;1;fulano;juiz;maceio;base;subsidio;30000
+tjal;5;2020;1;fulano;juiz;maceio;outras;auxilio-alimentacao;1000
+tjal;5;2020;1;fulano;juiz;maceio;outras;diferenca de subsidio - exercicios anteriores;93000
+tjal;3;2020;2;beltrano;desembargador;maceio;base;subsidio;35000
+tjal;4;2020;2;beltrano;desembargador;maceio;base;subsidio;35000
+tjal;5;2020;2;beltrano;desembargador;maceio;base;subsidio;35000
+tjal;5;2020;2;beltrano;desembargador;maceio;outras;licenca-premio;100000
+`
+
+func (g exceptionalPaymentsTests) testFindExceptionalPayments(t *testing.T) {
+	agg := newMemberAggregator()
+	err := readRemunerationsZip(remunerationsZip(t, exceptionalPaymentsCSV), "tjal.zip", agg.add)
+	assert.NoError(t, err)
+
+	p, _ := period.New("2020-03", "2020-05", 0)
+	got := findExceptionalPayments(agg.result(), p, 1, classification.Default(), nil)
+	enrollment, role := "1", "juiz"
+	assert.Equal(t, []exceptionalPayment{{
+		Name:               "fulano",
+		Enrollment:         &enrollment,
+		Role:               &role,
+		Year:               2020,
+		Month:              5,
+		Item:               "diferenca de subsidio - exercicios anteriores",
+		Category:           "retroativos",
+		Value:              93000,
+		ItemMedian:         0,
+		MedianRemuneration: 31000,
+		Ratio:              3,
+		ComparedMonths:     4,
+	}}, got)
+
+	assert.Empty(t, findExceptionalPayments(agg.result(), p, 3.5, classification.Default(), nil))
+}
+
+func (g exceptionalPaymentsTests) testWhenHistoryIsInvalid(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/uiapi/v2/orgao/pagamentos-excepcionais/:orgao?inicio=2020-01&fim=2020-12&historico=24", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao")
+	ctx.SetParamValues("tjal")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetExceptionalPayments(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"Parâmetro historico=24 inválido"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestGetItemCategories(t *testing.T) {
+	tests := getItemCategories{}
+	t.Run("Test items are classified by the dictionary", tests.testClassification)
+	t.Run("Test GetItemCategories when year is invalid", tests.testWhenYearIsInvalid)
+}
+
+type getItemCategories struct{}
+
+func (g getItemCategories) testClassification(t *testing.T) {
+	agg := newCategoryAggregator(classification.Default())
+	err := readRemunerationsZip(remunerationsZip(t, remunerationsCSV), "tjal-1-2020.zip", agg.add)
+	assert.NoError(t, err)
+
+	expected := []itemCategory{
+		{PayslipCategory: "base", Category: "subsidio", Total: 65000, Members: 2, Items: []string{"subsidio"}},
+		{PayslipCategory: "outras", Category: "licenca_premio", Total: 40000, Members: 1, Items: []string{"licenca-premio"}},
+		{PayslipCategory: "outras", Category: "auxilio_alimentacao", Total: 2500.5, Members: 1, Items: []string{"auxilio-alimentacao"}},
+		{PayslipCategory: "descontos", Category: "imposto_de_renda", Total: 16000, Members: 2, Items: []string{"imposto de renda"}},
+	}
+	// result não altera o agregador: a correção não se acumula entre chamadas.
+	corrected := agg.result(2)
+	assert.Equal(t, 130000.0, corrected[0].Total)
+	assert.Equal(t, expected, agg.result(1))
+}
+
+func (g getItemCategories) testWhenYearIsInvalid(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/uiapi/v2/rubricas/categorias/:orgao/:ano/:mes", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("orgao", "ano", "mes")
+	ctx.SetParamValues("tjal", "2020a", "1")
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetItemCategories(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"Parâmetro ano=2020a inválido"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestItemCatalog(t *testing.T) {
+	tests := itemCatalogTests{}
+	t.Run("Test catalog is built from the zips", tests.testBuildCatalog)
+	t.Run("Test only new zips are read", tests.testIncrementalIndex)
+	t.Run("Test only changed zips are read again", tests.testReindexChangedZip)
+	t.Run("Test removed zips leave the catalog", tests.testRemovedZip)
+	t.Run("Test failed zips are retried", tests.testRetryFailedZip)
+	t.Run("Test catalog filters", tests.testFilters)
+	t.Run("Test GetItemCatalog when desde is invalid", tests.testWhenSinceIsInvalid)
+}
+
+type itemCatalogTests struct{}
+
+// countingReader lê o CSV de remunerações para qualquer zip, contando as leituras.
+func countingReader(t *testing.T, reads *int) readZipFunc {
+	return func(z searchDetails, fn func(searchResult) error) error {
+		*reads++
+		return readRemunerationsZip(remunerationsZip(t, remunerationsCSV), z.ZipUrl, fn)
+	}
+}
+
+var catalogZips = []searchDetails{
+	{Orgao: "tjal", Ano: 2020, Mes: 1, ZipUrl: "tjal-1-2020.zip", Base: 2, Outras: 2, Descontos: 2},
+	{Orgao: "tjba", Ano: 2020, Mes: 3, ZipUrl: "tjba-3-2020.zip", Base: 2, Outras: 2, Descontos: 2},
+}
+
+func (g itemCatalogTests) testBuildCatalog(t *testing.T) {
+	reads := 0
+	c := newItemCatalog(newMemoryCatalogStore(), nil)
+	assert.NoError(t, c.index(catalogZips, countingReader(t, &reads)))
+
+	items, err := c.items(classification.Default(), catalogFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, items, 4)
+	assert.Equal(t, catalogItem{
+		Item:            "subsidio",
+		PayslipCategory: "base",
+		Category:        "subsidio",
+		Occurrences:     4,
+		Total:           130000,
+		FirstSeen:       "2020-01",
+		LastSeen:        "2020-03",
+		Agencies:        []string{"tjal", "tjba"},
+	}, items[0])
+
+	indexed, updated, indexing, err := c.status()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, indexed)
+	assert.False(t, updated.IsZero())
+	assert.False(t, indexing)
+}
+
+func (g itemCatalogTests) testIncrementalIndex(t *testing.T) {
+	reads := 0
+	c := newItemCatalog(newMemoryCatalogStore(), nil)
+	assert.NoError(t, c.index(catalogZips[:1], countingReader(t, &reads)))
+	assert.NoError(t, c.index(catalogZips, countingReader(t, &reads)))
+	assert.NoError(t, c.index(catalogZips, countingReader(t, &reads)))
+	assert.Equal(t, 2, reads)
+
+	items, err := c.items(classification.Default(), catalogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, items[0].Occurrences)
+}
+
+func (g itemCatalogTests) testReindexChangedZip(t *testing.T) {
+	reads := 0
+	c := newItemCatalog(newMemoryCatalogStore(), nil)
+	assert.NoError(t, c.index(catalogZips, countingReader(t, &reads)))
+
+	changed := append([]searchDetails{}, catalogZips...)
+	changed[1].Base = 3
+	assert.NoError(t, c.index(changed, countingReader(t, &reads)))
+	assert.Equal(t, 3, reads)
+
+	// O zip alterado substitui o anterior, em vez de ser somado a ele.
+	items, err := c.items(classification.Default(), catalogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, items[0].Occurrences)
+}
+
+func (g itemCatalogTests) testRemovedZip(t *testing.T) {
+	reads := 0
+	c := newItemCatalog(newMemoryCatalogStore(), nil)
+	assert.NoError(t, c.index(catalogZips, countingReader(t, &reads)))
+	assert.NoError(t, c.index(catalogZips[:1], countingReader(t, &reads)))
+	assert.Equal(t, 2, reads)
+
+	indexed, _, _, err := c.status()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, indexed)
+	items, err := c.items(classification.Default(), catalogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tjal"}, items[0].Agencies)
+}
+
+func (g itemCatalogTests) testRetryFailedZip(t *testing.T) {
+	reads := 0
+	c := newItemCatalog(newMemoryCatalogStore(), nil)
+	failing := func(z searchDetails, fn func(searchResult) error) error {
+		if z.Orgao == "tjba" {
+			return fmt.Errorf("network error")
+		}
+		return countingReader(t, &reads)(z, fn)
+	}
+	assert.Error(t, c.index(catalogZips, failing))
+	indexed, _, _, err := c.status()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, indexed)
+
+	assert.NoError(t, c.index(catalogZips, countingReader(t, &reads)))
+	indexed, _, _, err = c.status()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, indexed)
+	assert.Equal(t, 2, reads)
+}
+
+func (g itemCatalogTests) testFilters(t *testing.T) {
+	reads := 0
+	store := newMemoryCatalogStore()
+	c := newItemCatalog(store, nil)
+	assert.NoError(t, c.index(catalogZips[:1], countingReader(t, &reads)))
+	assert.NoError(t, store.replaceCatalogMonth(agencyMonthKey{"tjba", 2021, 5}, "tjba-5-2021.zip", map[itemKey]itemCount{
+		{category: "outras", item: "auxilio-saude"}: {occurrences: 1, total: 1000},
+	}))
+	dict := classification.Default()
+
+	items, err := c.items(dict, catalogFilter{category: "imposto_de_renda"})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "imposto de renda", items[0].Item)
+
+	items, err = c.items(dict, catalogFilter{agency: "tjba"})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "auxilio-saude", items[0].Item)
+
+	items, err = c.items(dict, catalogFilter{since: &period.YearMonth{Year: 2021, Month: 1}})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "2021-05", items[0].FirstSeen)
+
+	items, err = c.items(dict, catalogFilter{agency: "mppb"})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func (g itemCatalogTests) testWhenSinceIsInvalid(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/v2/rubricas/catalogo?desde=2021", nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetItemCatalog(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"parâmetro desde '2021' é inválido! Use o formato AAAA-MM"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func TestGetCeilingReport(t *testing.T) {
+	tests := getCeilingReport{}
+	t.Run("Test ceiling report from remunerations", tests.testReportFromRemunerations)
+	t.Run("Test GetCeilingReport when period is invalid", tests.testWhenPeriodIsInvalid)
+	t.Run("Test GetCeilingReport when group does not exist", tests.testWhenGroupDoesNotExist)
+	t.Run("Test GetCeilingReport when group has no agencies", tests.testWhenGroupHasNoAgencies)
+	t.Run("Test GetCeilingReport when param is invalid", tests.testWhenParamIsInvalid)
+}
+
+type getCeilingReport struct{}
+
+func (g getCeilingReport) testReportFromRemunerations(t *testing.T) {
+	// A licença-prêmio e o auxílio-alimentação não entram na comparação com o
+	// teto: só o subsídio e a gratificação de beltrano ultrapassam o teto.
+	csv := remunerationsCSV + "tjal;1;2020;2;beltrano;desembargador;maceio;outras;gratificacao por acumulacao de acervo;10000\n"
+	agg := newMemberAggregator()
+	err := readRemunerationsZip(remunerationsZip(t, csv), "tjal-1-2020.zip", agg.add)
+	assert.NoError(t, err)
+
+	dict := classification.Default()
+	report := newCeilingReport(agg.result(), tables.Ceiling(), dict, nil)
+	report.Agencies = []string{"tjal"}
+	report.Start = "2020-01"
+	report.End = "2020-01"
+	assert.Contains(t, report.ExcludedCategories, "licenca_premio")
+	assert.Contains(t, report.ExcludedCategories, "auxilio_alimentacao")
+	assert.NotContains(t, report.ExcludedCategories, "gratificacao_acumulacao")
+	report.ExcludedCategories = nil
+	b, _ := json.Marshal(report)
+
+	expectedJson := fmt.Sprintf(`
+		{
+			"orgaos": ["tjal"],
+			"inicio": "2020-01",
+			"fim": "2020-01",
+			"versao_tabela_teto": "%s",
+			"versao_dicionario": "%s",
+			"categorias_fora_do_teto": null,
+			"membros_acima_teto": 1,
+			"ocorrencias_acima_teto": 1,
+			"valor_acima_teto": 5706.68,
+			"meses": [
+				{
+					"orgao": "tjal",
+					"ano": 2020,
+					"mes": 1,
+					"teto": 39293.32,
+					"total_membros": 2,
+					"membros_acima_teto": 1,
+					"valor_acima_teto": 5706.68
+				}
+			],
+			"rubricas": [
+				{"detalhamento": "gratificacao por acumulacao de acervo", "categoria": "gratificacao_acumulacao", "ocorrencias": 1, "valor": 10000}
+			]
+		}
+	`, tables.Ceiling().Version, dict.Version)
+	assert.JSONEq(t, expectedJson, string(b))
+}
+
+func (g getCeilingReport) testWhenPeriodIsInvalid(t *testing.T) {
+	recorder := g.request(t, "/uiapi/v2/teto/:param/:valor?inicio=2020-05&fim=2020-01", "orgao", "tjal")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"o parâmetro fim '2020-01' é anterior ao parâmetro inicio '2020-05'"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func (g getCeilingReport) testWhenGroupDoesNotExist(t *testing.T) {
+	recorder := g.request(t, "/uiapi/v2/teto/:param/:valor?inicio=2020-01&fim=2020-12", "grupo", "justica-inexistente")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, `"Grupo não encontrado: 'justica-inexistente'"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func (g getCeilingReport) testWhenGroupHasNoAgencies(t *testing.T) {
+	recorder := g.request(t, "/uiapi/v2/teto/:param/:valor?inicio=2020-01&fim=2020-12", "grupo", "justica-estadual", func(dbMock *database.MockInterface) {
+		dbMock.EXPECT().GetOPJ("Estadual").Return([]models.Agency{}, nil).Times(1)
+	})
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, `"Nenhum órgão encontrado no grupo 'justica-estadual'"`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func (g getCeilingReport) testWhenParamIsInvalid(t *testing.T) {
+	recorder := g.request(t, "/uiapi/v2/teto/:param/:valor?inicio=2020-01&fim=2020-12", "estado", "pb")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, `"Parâmetro inválido: estado."`, strings.Trim(recorder.Body.String(), "\n"))
+}
+
+func (g getCeilingReport) request(t *testing.T, url, param, value string, expect ...func(*database.MockInterface)) *httptest.ResponseRecorder {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	for _, e := range expect {
+		e(dbMock)
+	}
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, url, nil)
+	recorder := httptest.NewRecorder()
+	ctx := e.NewContext(request, recorder)
+	ctx.SetParamNames("param", "valor")
+	ctx.SetParamValues(param, value)
+
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.V2GetCeilingReport(ctx)
+	return recorder
+}
+
 func agencyMonthlyInfos() []models.AgencyMonthlyInfo {
 	return []models.AgencyMonthlyInfo{
 		{
@@ -1685,3 +2733,137 @@ func agencyMonthlyInfos() []models.AgencyMonthlyInfo {
 		},
 	}
 }
+
+// s3Stub serve os arquivos informados como um bucket do S3 (URLs no estilo
+// /<bucket>/<chave>) e retorna uma sessão apontando para ele.
+func s3Stub(t *testing.T, bucket string, files map[string][]byte) *awsSession {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, ok := files[strings.TrimPrefix(r.URL.Path, "/"+bucket+"/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code></Error>`)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(b))
+	}))
+	t.Cleanup(srv.Close)
+	sess, err := session.NewSession(&aws.Config{
+		Region:           aws.String("us-east-1"),
+		Endpoint:         aws.String(srv.URL),
+		S3ForcePathStyle: aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &awsSession{Sess: sess}
+}
+
+// flushRecorder registra quantas linhas já tinham sido escritas a cada Flush.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes []int
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushes = append(f.flushes, strings.Count(f.Body.String(), "\n"))
+	f.ResponseRecorder.Flush()
+}
+
+func TestDownloadByUrlWithoutFilters(t *testing.T) {
+	for _, format := range []string{"", "csv", "datapackage", "ndjson"} {
+		mockCtrl := gomock.NewController(t)
+		dbMock := database.NewMockInterface(mockCtrl)
+		fsMock := file_storage.NewMockInterface(mockCtrl)
+		dbMock.EXPECT().Connect().Return(nil).Times(1)
+		client, _ := storage.NewClient(dbMock, fsMock)
+		handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		e := echo.New()
+		request := httptest.NewRequest(http.MethodGet, "/uiapi/v2/download?formato="+format, nil)
+		recorder := httptest.NewRecorder()
+		ctx := e.NewContext(request, recorder)
+		assert.NoError(t, handler.DownloadByUrl(ctx))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, format)
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	tests := streamNDJSONTests{}
+	t.Run("Test flushing and limit", tests.testFlushAndLimit)
+	t.Run("Test error record when a zip can not be read", tests.testErrorRecord)
+}
+
+type streamNDJSONTests struct{}
+
+func (g streamNDJSONTests) stream(t *testing.T, files map[string][]byte, limit int, results []searchDetails) (*flushRecorder, []map[string]interface{}) {
+	mockCtrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCtrl)
+	fsMock := file_storage.NewMockInterface(mockCtrl)
+	dbMock.EXPECT().Connect().Return(nil).Times(1)
+	client, _ := storage.NewClient(dbMock, fsMock)
+	handler, err := NewHandler(client, nil, nil, "us-east-1", "dadosjusbr_public", loc, []string{}, 100, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.sess = s3Stub(t, "dadosjusbr_public", files)
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/uiapi/v2/pesquisar?formato=ndjson", nil)
+	recorder := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	ctx := e.NewContext(request, recorder)
+	assert.NoError(t, handler.streamNDJSON(ctx, "dadosjusbr-pesquisa.ndjson", limit, "", results))
+
+	var lines []map[string]interface{}
+	for _, l := range strings.Split(strings.TrimSpace(recorder.Body.String()), "\n") {
+		var line map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(l), &line))
+		lines = append(lines, line)
+	}
+	return recorder, lines
+}
+
+func (g streamNDJSONTests) testFlushAndLimit(t *testing.T) {
+	var csv strings.Builder
+	csv.WriteString("orgao;mes;ano;matricula;nome;cargo;lotacao;categoria_contracheque;detalhamento_contracheque;valor\n")
+	for i := 0; i < 150; i++ {
+		fmt.Fprintf(&csv, "tjal;1;2020;%d;membro %d;juiz;maceio;base;subsidio;30000\n", i, i)
+	}
+	files := map[string][]byte{
+		"tjal/remuneracoes/tjal-1-2020.zip": remunerationsZip(t, csv.String()),
+		"tjal/remuneracoes/tjal-2-2020.zip": remunerationsZip(t, strings.ReplaceAll(csv.String(), "tjal;1;2020", "tjal;2;2020")),
+	}
+	results := []searchDetails{
+		{Orgao: "tjal", Mes: 2, Ano: 2020, ZipUrl: "https://dadosjusbr_public.s3.amazonaws.com/tjal/remuneracoes/tjal-2-2020.zip"},
+		{Orgao: "tjal", Mes: 1, Ano: 2020, ZipUrl: "https://dadosjusbr_public.s3.amazonaws.com/tjal/remuneracoes/tjal-1-2020.zip"},
+	}
+	recorder, lines := g.stream(t, files, 230, results)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/x-ndjson", recorder.Header().Get(echo.HeaderContentType))
+	assert.Len(t, lines, 230)
+	// Os zips são lidos em ordem de órgão/ano/mês.
+	assert.Equal(t, 1.0, lines[0]["mes"])
+	assert.Equal(t, 2.0, lines[229]["mes"])
+	// O cabeçalho é enviado antes dos dados e as linhas são enviadas a cada ndjsonFlushInterval.
+	assert.Equal(t, []int{0, 100, 200, 230}, recorder.flushes)
+}
+
+func (g streamNDJSONTests) testErrorRecord(t *testing.T) {
+	files := map[string][]byte{
+		"tjal/remuneracoes/tjal-1-2020.zip": remunerationsZip(t, remunerationsCSV),
+	}
+	results := []searchDetails{
+		{Orgao: "tjal", Mes: 1, Ano: 2020, ZipUrl: "https://dadosjusbr_public.s3.amazonaws.com/tjal/remuneracoes/tjal-1-2020.zip"},
+		{Orgao: "tjal", Mes: 2, Ano: 2020, ZipUrl: "https://dadosjusbr_public.s3.amazonaws.com/tjal/remuneracoes/tjal-2-2020.zip"},
+	}
+	recorder, lines := g.stream(t, files, 100, results)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, lines, 7)
+	assert.Equal(t, "fulano", lines[0]["nome"])
+	assert.Equal(t, map[string]interface{}{"erro": "Erro lendo as remunerações: o arquivo está incompleto"}, lines[6])
+}
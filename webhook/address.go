@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrInvalidURL é retornado quando a URL da assinatura não é aceita: esquema
+// diferente de http/https, host que não resolve ou que aponta para um endereço
+// interno (loopback, rede privada, link-local...).
+var ErrInvalidURL = errors.New("invalid webhook url")
+
+// errForbiddenAddress é retornado pelo dialer ao tentar conectar a um endereço
+// interno. A verificação é feita também na conexão, e não só na assinatura,
+// porque o DNS do host pode mudar depois da assinatura.
+var errForbiddenAddress = errors.New("forbidden address")
+
+// publicAddress diz se o endereço pode receber entregas.
+func publicAddress(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast()
+}
+
+// checkURL verifica a URL da assinatura, resolvendo o host.
+func (d *Dispatcher) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: %s", ErrInvalidURL, rawURL)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: host %s not found", ErrInvalidURL, u.Hostname())
+	}
+	for _, a := range addrs {
+		if !d.allowedAddress(a.IP) {
+			return fmt.Errorf("%w: host %s resolves to %s", ErrInvalidURL, u.Hostname(), a.IP)
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) allowedAddress(ip net.IP) bool {
+	return d.AllowPrivateAddresses || publicAddress(ip)
+}
+
+// newClient cria o cliente HTTP das entregas: não segue redirecionamentos,
+// não usa proxy e recusa conexões a endereços internos.
+func (d *Dispatcher) newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: defaultTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !d.allowedAddress(ip) {
+				return fmt.Errorf("%w: %s", errForbiddenAddress, host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: defaultTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: defaultTimeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// A resposta de redirecionamento é tratada como a resposta final: um
+		// 3xx não confirma a entrega.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Categorias de erro guardadas nas entregas. A mensagem completa do erro não é
+// guardada porque é exibida a qualquer um que tenha o id da assinatura.
+const (
+	errorInvalidRequest   = "requisicao_invalida"
+	errorForbiddenAddress = "endereco_bloqueado"
+	errorDNS              = "dns"
+	errorTimeout          = "tempo_esgotado"
+	errorConnRefused      = "conexao_recusada"
+	errorTLS              = "tls"
+	errorNetwork          = "erro_de_rede"
+)
+
+// errorCategory classifica o erro da requisição.
+func errorCategory(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var certErr x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	switch {
+	case errors.Is(err, errForbiddenAddress):
+		return errorForbiddenAddress
+	case errors.As(err, &dnsErr):
+		return errorDNS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return errorTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return errorConnRefused
+	case errors.As(err, &certErr), errors.As(err, &hostErr), errors.As(err, &invalidErr):
+		return errorTLS
+	default:
+		return errorNetwork
+	}
+}
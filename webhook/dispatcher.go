@@ -0,0 +1,396 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dadosjusbr/api/lease"
+)
+
+// Cabeçalhos enviados em cada entrega.
+const (
+	SignatureHeader = "X-DadosJusBr-Signature" // sha256=<HMAC-SHA256 do corpo, em hexadecimal>
+	EventHeader     = "X-DadosJusBr-Event"     // tipo do evento
+	DeliveryHeader  = "X-DadosJusBr-Delivery"  // id do evento
+)
+
+const (
+	defaultMaxAttempts = 5
+	// Com o padrão, as novas tentativas são feitas ao longo de duas horas e meia.
+	defaultBackoff = 10 * time.Minute
+	defaultTimeout = 10 * time.Second
+	// Validade da concessão das rodadas. É renovada antes de cada entrega.
+	leaseTTL = 15 * time.Minute
+)
+
+// ErrVerificationFailed é retornado quando a URL não responde à verificação
+// feita na criação da assinatura.
+var ErrVerificationFailed = errors.New("webhook url verification failed")
+
+// Sign retorna a assinatura do corpo, no formato do cabeçalho SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify diz se a assinatura confere com o corpo. Pode ser usada pelos
+// assinantes escritos em Go.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Dispatcher busca as coletas novas e as entrega às assinaturas.
+type Dispatcher struct {
+	store  Store
+	source Source
+	client *http.Client
+	// MaxAttempts é o número máximo de tentativas de cada entrega.
+	MaxAttempts int
+	// Backoff é a espera antes da segunda tentativa. A espera dobra a cada nova
+	// tentativa, que é feita na primeira rodada depois da espera.
+	Backoff time.Duration
+	// AllowPrivateAddresses permite URLs em endereços internos (loopback, rede
+	// privada...). Só deve ser usado nos testes.
+	AllowPrivateAddresses bool
+	// Lease é a concessão das rodadas: só a instância que a detém busca as
+	// coletas e faz as entregas. Se for nil, a instância sempre faz as rodadas.
+	Lease *lease.Lease
+	now   func() time.Time
+}
+
+// NewDispatcher cria um Dispatcher. Se client for nil, usa um cliente HTTP com
+// timeout de 10s, que não segue redirecionamentos e recusa endereços internos.
+func NewDispatcher(store Store, source Source, client *http.Client) *Dispatcher {
+	d := &Dispatcher{
+		store:       store,
+		source:      source,
+		client:      client,
+		MaxAttempts: defaultMaxAttempts,
+		Backoff:     defaultBackoff,
+		now:         time.Now,
+	}
+	if d.client == nil {
+		d.client = d.newClient()
+	}
+	return d
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating random id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Subscribe cria uma assinatura, gerando o id e o segredo. Antes de gravar a
+// assinatura, a URL é verificada: o host não pode ser um endereço interno e a
+// URL deve responder ao evento de verificação com o desafio enviado, o que
+// mostra que o dono da URL pediu a assinatura.
+func (d *Dispatcher) Subscribe(url, agency, group string) (Subscription, error) {
+	if err := d.checkURL(url); err != nil {
+		return Subscription{}, err
+	}
+	id, err := randomHex(16)
+	if err != nil {
+		return Subscription{}, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return Subscription{}, err
+	}
+	s := Subscription{
+		ID:        id,
+		URL:       url,
+		Secret:    secret,
+		Agency:    agency,
+		Group:     group,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := d.verify(s); err != nil {
+		return Subscription{}, err
+	}
+	if err := d.store.AddSubscription(s); err != nil {
+		return Subscription{}, fmt.Errorf("error saving subscription: %w", err)
+	}
+	return s, nil
+}
+
+// verify envia o evento de verificação para a URL da assinatura. A resposta deve
+// ter status 2xx e trazer o desafio no corpo.
+func (d *Dispatcher) verify(s Subscription) error {
+	challenge, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+	e := Event{ID: Verification + "/" + challenge, Type: Verification, CrawlingTimestamp: time.Now().UTC(), Challenge: challenge}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("error marshaling event: %w", err)
+	}
+	resp, err := d.send(s, e, body)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrVerificationFailed, errorCategory(err))
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 || !strings.Contains(string(b), challenge) {
+		return fmt.Errorf("%w: status %d", ErrVerificationFailed, resp.StatusCode)
+	}
+	return nil
+}
+
+// Subscription retorna a assinatura, sem o segredo.
+func (d *Dispatcher) Subscription(id string) (Subscription, error) {
+	s, err := d.store.Subscription(id)
+	if err != nil {
+		return Subscription{}, err
+	}
+	s.Secret = ""
+	return s, nil
+}
+
+// Unsubscribe apaga a assinatura.
+func (d *Dispatcher) Unsubscribe(id string) error {
+	return d.store.DeleteSubscription(id)
+}
+
+// Deliveries retorna as últimas entregas da assinatura.
+func (d *Dispatcher) Deliveries(id string, limit int) ([]Delivery, error) {
+	if _, err := d.store.Subscription(id); err != nil {
+		return nil, err
+	}
+	return d.store.Deliveries(id, limit)
+}
+
+// Ping envia um evento de teste para a assinatura, sem novas tentativas, e
+// retorna o registro da entrega.
+func (d *Dispatcher) Ping(id string) (Delivery, error) {
+	s, err := d.store.Subscription(id)
+	if err != nil {
+		return Delivery{}, err
+	}
+	eventID, err := randomHex(8)
+	if err != nil {
+		return Delivery{}, err
+	}
+	e := Event{ID: Ping + "/" + eventID, Type: Ping, CrawlingTimestamp: time.Now().UTC()}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("error marshaling event: %w", err)
+	}
+	delivery := d.post(s, e, body, 1)
+	if err := d.store.AddDelivery(delivery); err != nil {
+		return Delivery{}, fmt.Errorf("error saving delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// Deliver faz a primeira tentativa de entrega do evento à assinatura.
+func (d *Dispatcher) Deliver(s Subscription, e Event) error {
+	return d.attempt(s, e, 1)
+}
+
+// attempt faz uma tentativa de entrega e a registra. Se a falha for temporária
+// (erro de rede, 429 ou 5xx) e ainda houver tentativas, a entrega fica
+// pendente e é tentada de novo em uma rodada seguinte, depois da espera.
+func (d *Dispatcher) attempt(s Subscription, e Event, attempt int) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("error marshaling event: %w", err)
+	}
+	delivery := d.post(s, e, body, attempt)
+	if err := d.store.AddDelivery(delivery); err != nil {
+		log.Printf("[webhook] error saving delivery of %s to %s: %q", e.ID, s.ID, err)
+	}
+	if !delivery.Delivered && attempt < d.MaxAttempts && retryable(delivery) {
+		next := d.now().UTC().Add(d.Backoff << (attempt - 1))
+		if err := d.store.SetRetry(Retry{SubscriptionID: s.ID, Event: e, Attempt: attempt + 1, NextAttempt: next}); err != nil {
+			return fmt.Errorf("error saving retry of %s to %s: %w", e.ID, s.ID, err)
+		}
+		return fmt.Errorf("event %s not delivered to %s on attempt %d, retrying after %s: %s", e.ID, s.ID, attempt, next.Format(time.RFC3339), deliveryError(delivery))
+	}
+	if attempt > 1 {
+		if err := d.store.DeleteRetry(s.ID, e.ID); err != nil {
+			log.Printf("[webhook] %q", err)
+		}
+	}
+	if !delivery.Delivered {
+		return fmt.Errorf("event %s not delivered to %s after %d attempt(s): %s", e.ID, s.ID, attempt, deliveryError(delivery))
+	}
+	return nil
+}
+
+// post faz uma tentativa de entrega.
+func (d *Dispatcher) post(s Subscription, e Event, body []byte, attempt int) Delivery {
+	delivery := Delivery{
+		SubscriptionID: s.ID,
+		EventID:        e.ID,
+		EventType:      e.Type,
+		Attempt:        attempt,
+		Timestamp:      time.Now().UTC(),
+	}
+	resp, err := d.send(s, e, body)
+	delivery.Duration = time.Since(delivery.Timestamp).Milliseconds()
+	if err != nil {
+		// O erro completo fica só no log do servidor.
+		log.Printf("[webhook] error posting %s to %s: %q", e.ID, s.ID, err)
+		var reqErr requestError
+		if errors.As(err, &reqErr) {
+			delivery.Error = errorInvalidRequest
+		} else {
+			delivery.Error = errorCategory(err)
+		}
+		return delivery
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	delivery.StatusCode = resp.StatusCode
+	delivery.Delivered = resp.StatusCode >= 200 && resp.StatusCode < 300
+	return delivery
+}
+
+// requestError é o erro na montagem da requisição, antes do envio.
+type requestError struct{ error }
+
+// send faz o POST assinado do evento.
+func (d *Dispatcher) send(s Subscription, e Event, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, requestError{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "DadosJusBr-Webhook")
+	req.Header.Set(SignatureHeader, Sign(s.Secret, body))
+	req.Header.Set(EventHeader, e.Type)
+	req.Header.Set(DeliveryHeader, e.ID)
+	return d.client.Do(req)
+}
+
+func retryable(d Delivery) bool {
+	return d.StatusCode == 0 || d.StatusCode == http.StatusTooManyRequests || d.StatusCode >= 500
+}
+
+func deliveryError(d Delivery) string {
+	if d.Error != "" {
+		return d.Error
+	}
+	return fmt.Sprintf("status %d", d.StatusCode)
+}
+
+// pending é uma entrega a ser feita em uma rodada.
+type pending struct {
+	event   Event
+	attempt int
+}
+
+// Run faz uma rodada: busca as coletas feitas desde a última rodada e as
+// entrega às assinaturas interessadas, junto com as novas tentativas das
+// entregas que falharam nas rodadas anteriores. As assinaturas são atendidas
+// em paralelo; em cada uma, as novas tentativas são feitas antes dos eventos
+// novos. Na primeira rodada não há eventos: o cursor é apenas iniciado com o
+// momento atual, para não enviar todo o histórico. Se outra instância detém a
+// concessão das rodadas, nada é feito.
+func (d *Dispatcher) Run() error {
+	ok, err := d.Lease.Acquire(leaseTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	since, err := d.store.Cursor()
+	if err != nil {
+		return fmt.Errorf("error getting cursor: %w", err)
+	}
+	if since.IsZero() {
+		return d.store.SetCursor(time.Now().UTC())
+	}
+	retries, err := d.store.DueRetries(d.now().UTC())
+	if err != nil {
+		return fmt.Errorf("error getting retries: %w", err)
+	}
+	changes, err := d.source.Changes(since)
+	if err != nil {
+		return fmt.Errorf("error getting changes since %s: %w", since, err)
+	}
+	if len(changes) == 0 && len(retries) == 0 {
+		return nil
+	}
+	subscriptions, err := d.store.Subscriptions()
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions: %w", err)
+	}
+	var wg sync.WaitGroup
+	for _, s := range subscriptions {
+		var deliveries []pending
+		for _, r := range retries {
+			if r.SubscriptionID == s.ID {
+				deliveries = append(deliveries, pending{r.Event, r.Attempt})
+			}
+		}
+		for _, c := range changes {
+			if s.Matches(c) {
+				deliveries = append(deliveries, pending{NewEvent(c), 1})
+			}
+		}
+		if len(deliveries) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(s Subscription, deliveries []pending) {
+			defer wg.Done()
+			for _, p := range deliveries {
+				if ok, err := d.Lease.Acquire(leaseTTL); err != nil || !ok {
+					log.Printf("[webhook] lease lost, stopping deliveries to %s (err: %v)", s.ID, err)
+					return
+				}
+				if err := d.attempt(s, p.event, p.attempt); err != nil {
+					log.Printf("[webhook] %q", err)
+				}
+			}
+		}(s, deliveries)
+	}
+	wg.Wait()
+	if len(changes) == 0 {
+		return nil
+	}
+	// Se a concessão foi perdida, o cursor fica como está e a instância que a
+	// assumiu entrega os eventos de novo. O id do evento permite ao assinante
+	// descartar as repetições.
+	if ok, err := d.Lease.Acquire(leaseTTL); err != nil || !ok {
+		return fmt.Errorf("lease lost before saving the cursor (err: %v)", err)
+	}
+	last := changes[len(changes)-1].Timestamp
+	for _, c := range changes {
+		if c.Timestamp.After(last) {
+			last = c.Timestamp
+		}
+	}
+	return d.store.SetCursor(last)
+}
+
+// Start faz as rodadas em segundo plano, uma a cada interval.
+func (d *Dispatcher) Start(interval time.Duration) {
+	go func() {
+		for {
+			if err := d.Run(); err != nil {
+				log.Printf("[webhook] error dispatching events: %q", err)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
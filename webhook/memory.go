@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore guarda as assinaturas e as entregas em memória. Os dados são
+// perdidos ao reiniciar a API: é usado nos testes e quando não há banco.
+type MemoryStore struct {
+	mu            sync.RWMutex
+	subscriptions []Subscription
+	deliveries    []Delivery
+	retries       []Retry
+	cursor        time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (m *MemoryStore) AddSubscription(s Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscriptions = append(m.subscriptions, s)
+	return nil
+}
+
+func (m *MemoryStore) Subscription(id string) (Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, s := range m.subscriptions {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return Subscription{}, ErrNotFound
+}
+
+func (m *MemoryStore) Subscriptions() ([]Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Subscription{}, m.subscriptions...), nil
+}
+
+func (m *MemoryStore) DeleteSubscription(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, s := range m.subscriptions {
+		if s.ID == id {
+			m.subscriptions = append(m.subscriptions[:i], m.subscriptions[i+1:]...)
+			var retries []Retry
+			for _, r := range m.retries {
+				if r.SubscriptionID != id {
+					retries = append(retries, r)
+				}
+			}
+			m.retries = retries
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (m *MemoryStore) AddDelivery(d Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, d)
+	return nil
+}
+
+func (m *MemoryStore) Deliveries(subscriptionID string, limit int) ([]Delivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	deliveries := []Delivery{}
+	for i := len(m.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if m.deliveries[i].SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, m.deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *MemoryStore) SetRetry(r Retry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, p := range m.retries {
+		if p.SubscriptionID == r.SubscriptionID && p.Event.ID == r.Event.ID {
+			m.retries[i] = r
+			return nil
+		}
+	}
+	m.retries = append(m.retries, r)
+	return nil
+}
+
+func (m *MemoryStore) DeleteRetry(subscriptionID, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, r := range m.retries {
+		if r.SubscriptionID == subscriptionID && r.Event.ID == eventID {
+			m.retries = append(m.retries[:i], m.retries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) DueRetries(now time.Time) ([]Retry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var due []Retry
+	for _, r := range m.retries {
+		if !r.NextAttempt.After(now) {
+			due = append(due, r)
+		}
+	}
+	return due, nil
+}
+
+func (m *MemoryStore) Cursor() (time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cursor, nil
+}
+
+func (m *MemoryStore) SetCursor(t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cursor = t
+	return nil
+}
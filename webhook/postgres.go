@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dadosjusbr/storage/models"
+	"gorm.io/gorm"
+)
+
+// Postgres guarda as assinaturas e as entregas nas tabelas webhooks,
+// webhook_entregas, webhook_pendentes e webhook_cursor (ver init_db.sql) e
+// lista as coletas novas a partir da tabela coletas.
+type Postgres struct {
+	conn *gorm.DB
+}
+
+func NewPostgres(conn *gorm.DB) *Postgres {
+	return &Postgres{conn: conn}
+}
+
+type subscriptionRow struct {
+	ID        string    `gorm:"column:id"`
+	URL       string    `gorm:"column:url"`
+	Secret    string    `gorm:"column:segredo"`
+	Agency    string    `gorm:"column:id_orgao"`
+	Group     string    `gorm:"column:grupo"`
+	CreatedAt time.Time `gorm:"column:criado_em"`
+}
+
+func (r subscriptionRow) toSubscription() Subscription {
+	return Subscription{
+		ID:        r.ID,
+		URL:       r.URL,
+		Secret:    r.Secret,
+		Agency:    r.Agency,
+		Group:     r.Group,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (p *Postgres) AddSubscription(s Subscription) error {
+	query := `INSERT INTO webhooks (id, url, segredo, id_orgao, grupo, criado_em) VALUES (?, ?, ?, ?, ?, ?)`
+	if err := p.conn.Exec(query, s.ID, s.URL, s.Secret, s.Agency, s.Group, s.CreatedAt).Error; err != nil {
+		return fmt.Errorf("error inserting webhook: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) Subscription(id string) (Subscription, error) {
+	var rows []subscriptionRow
+	query := `SELECT id, url, segredo, COALESCE(id_orgao, '') AS id_orgao, COALESCE(grupo, '') AS grupo, criado_em FROM webhooks WHERE id = ?`
+	if err := p.conn.Raw(query, id).Scan(&rows).Error; err != nil {
+		return Subscription{}, fmt.Errorf("error getting webhook (%s): %w", id, err)
+	}
+	if len(rows) == 0 {
+		return Subscription{}, ErrNotFound
+	}
+	return rows[0].toSubscription(), nil
+}
+
+func (p *Postgres) Subscriptions() ([]Subscription, error) {
+	var rows []subscriptionRow
+	query := `SELECT id, url, segredo, COALESCE(id_orgao, '') AS id_orgao, COALESCE(grupo, '') AS grupo, criado_em FROM webhooks ORDER BY criado_em`
+	if err := p.conn.Raw(query).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting webhooks: %w", err)
+	}
+	subscriptions := make([]Subscription, len(rows))
+	for i, r := range rows {
+		subscriptions[i] = r.toSubscription()
+	}
+	return subscriptions, nil
+}
+
+func (p *Postgres) DeleteSubscription(id string) error {
+	// As entregas e as entregas pendentes são apagadas em cascata.
+	res := p.conn.Exec(`DELETE FROM webhooks WHERE id = ?`, id)
+	if res.Error != nil {
+		return fmt.Errorf("error deleting webhook (%s): %w", id, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type deliveryRow struct {
+	SubscriptionID string    `gorm:"column:id_webhook"`
+	EventID        string    `gorm:"column:id_evento"`
+	EventType      string    `gorm:"column:tipo"`
+	Attempt        int       `gorm:"column:tentativa"`
+	StatusCode     int       `gorm:"column:status_http"`
+	Error          string    `gorm:"column:erro"`
+	Delivered      bool      `gorm:"column:entregue"`
+	Timestamp      time.Time `gorm:"column:timestamp"`
+	Duration       int64     `gorm:"column:duracao_ms"`
+}
+
+func (p *Postgres) AddDelivery(d Delivery) error {
+	query := `INSERT INTO webhook_entregas (id_webhook, id_evento, tipo, tentativa, status_http, erro, entregue, timestamp, duracao_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if err := p.conn.Exec(query, d.SubscriptionID, d.EventID, d.EventType, d.Attempt, d.StatusCode, d.Error, d.Delivered, d.Timestamp, d.Duration).Error; err != nil {
+		return fmt.Errorf("error inserting webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) Deliveries(subscriptionID string, limit int) ([]Delivery, error) {
+	var rows []deliveryRow
+	query := `SELECT id_webhook, id_evento, tipo, tentativa, status_http, erro, entregue, timestamp, duracao_ms
+		FROM webhook_entregas WHERE id_webhook = ? ORDER BY id DESC LIMIT ?`
+	if err := p.conn.Raw(query, subscriptionID, limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting webhook deliveries (%s): %w", subscriptionID, err)
+	}
+	deliveries := make([]Delivery, len(rows))
+	for i, r := range rows {
+		deliveries[i] = Delivery(r)
+	}
+	return deliveries, nil
+}
+
+type retryRow struct {
+	SubscriptionID string    `gorm:"column:id_webhook"`
+	Event          string    `gorm:"column:evento"`
+	Attempt        int       `gorm:"column:tentativa"`
+	NextAttempt    time.Time `gorm:"column:proxima_tentativa"`
+}
+
+func (p *Postgres) SetRetry(r Retry) error {
+	event, err := json.Marshal(r.Event)
+	if err != nil {
+		return fmt.Errorf("error marshaling event %s: %w", r.Event.ID, err)
+	}
+	query := `INSERT INTO webhook_pendentes (id_webhook, id_evento, evento, tentativa, proxima_tentativa) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id_webhook, id_evento) DO UPDATE SET evento = EXCLUDED.evento, tentativa = EXCLUDED.tentativa, proxima_tentativa = EXCLUDED.proxima_tentativa`
+	if err := p.conn.Exec(query, r.SubscriptionID, r.Event.ID, string(event), r.Attempt, r.NextAttempt).Error; err != nil {
+		return fmt.Errorf("error saving webhook retry: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) DeleteRetry(subscriptionID, eventID string) error {
+	if err := p.conn.Exec(`DELETE FROM webhook_pendentes WHERE id_webhook = ? AND id_evento = ?`, subscriptionID, eventID).Error; err != nil {
+		return fmt.Errorf("error deleting webhook retry (%s, %s): %w", subscriptionID, eventID, err)
+	}
+	return nil
+}
+
+func (p *Postgres) DueRetries(now time.Time) ([]Retry, error) {
+	var rows []retryRow
+	query := `SELECT id_webhook, evento::text AS evento, tentativa, proxima_tentativa
+		FROM webhook_pendentes WHERE proxima_tentativa <= ? ORDER BY proxima_tentativa`
+	if err := p.conn.Raw(query, now).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting webhook retries: %w", err)
+	}
+	retries := make([]Retry, 0, len(rows))
+	for _, r := range rows {
+		retry := Retry{SubscriptionID: r.SubscriptionID, Attempt: r.Attempt, NextAttempt: r.NextAttempt}
+		if err := json.Unmarshal([]byte(r.Event), &retry.Event); err != nil {
+			return nil, fmt.Errorf("error unmarshaling webhook retry of %s: %w", r.SubscriptionID, err)
+		}
+		retries = append(retries, retry)
+	}
+	return retries, nil
+}
+
+func (p *Postgres) Cursor() (time.Time, error) {
+	var rows []time.Time
+	if err := p.conn.Raw(`SELECT timestamp FROM webhook_cursor WHERE id = 1`).Scan(&rows).Error; err != nil {
+		return time.Time{}, fmt.Errorf("error getting webhook cursor: %w", err)
+	}
+	if len(rows) == 0 {
+		return time.Time{}, nil
+	}
+	return rows[0], nil
+}
+
+func (p *Postgres) SetCursor(t time.Time) error {
+	query := `INSERT INTO webhook_cursor (id, timestamp) VALUES (1, ?) ON CONFLICT (id) DO UPDATE SET timestamp = EXCLUDED.timestamp`
+	if err := p.conn.Exec(query, t).Error; err != nil {
+		return fmt.Errorf("error setting webhook cursor: %w", err)
+	}
+	return nil
+}
+
+type changeRow struct {
+	Agency       string    `gorm:"column:id_orgao"`
+	Jurisdiction string    `gorm:"column:jurisdicao"`
+	Month        int       `gorm:"column:mes"`
+	Year         int       `gorm:"column:ano"`
+	Timestamp    time.Time `gorm:"column:timestamp"`
+	Score        float64   `gorm:"column:indice_transparencia"`
+	Summary      string    `gorm:"column:sumario"`
+	Previous     *string   `gorm:"column:sumario_anterior"`
+}
+
+// Changes lista as coletas atuais com dados feitas depois de since. O resumo
+// anterior é o da última coleta com dados do mesmo órgão/mês que foi
+// substituída pela atual.
+func (p *Postgres) Changes(since time.Time) ([]Change, error) {
+	var rows []changeRow
+	query := `SELECT
+			c.id_orgao,
+			o.jurisdicao,
+			c.mes,
+			c.ano,
+			c.timestamp,
+			c.indice_transparencia,
+			c.sumario::text AS sumario,
+			(SELECT a.sumario::text FROM coletas a
+				WHERE a.id = c.id AND a.atual = false AND a.timestamp < c.timestamp
+				AND (a.procinfo IS NULL OR a.procinfo::text = 'null')
+				ORDER BY a.timestamp DESC LIMIT 1) AS sumario_anterior
+		FROM coletas c
+		INNER JOIN orgaos o ON o.id = c.id_orgao
+		WHERE c.atual = true AND c.timestamp > ? AND (c.procinfo IS NULL OR c.procinfo::text = 'null')
+		ORDER BY c.timestamp`
+	if err := p.conn.Raw(query, since).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting collections since %s: %w", since, err)
+	}
+	changes := make([]Change, 0, len(rows))
+	for _, r := range rows {
+		c := Change{
+			Agency:       r.Agency,
+			Jurisdiction: r.Jurisdiction,
+			Year:         r.Year,
+			Month:        r.Month,
+			Timestamp:    r.Timestamp,
+			Score:        r.Score,
+		}
+		var summary models.Summary
+		if err := json.Unmarshal([]byte(r.Summary), &summary); err != nil {
+			return nil, fmt.Errorf("error unmarshaling summary of %s/%d/%d: %w", r.Agency, r.Month, r.Year, err)
+		}
+		c.Summary = &summary
+		if r.Previous != nil {
+			var previous models.Summary
+			if err := json.Unmarshal([]byte(*r.Previous), &previous); err != nil {
+				return nil, fmt.Errorf("error unmarshaling previous summary of %s/%d/%d: %w", r.Agency, r.Month, r.Year, err)
+			}
+			c.Previous = &previous
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
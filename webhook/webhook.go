@@ -0,0 +1,190 @@
+// Package webhook avisa os assinantes quando uma coleta nova aparece ou quando
+// um órgão/mês é coletado de novo.
+//
+// Uma assinatura aponta para uma URL e pode ser restrita a um órgão ou a um
+// grupo de órgãos. A cada rodada, o Dispatcher busca as coletas feitas desde a
+// última rodada e envia um POST assinado (HMAC-SHA256 do corpo com o segredo da
+// assinatura) para cada assinatura interessada. As entregas que falham são
+// repetidas com intervalos crescentes e todas as tentativas ficam registradas.
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dadosjusbr/api/taxonomy"
+	"github.com/dadosjusbr/storage/models"
+)
+
+// Tipos de evento.
+const (
+	NewCollection = "nova_coleta" // primeira coleta com dados do órgão/mês
+	Recollection  = "recoleta"    // órgão/mês que já tinha dados foi coletado de novo
+	Ping          = "teste"       // evento enviado a pedido do assinante, para testar a URL
+	Verification  = "verificacao" // evento enviado na criação da assinatura; a resposta deve trazer o desafio
+)
+
+// ErrNotFound é retornado quando a assinatura não existe.
+var ErrNotFound = errors.New("subscription not found")
+
+// Subscription é uma assinatura de webhook. Se Agency e Group forem vazios, a
+// assinatura recebe os eventos de todos os órgãos.
+type Subscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"segredo,omitempty"` // só é retornado na criação da assinatura
+	Agency    string    `json:"orgao,omitempty"`
+	Group     string    `json:"grupo,omitempty"` // slug do grupo, como em /v2/grupos
+	CreatedAt time.Time `json:"criado_em"`
+}
+
+// Matches diz se a assinatura tem interesse na coleta.
+func (s Subscription) Matches(c Change) bool {
+	if s.Agency != "" && !strings.EqualFold(s.Agency, c.Agency) {
+		return false
+	}
+	if s.Group != "" {
+		g, ok := taxonomy.GroupBySlug(s.Group)
+		if !ok || !strings.EqualFold(g.Jurisdiction, c.Jurisdiction) {
+			return false
+		}
+	}
+	return true
+}
+
+// Change é uma coleta feita desde a última rodada. Previous é o resumo da
+// coleta anterior do mesmo órgão/mês, ou nil se o mês ainda não tinha dados.
+type Change struct {
+	Agency       string
+	Jurisdiction string
+	Year         int
+	Month        int
+	Timestamp    time.Time
+	Score        float64
+	Summary      *models.Summary
+	Previous     *models.Summary
+}
+
+// Figures são os números do resumo de uma coleta enviados nos eventos.
+type Figures struct {
+	MemberCount        int     `json:"membros"`
+	BaseRemuneration   float64 `json:"remuneracao_base"`
+	OtherRemunerations float64 `json:"outras_remuneracoes"`
+	Discounts          float64 `json:"descontos"`
+	Remunerations      float64 `json:"remuneracoes"`
+}
+
+func newFigures(s *models.Summary) Figures {
+	if s == nil {
+		return Figures{}
+	}
+	return Figures{
+		MemberCount:        s.Count,
+		BaseRemuneration:   s.BaseRemuneration.Total,
+		OtherRemunerations: s.OtherRemunerations.Total,
+		Discounts:          s.Discounts.Total,
+		Remunerations:      s.Remunerations.Total,
+	}
+}
+
+func (f Figures) sub(o Figures) Figures {
+	return Figures{
+		MemberCount:        f.MemberCount - o.MemberCount,
+		BaseRemuneration:   f.BaseRemuneration - o.BaseRemuneration,
+		OtherRemunerations: f.OtherRemunerations - o.OtherRemunerations,
+		Discounts:          f.Discounts - o.Discounts,
+		Remunerations:      f.Remunerations - o.Remunerations,
+	}
+}
+
+// Event é o corpo do POST enviado aos assinantes. Nas recoletas, Previous traz
+// o resumo anterior e Deltas a diferença entre o resumo novo e o anterior.
+type Event struct {
+	ID                string    `json:"id"`
+	Type              string    `json:"tipo"`
+	Agency            string    `json:"orgao,omitempty"`
+	Year              int       `json:"ano,omitempty"`
+	Month             int       `json:"mes,omitempty"`
+	CrawlingTimestamp time.Time `json:"timestamp_coleta"`
+	TransparencyScore float64   `json:"indice_transparencia,omitempty"`
+	Summary           *Figures  `json:"resumo,omitempty"`
+	Previous          *Figures  `json:"resumo_anterior,omitempty"`
+	Deltas            *Figures  `json:"variacao,omitempty"`
+	Challenge         string    `json:"desafio,omitempty"` // só nos eventos de verificação
+}
+
+// NewEvent cria o evento de uma coleta. O id do evento identifica a coleta
+// (órgão/mês/ano e timestamp), então o assinante pode usá-lo para descartar
+// entregas repetidas.
+func NewEvent(c Change) Event {
+	e := Event{
+		ID:                fmt.Sprintf("%s/%02d/%d/%d", strings.ToLower(c.Agency), c.Month, c.Year, c.Timestamp.Unix()),
+		Type:              NewCollection,
+		Agency:            c.Agency,
+		Year:              c.Year,
+		Month:             c.Month,
+		CrawlingTimestamp: c.Timestamp,
+		TransparencyScore: c.Score,
+	}
+	current := newFigures(c.Summary)
+	e.Summary = &current
+	if c.Previous != nil {
+		previous := newFigures(c.Previous)
+		deltas := current.sub(previous)
+		e.Type = Recollection
+		e.Previous = &previous
+		e.Deltas = &deltas
+	}
+	return e
+}
+
+// Delivery é uma tentativa de entrega de um evento a uma assinatura.
+type Delivery struct {
+	SubscriptionID string    `json:"-"`
+	EventID        string    `json:"id_evento"`
+	EventType      string    `json:"tipo"`
+	Attempt        int       `json:"tentativa"`
+	StatusCode     int       `json:"status_http,omitempty"`
+	Error          string    `json:"erro,omitempty"` // categoria do erro: dns, tempo_esgotado, conexao_recusada, tls, endereco_bloqueado, requisicao_invalida ou erro_de_rede
+	Delivered      bool      `json:"entregue"`
+	Timestamp      time.Time `json:"timestamp"`
+	Duration       int64     `json:"duracao_ms"`
+}
+
+// Retry é uma entrega que falhou e será tentada de novo na primeira rodada a
+// partir de NextAttempt.
+type Retry struct {
+	SubscriptionID string
+	Event          Event
+	Attempt        int // número da próxima tentativa
+	NextAttempt    time.Time
+}
+
+// Store guarda as assinaturas, o registro das entregas, as entregas a serem
+// tentadas de novo e até que momento as coletas já foram avisadas.
+type Store interface {
+	AddSubscription(s Subscription) error
+	Subscription(id string) (Subscription, error)
+	Subscriptions() ([]Subscription, error)
+	// DeleteSubscription apaga a assinatura, com as suas entregas pendentes.
+	DeleteSubscription(id string) error
+	AddDelivery(d Delivery) error
+	// Deliveries retorna as últimas entregas da assinatura, da mais recente para a mais antiga.
+	Deliveries(subscriptionID string, limit int) ([]Delivery, error)
+	// SetRetry guarda uma entrega a ser tentada de novo, substituindo a pendente do mesmo evento.
+	SetRetry(r Retry) error
+	// DeleteRetry apaga a entrega pendente do evento, se houver.
+	DeleteRetry(subscriptionID, eventID string) error
+	// DueRetries retorna as entregas pendentes cuja próxima tentativa é até now.
+	DueRetries(now time.Time) ([]Retry, error)
+	// Cursor retorna o timestamp da última coleta avisada, ou zero se nenhuma rodada foi feita.
+	Cursor() (time.Time, error)
+	SetCursor(t time.Time) error
+}
+
+// Source lista as coletas com dados feitas depois de since, em ordem de timestamp.
+type Source interface {
+	Changes(since time.Time) ([]Change, error)
+}
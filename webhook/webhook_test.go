@@ -0,0 +1,347 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// stub é um assinante local que guarda os eventos recebidos e responde com os
+// status informados, em ordem (o último se repete). Os eventos de verificação
+// são respondidos com o desafio e não são guardados.
+type stub struct {
+	mu       sync.Mutex
+	statuses []int
+	events   []Event
+	headers  []http.Header
+	bodies   [][]byte
+}
+
+func (s *stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	var e Event
+	json.Unmarshal(body, &e)
+	if e.Type == Verification {
+		w.Write([]byte(e.Challenge))
+		return
+	}
+	s.events = append(s.events, e)
+	s.headers = append(s.headers, r.Header)
+	s.bodies = append(s.bodies, body)
+	status := s.statuses[0]
+	if len(s.statuses) > 1 {
+		s.statuses = s.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+type fakeSource []Change
+
+func (f fakeSource) Changes(since time.Time) ([]Change, error) {
+	var changes []Change
+	for _, c := range f {
+		if c.Timestamp.After(since) {
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+// newTestDispatcher cria um Dispatcher com um relógio controlado pelo teste.
+func newTestDispatcher(store Store, source Source) (*Dispatcher, *time.Time) {
+	d := NewDispatcher(store, source, nil)
+	d.AllowPrivateAddresses = true
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	d.Backoff = time.Second
+	d.MaxAttempts = 3
+	return d, &now
+}
+
+// retryStore cria um MemoryStore com a assinatura e o cursor já iniciado, para
+// que as rodadas façam as novas tentativas.
+func retryStore(t *testing.T, s Subscription) *MemoryStore {
+	store := NewMemoryStore()
+	assert.NoError(t, store.AddSubscription(s))
+	assert.NoError(t, store.SetCursor(time.Now().UTC()))
+	return store
+}
+
+func summary(count int, base, other float64) *models.Summary {
+	return &models.Summary{
+		Count:              count,
+		BaseRemuneration:   models.DataSummary{Total: base},
+		OtherRemunerations: models.DataSummary{Total: other},
+		Remunerations:      models.DataSummary{Total: base + other},
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"tjal/01/2023/1"}`)
+	sig := Sign("segredo", body)
+	assert.Equal(t, "sha256=", sig[:7])
+	assert.Len(t, sig, 7+64)
+	assert.True(t, Verify("segredo", body, sig))
+	assert.False(t, Verify("outro", body, sig))
+	assert.False(t, Verify("segredo", []byte(`{}`), sig))
+}
+
+func TestNewEvent(t *testing.T) {
+	ts := time.Date(2023, 2, 10, 12, 0, 0, 0, time.UTC)
+	c := Change{Agency: "tjal", Year: 2023, Month: 1, Timestamp: ts, Score: 0.5, Summary: summary(10, 1000, 200)}
+	e := NewEvent(c)
+	assert.Equal(t, "tjal/01/2023/1676030400", e.ID)
+	assert.Equal(t, NewCollection, e.Type)
+	assert.Equal(t, Figures{MemberCount: 10, BaseRemuneration: 1000, OtherRemunerations: 200, Remunerations: 1200}, *e.Summary)
+	assert.Nil(t, e.Previous)
+	assert.Nil(t, e.Deltas)
+
+	c.Previous = summary(8, 900, 250)
+	e = NewEvent(c)
+	assert.Equal(t, Recollection, e.Type)
+	assert.Equal(t, 8, e.Previous.MemberCount)
+	assert.Equal(t, Figures{MemberCount: 2, BaseRemuneration: 100, OtherRemunerations: -50, Remunerations: 50}, *e.Deltas)
+}
+
+func TestMatches(t *testing.T) {
+	c := Change{Agency: "tjal", Jurisdiction: "Estadual"}
+	assert.True(t, Subscription{}.Matches(c))
+	assert.True(t, Subscription{Agency: "TJAL"}.Matches(c))
+	assert.False(t, Subscription{Agency: "mpal"}.Matches(c))
+	assert.True(t, Subscription{Group: "justica-estadual"}.Matches(c))
+	assert.False(t, Subscription{Group: "ministerios-publicos"}.Matches(c))
+	assert.False(t, Subscription{Group: "inexistente"}.Matches(c))
+}
+
+func TestDeliver(t *testing.T) {
+	t.Run("Tenta de novo com espera crescente nas rodadas seguintes", func(t *testing.T) {
+		s := &stub{statuses: []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK}}
+		srv := httptest.NewServer(s)
+		defer srv.Close()
+		sub := Subscription{ID: "a", URL: srv.URL, Secret: "segredo"}
+		store := retryStore(t, sub)
+		d, now := newTestDispatcher(store, fakeSource{})
+
+		e := NewEvent(Change{Agency: "tjal", Year: 2023, Month: 1, Summary: summary(10, 1000, 0)})
+		assert.Error(t, d.Deliver(sub, e))
+		retries, _ := store.DueRetries(now.Add(time.Second))
+		assert.Equal(t, []Retry{{SubscriptionID: "a", Event: e, Attempt: 2, NextAttempt: now.Add(time.Second)}}, retries)
+
+		// A nova tentativa só é feita depois da espera.
+		assert.NoError(t, d.Run())
+		assert.Len(t, s.events, 1)
+		*now = now.Add(time.Second)
+		assert.NoError(t, d.Run())
+		assert.Len(t, s.events, 2)
+		*now = now.Add(time.Second)
+		assert.NoError(t, d.Run())
+		assert.Len(t, s.events, 2)
+		*now = now.Add(time.Second)
+		assert.NoError(t, d.Run())
+		assert.Len(t, s.events, 3)
+		retries, _ = store.DueRetries(now.Add(time.Hour))
+		assert.Empty(t, retries)
+		assert.Equal(t, e.ID, s.events[2].ID)
+		assert.Equal(t, NewCollection, s.headers[2].Get(EventHeader))
+		assert.Equal(t, e.ID, s.headers[2].Get(DeliveryHeader))
+		assert.True(t, Verify("segredo", s.bodies[2], s.headers[2].Get(SignatureHeader)))
+
+		deliveries, _ := store.Deliveries("a", 10)
+		assert.Len(t, deliveries, 3)
+		assert.Equal(t, 3, deliveries[0].Attempt)
+		assert.True(t, deliveries[0].Delivered)
+		assert.Equal(t, http.StatusInternalServerError, deliveries[2].StatusCode)
+		assert.False(t, deliveries[2].Delivered)
+	})
+	t.Run("Desiste após o número máximo de tentativas", func(t *testing.T) {
+		s := &stub{statuses: []int{http.StatusBadGateway}}
+		srv := httptest.NewServer(s)
+		defer srv.Close()
+		sub := Subscription{ID: "a", URL: srv.URL}
+		store := retryStore(t, sub)
+		d, now := newTestDispatcher(store, fakeSource{})
+
+		assert.Error(t, d.Deliver(sub, Event{ID: "x"}))
+		for i := 0; i < 5; i++ {
+			*now = now.Add(time.Hour)
+			assert.NoError(t, d.Run())
+		}
+		deliveries, _ := store.Deliveries("a", 10)
+		assert.Len(t, deliveries, 3)
+		retries, _ := store.DueRetries(now.Add(time.Hour))
+		assert.Empty(t, retries)
+	})
+	t.Run("Apaga as entregas pendentes da assinatura apagada", func(t *testing.T) {
+		s := &stub{statuses: []int{http.StatusBadGateway}}
+		srv := httptest.NewServer(s)
+		defer srv.Close()
+		sub := Subscription{ID: "a", URL: srv.URL}
+		store := retryStore(t, sub)
+		d, now := newTestDispatcher(store, fakeSource{})
+
+		assert.Error(t, d.Deliver(sub, Event{ID: "x"}))
+		assert.NoError(t, d.Unsubscribe("a"))
+		retries, _ := store.DueRetries(now.Add(time.Hour))
+		assert.Empty(t, retries)
+	})
+	t.Run("Não tenta de novo se o assinante recusar o evento", func(t *testing.T) {
+		s := &stub{statuses: []int{http.StatusGone}}
+		srv := httptest.NewServer(s)
+		defer srv.Close()
+		store := NewMemoryStore()
+		d, now := newTestDispatcher(store, nil)
+		sub := Subscription{ID: "a", URL: srv.URL}
+
+		assert.Error(t, d.Deliver(sub, Event{ID: "x"}))
+		deliveries, _ := store.Deliveries("a", 10)
+		assert.Len(t, deliveries, 1)
+		retries, _ := store.DueRetries(now.Add(time.Hour))
+		assert.Empty(t, retries)
+	})
+	t.Run("Erro de rede", func(t *testing.T) {
+		srv := httptest.NewServer(&stub{statuses: []int{http.StatusOK}})
+		srv.Close()
+		store := NewMemoryStore()
+		d, now := newTestDispatcher(store, nil)
+
+		assert.Error(t, d.Deliver(Subscription{ID: "a", URL: srv.URL}, Event{ID: "x"}))
+		deliveries, _ := store.Deliveries("a", 10)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, 0, deliveries[0].StatusCode)
+		assert.Equal(t, errorConnRefused, deliveries[0].Error)
+		retries, _ := store.DueRetries(now.Add(time.Second))
+		assert.Len(t, retries, 1)
+	})
+	t.Run("Não segue redirecionamentos", func(t *testing.T) {
+		target := &stub{statuses: []int{http.StatusOK}}
+		targetSrv := httptest.NewServer(target)
+		defer targetSrv.Close()
+		srv := httptest.NewServer(http.RedirectHandler(targetSrv.URL, http.StatusTemporaryRedirect))
+		defer srv.Close()
+		store := NewMemoryStore()
+		d, _ := newTestDispatcher(store, nil)
+
+		assert.Error(t, d.Deliver(Subscription{ID: "a", URL: srv.URL}, Event{ID: "x"}))
+		assert.Empty(t, target.events)
+		deliveries, _ := store.Deliveries("a", 10)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, http.StatusTemporaryRedirect, deliveries[0].StatusCode)
+	})
+	t.Run("Recusa endereços internos na conexão", func(t *testing.T) {
+		s := &stub{statuses: []int{http.StatusOK}}
+		srv := httptest.NewServer(s)
+		defer srv.Close()
+		store := NewMemoryStore()
+		d, _ := newTestDispatcher(store, nil)
+		d.AllowPrivateAddresses = false
+
+		assert.Error(t, d.Deliver(Subscription{ID: "a", URL: srv.URL}, Event{ID: "x"}))
+		assert.Empty(t, s.events)
+		deliveries, _ := store.Deliveries("a", 10)
+		assert.Equal(t, errorForbiddenAddress, deliveries[0].Error)
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Run("Recusa endereços internos", func(t *testing.T) {
+		d := NewDispatcher(NewMemoryStore(), nil, nil)
+		for _, u := range []string{"http://127.0.0.1:8080/hook", "http://10.0.0.1/hook", "http://169.254.169.254/latest", "http://[::1]/hook", "http://localhost/hook", "ftp://example.com"} {
+			_, err := d.Subscribe(u, "", "")
+			assert.ErrorIs(t, err, ErrInvalidURL, u)
+		}
+	})
+	t.Run("Exige a resposta ao desafio", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+		store := NewMemoryStore()
+		d, _ := newTestDispatcher(store, nil)
+
+		_, err := d.Subscribe(srv.URL, "", "")
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+		subscriptions, _ := store.Subscriptions()
+		assert.Empty(t, subscriptions)
+	})
+}
+
+func TestPublicAddress(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"200.160.2.3":     true,
+		"2001:4860::8888": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.0.1":     false,
+		"169.254.169.254": false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+	} {
+		assert.Equal(t, public, publicAddress(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestRun(t *testing.T) {
+	base := time.Now().UTC()
+	source := fakeSource{
+		{Agency: "tjal", Jurisdiction: "Estadual", Year: 2023, Month: 1, Timestamp: base.Add(time.Minute), Summary: summary(10, 1000, 0)},
+		{Agency: "mpal", Jurisdiction: "Ministério", Year: 2023, Month: 1, Timestamp: base.Add(2 * time.Minute), Summary: summary(5, 500, 0), Previous: summary(4, 400, 0)},
+	}
+	all := &stub{statuses: []int{http.StatusOK}}
+	allSrv := httptest.NewServer(all)
+	defer allSrv.Close()
+	mp := &stub{statuses: []int{http.StatusOK}}
+	mpSrv := httptest.NewServer(mp)
+	defer mpSrv.Close()
+
+	store := NewMemoryStore()
+	d, _ := newTestDispatcher(store, source)
+	subAll, err := d.Subscribe(allSrv.URL, "", "")
+	assert.NoError(t, err)
+	assert.Len(t, subAll.ID, 32)
+	assert.Len(t, subAll.Secret, 64)
+	_, err = d.Subscribe(mpSrv.URL, "", "ministerios-publicos")
+	assert.NoError(t, err)
+
+	// A primeira rodada só inicia o cursor.
+	assert.NoError(t, store.SetCursor(time.Time{}))
+	assert.NoError(t, d.Run())
+	assert.Empty(t, all.events)
+	cursor, _ := store.Cursor()
+	assert.False(t, cursor.IsZero())
+
+	assert.NoError(t, store.SetCursor(base))
+	assert.NoError(t, d.Run())
+	assert.Len(t, all.events, 2)
+	assert.Equal(t, "tjal", all.events[0].Agency)
+	assert.True(t, Verify(subAll.Secret, all.bodies[0], all.headers[0].Get(SignatureHeader)))
+	assert.Len(t, mp.events, 1)
+	assert.Equal(t, Recollection, mp.events[0].Type)
+	assert.Equal(t, 1, mp.events[0].Deltas.MemberCount)
+	cursor, _ = store.Cursor()
+	assert.Equal(t, base.Add(2*time.Minute), cursor)
+
+	// Nada de novo: nenhuma entrega.
+	assert.NoError(t, d.Run())
+	assert.Len(t, all.events, 2)
+
+	// O segredo só é retornado na criação.
+	got, err := d.Subscription(subAll.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, got.Secret)
+	assert.NoError(t, d.Unsubscribe(subAll.ID))
+	_, err = d.Subscription(subAll.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}